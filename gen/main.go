@@ -0,0 +1,345 @@
+/*
+Command gen generates treap with implicit keys specialized for a single value type.
+Generated code has no type parameters, builtin min and max or packages newer than Go 1.17,
+so it can be used with old Go versions, and works the same way as `Generic` treap of the treap package.
+
+Usage:
+
+	//go:generate go run main/gen -type=Point -package=geometry
+
+Flags:
+
+	-type     value type, required
+	-name     name of the generated treap type, default is the value type with "Treap" suffix
+	-package  package of the generated file, default is "main"
+	-import   import path of the package containing value type, if it is not the generated package
+	-output   generated file, default is lowercase name with ".go" suffix
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var (
+	valueType = flag.String("type", "", "value type, required")
+	name      = flag.String("name", "", "name of the generated treap type")
+	pkg       = flag.String("package", "main", "package of the generated file")
+	imp       = flag.String("import", "", "import path of the package containing value type")
+	output    = flag.String("output", "", "generated file")
+)
+
+func main() {
+	flag.Parse()
+	if *valueType == "" {
+		fmt.Fprintln(os.Stderr, "gen: -type flag is required")
+		os.Exit(2)
+	}
+	if *name == "" {
+		base := (*valueType)[strings.LastIndex(*valueType, ".")+1:]
+		*name = upper(strings.TrimLeft(base, "*[]")) + "Treap"
+	}
+	if *output == "" {
+		*output = strings.ToLower(*name) + ".go"
+	}
+	code, err := generate(*valueType, *name, *pkg, *imp)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, code, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+// Returns the word with the 1st letter in upper case.
+func upper(word string) string {
+	if word == "" {
+		return word
+	}
+	r := []rune(word)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// Returns formatted source code of the specialized treap.
+func generate(valueType string, name string, pkg string, imp string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := source.Execute(&buf, map[string]string{
+		"Type":    valueType,
+		"Name":    name,
+		"Prefix":  strings.ToLower(name[:1]) + name[1:],
+		"Package": pkg,
+		"Import":  imp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var source = template.Must(template.New("treap").Parse(`// Code generated by main/gen -type={{.Type}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"math/rand"
+{{- if .Import}}
+	"{{.Import}}"
+{{- end}}
+)
+
+/*
+Node of the {{.Name}}.
+*/
+type {{.Prefix}}Node struct {
+	value    {{.Type}}
+	size     int
+	priority int
+	tiebreak uint64
+	lson     *{{.Prefix}}Node
+	rson     *{{.Prefix}}Node
+}
+
+func new{{.Name}}Node(value {{.Type}}) *{{.Prefix}}Node {
+	return &{{.Prefix}}Node{value: value, size: 1, priority: rand.Int(), tiebreak: rand.Uint64()}
+}
+
+func {{.Prefix}}Size(n *{{.Prefix}}Node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func {{.Prefix}}Sync(n *{{.Prefix}}Node) {
+	n.size = 1 + {{.Prefix}}Size(n.lson) + {{.Prefix}}Size(n.rson)
+}
+
+func {{.Prefix}}Merge(n1 *{{.Prefix}}Node, n2 *{{.Prefix}}Node) *{{.Prefix}}Node {
+	if n1 == nil {
+		return n2
+	} else if n2 == nil {
+		return n1
+	}
+	if n1.priority > n2.priority || (n1.priority == n2.priority && n1.tiebreak > n2.tiebreak) {
+		n1.rson = {{.Prefix}}Merge(n1.rson, n2)
+		{{.Prefix}}Sync(n1)
+		return n1
+	}
+	n2.lson = {{.Prefix}}Merge(n1, n2.lson)
+	{{.Prefix}}Sync(n2)
+	return n2
+}
+
+func {{.Prefix}}Split(n *{{.Prefix}}Node, index int) (l *{{.Prefix}}Node, r *{{.Prefix}}Node) {
+	if n == nil {
+		return nil, nil
+	} else if index < 0 {
+		return nil, n
+	} else if index >= n.size {
+		return n, nil
+	}
+	lsize := {{.Prefix}}Size(n.lson)
+	if index < lsize {
+		l, n.lson = {{.Prefix}}Split(n.lson, index)
+		{{.Prefix}}Sync(n)
+		return l, n
+	}
+	n.rson, r = {{.Prefix}}Split(n.rson, index-lsize-1)
+	{{.Prefix}}Sync(n)
+	return n, r
+}
+
+func {{.Prefix}}Build(values []{{.Type}}, reversed bool) *{{.Prefix}}Node {
+	spine := make([]*{{.Prefix}}Node, 0, 64)
+	for i := range values {
+		if reversed {
+			i = len(values) - 1 - i
+		}
+		n := new{{.Name}}Node(values[i])
+		var last *{{.Prefix}}Node
+		for len(spine) > 0 && (n.priority > spine[len(spine)-1].priority ||
+			(n.priority == spine[len(spine)-1].priority && n.tiebreak > spine[len(spine)-1].tiebreak)) {
+			last = spine[len(spine)-1]
+			spine = spine[:len(spine)-1]
+			{{.Prefix}}Sync(last)
+		}
+		n.lson = last
+		if len(spine) > 0 {
+			spine[len(spine)-1].rson = n
+		}
+		spine = append(spine, n)
+	}
+	for i := len(spine) - 1; i >= 0; i-- {
+		{{.Prefix}}Sync(spine[i])
+	}
+	if len(spine) == 0 {
+		return nil
+	}
+	return spine[0]
+}
+
+func {{.Prefix}}Export(values []{{.Type}}, position int, n *{{.Prefix}}Node) {
+	for n != nil {
+		lsize := {{.Prefix}}Size(n.lson)
+		{{.Prefix}}Export(values, position, n.lson)
+		values[position+lsize] = n.value
+		position += lsize + 1
+		n = n.rson
+	}
+}
+
+/*
+Treap with implicit keys storing values of {{.Type}} type.
+Has the same methods as the generic treap of the treap package.
+*/
+type {{.Name}} struct {
+	root *{{.Prefix}}Node
+}
+
+/*
+Correctly initialize a treap.
+Insert all given values to the back.
+*/
+func New{{.Name}}(values ...{{.Type}}) {{.Name}} {
+	t := {{.Name}}{}
+	t.PushBack(values...)
+	return t
+}
+
+/*
+Merges 2 treaps. Old treaps are left empty.
+*/
+func Merge{{.Name}}(t1 *{{.Name}}, t2 *{{.Name}}) {{.Name}} {
+	var t {{.Name}}
+	if t1 != nil {
+		t.root, t1.root = t1.root, nil
+	}
+	if t2 != nil {
+		t.root, t2.root = {{.Prefix}}Merge(t.root, t2.root), nil
+	}
+	return t
+}
+
+/*
+Split treap by provided index, left treap gets all elements up to the index.
+Old treap is left empty.
+*/
+func Split{{.Name}}(t *{{.Name}}, index int) (tl {{.Name}}, tr {{.Name}}) {
+	if t != nil {
+		tl.root, tr.root = {{.Prefix}}Split(t.root, index)
+		t.root = nil
+	}
+	return
+}
+
+/*
+Insert value into provided index.
+
+	if index <= 0: insert to the front
+	if index >= size: insert to the back
+*/
+func (t *{{.Name}}) Insert(index int, value {{.Type}}) {
+	if index <= 0 {
+		t.root = {{.Prefix}}Merge(new{{.Name}}Node(value), t.root)
+		return
+	} else if index >= {{.Prefix}}Size(t.root) {
+		t.root = {{.Prefix}}Merge(t.root, new{{.Name}}Node(value))
+		return
+	}
+	l, r := {{.Prefix}}Split(t.root, index-1)
+	t.root = {{.Prefix}}Merge({{.Prefix}}Merge(l, new{{.Name}}Node(value)), r)
+}
+
+/*
+Insert all provided values to the front, the last provided value becomes the first element.
+*/
+func (t *{{.Name}}) PushFront(values ...{{.Type}}) {
+	t.root = {{.Prefix}}Merge({{.Prefix}}Build(values, true), t.root)
+}
+
+/*
+Insert all provided values to the back.
+*/
+func (t *{{.Name}}) PushBack(values ...{{.Type}}) {
+	t.root = {{.Prefix}}Merge(t.root, {{.Prefix}}Build(values, false))
+}
+
+/*
+Delete all elements in the given range, range is clamped to the existing elements.
+*/
+func (t *{{.Name}}) Cut(index_left int, index_right int) {
+	if t.root == nil || index_left > index_right || index_right < 0 || index_left >= t.root.size {
+		return
+	}
+	if index_left < 0 {
+		index_left = 0
+	}
+	if index_right >= t.root.size {
+		index_right = t.root.size - 1
+	}
+	l, k := {{.Prefix}}Split(t.root, index_left-1)
+	_, r := {{.Prefix}}Split(k, index_right-index_left)
+	t.root = {{.Prefix}}Merge(l, r)
+}
+
+/*
+Delete 1 element by provided index.
+
+	if index out of range: do nothing
+*/
+func (t *{{.Name}}) Delete(index int) {
+	if index < 0 || index >= {{.Prefix}}Size(t.root) {
+		return
+	}
+	t.Cut(index, index)
+}
+
+/*
+Returns amount of elements.
+*/
+func (t *{{.Name}}) Size() int {
+	return {{.Prefix}}Size(t.root)
+}
+
+/*
+Return the element on the given index.
+
+	if index out of range: return zero value
+*/
+func (t *{{.Name}}) Find(index int) (value {{.Type}}) {
+	for n := t.root; n != nil; {
+		lsize := {{.Prefix}}Size(n.lson)
+		if index < lsize {
+			n = n.lson
+		} else if index > lsize {
+			index -= lsize + 1
+			n = n.rson
+		} else {
+			return n.value
+		}
+	}
+	return
+}
+
+/*
+Returns all values as a slice.
+*/
+func (t *{{.Name}}) Export() []{{.Type}} {
+	if t.root == nil {
+		return nil
+	}
+	values := make([]{{.Type}}, t.root.size)
+	{{.Prefix}}Export(values, 0, t.root)
+	return values
+}
+`))
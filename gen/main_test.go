@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Program using the generated treap, it prints values left after the edits.
+const program = `package main
+
+import "fmt"
+
+type Point struct {
+	X, Y int
+}
+
+func main() {
+	t := NewPointTreap(Point{1, 1}, Point{2, 2})
+	t.Insert(1, Point{3, 3})
+	t.Insert(10, Point{4, 4})
+	t.PushFront(Point{5, 5})
+	t.Cut(-1, 0)
+	t.Delete(t.Size() - 1)
+	fmt.Println(t.Export(), t.Find(1), t.Find(-1))
+}
+`
+
+func TestGenerateBuildsWithOldGo(t *testing.T) {
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command is not available")
+	}
+	code, err := generate("Point", "PointTreap", "main", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":        "module old\n\ngo 1.17\n",
+		"pointtreap.go": string(code),
+		"main.go":       program,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Vet also reports usage of the standard library newer than the go directive.
+	for _, args := range [][]string{{"vet", "."}, {"run", "."}} {
+		cmd := exec.Command(gobin, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local", "GOFLAGS=")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		if args[0] == "run" {
+			if got, want := strings.TrimSpace(string(out)), "[{1 1} {3 3} {2 2}] {3 3} {0 0}"; got != want {
+				t.Errorf("generated treap printed %q, want %q", got, want)
+			}
+		}
+	}
+}
+
+func TestGenerateWithImport(t *testing.T) {
+	code, err := generate("geo.Point", "PointTreap", "shapes", "example.com/geo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package shapes", `"example.com/geo"`, "value    geo.Point", "func NewPointTreap(values ...geo.Point) PointTreap"} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("generated code does not contain %q", want)
+		}
+	}
+}
@@ -1,17 +1,51 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"main/treap"
 	"math/rand/v2"
+	"os"
+	"runtime"
 	"time"
 )
 
 const tests_amount = 100_000_000
 
+var (
+	record = flag.String("record", "", "write operation trace of the treap testing into the file")
+	replay = flag.String("replay", "", "only replay operation trace from the file and measure it")
+)
+
 func main() {
+	flag.Parse()
 	var timestamp time.Time
 
+	//* TRACE REPLAY TESTING
+	if *replay != "" {
+		file, err := os.Open(*replay)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		log, err := treap.ReadLog(file)
+		file.Close()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		r := treap.New()
+		timestamp = time.Now()
+
+		if err := treap.Replay(log, &r); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Println(time.Since(timestamp).Seconds())
+		return
+	}
+
 	values := make([]int, tests_amount)
 	indexes := make([]int, tests_amount)
 	for i := 0; i < tests_amount; i++ {
@@ -26,7 +60,7 @@ func main() {
 	for i := 0; i < tests_amount; i++ {
 		index := indexes[i]
 		value := values[i]
-		if len(s)-1 >= index {
+		if index >= len(s) {
 			s = append(s, value)
 		} else if index <= 0 {
 			s = append([]int{value}, s...)
@@ -39,6 +73,10 @@ func main() {
 
 	//* TREAP TESTING
 	t := treap.New()
+	var trace *treap.Log
+	if *record != "" {
+		trace = t.Record()
+	}
 	timestamp = time.Now()
 
 	for i := 0; i < tests_amount; i++ {
@@ -47,12 +85,71 @@ func main() {
 
 	fmt.Println(time.Since(timestamp).Seconds())
 
+	if trace != nil {
+		trace.Stop()
+		if err := writeTrace(*record, trace); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	//* FIND ALLOCATIONS TESTING
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	for i := 0; i < tests_amount; i++ {
+		t.Find(indexes[i])
+	}
+
+	runtime.ReadMemStats(&after)
+	fmt.Println(after.Mallocs - before.Mallocs)
+
+	//* GENERIC TREAP TESTING
+	g := treap.NewGeneric[int]()
+	timestamp = time.Now()
+
+	for i := 0; i < tests_amount; i++ {
+		g.Insert(indexes[i], values[i])
+	}
+
+	fmt.Println(time.Since(timestamp).Seconds())
+
+	//* BULK CONSTRUCTION TESTING
+	timestamp = time.Now()
+
+	b := treap.New()
+	for i := 0; i < tests_amount; i++ {
+		b.PushBack(values[i])
+	}
+
+	fmt.Println(time.Since(timestamp).Seconds())
+
+	timestamp = time.Now()
+
+	b = treap.New(values[:tests_amount]...)
+
+	fmt.Println(time.Since(timestamp).Seconds())
+
 	//* COMPARE RESULTS
 	e := t.Export()
+	ge := g.Export()
 	for i := 0; i < tests_amount; i++ {
-		if e[i] != s[i] {
+		if e[i] != s[i] || ge[i] != s[i] {
 			fmt.Println("Bad")
 		}
 	}
 	fmt.Println("Good")
 }
+
+// Writes recorded operation trace into the file, so it can be replayed by -replay flag.
+func writeTrace(path string, trace *treap.Log) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := trace.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,239 @@
+/*
+Package rope provides line-based editing of text files on top of the generic treap.
+Every line is stored as a separate element, so lines can be inserted, deleted and replaced
+in a logarithmic time without rewriting the whole text.
+
+# Package is unsafe to be used in parallel goroutines.
+*/
+package rope
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"main/treap"
+)
+
+/*
+Text stored as a sequence of lines.
+Lines do not contain line terminators.
+*/
+type Rope struct {
+	lines treap.Generic[string]
+	// Reports whether the text ends with a line terminator.
+	newline bool
+}
+
+/*
+Correctly initialize a rope with provided lines.
+Text ends with a line terminator.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided lines;
+*/
+func New(lines ...string) *Rope {
+	return &Rope{lines: treap.NewGeneric(lines...), newline: true}
+}
+
+/*
+Reads the whole text line by line.
+Lines can be terminated by "\n" or "\r\n", terminators are not stored.
+Writing the rope back produces "\n" terminators only.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the text;
+*/
+func Read(r io.Reader) (*Rope, error) {
+	rp := &Rope{}
+	br := bufio.NewReader(r)
+	batch := make([]string, 0, 1024)
+	for {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			rp.newline = strings.HasSuffix(line, "\n")
+			line = strings.TrimSuffix(line, "\n")
+			line = strings.TrimSuffix(line, "\r")
+			batch = append(batch, line)
+		}
+		if len(batch) == cap(batch) || (err != nil && len(batch) > 0) {
+			rp.lines.PushBack(batch...)
+			batch = batch[:0]
+		}
+		if err == io.EOF {
+			return rp, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
+
+/*
+Opens and reads the file by `Read()`.
+File is closed afterwards, use `SaveAtomic()` to write the changes.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the file;
+*/
+func OpenFile(path string) (*Rope, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+/*
+Writes the text, every line except the last is terminated by "\n".
+The last line is terminated only if the read text had the terminator.
+Implements io.WriterTo interface.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the text;
+*/
+func (r *Rope) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	written := int64(0)
+	lines := r.lines.Export()
+	for i, line := range lines {
+		n, err := bw.WriteString(line)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		if i+1 < len(lines) || r.newline {
+			if err := bw.WriteByte('\n'); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+	return written, bw.Flush()
+}
+
+/*
+Writes the text into the file atomically.
+Text is written into a temporary file in the same directory,
+which is synced and renamed over the target file,
+so the target file always contains either old or new text.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the text;
+*/
+func (r *Rope) SaveAtomic(path string) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if info, statErr := os.Stat(path); statErr == nil {
+		if err = tmp.Chmod(info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if _, err = r.WriteTo(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+/*
+Returns amount of lines.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (r *Rope) Lines() int {
+	return r.lines.Size()
+}
+
+/*
+Returns the line with the given index.
+
+	if index out of range: return ""
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (r *Rope) Line(index int) string {
+	return r.lines.Find(index)
+}
+
+/*
+Inserts lines, so the 1st of them gets the given index.
+
+	if index <= 0: insert before the 1st line
+	if index >= amount of lines: insert after the last line
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided lines plus height of the treap;
+*/
+func (r *Rope) InsertLines(index int, lines ...string) {
+	index = max(0, min(index, r.lines.Size()))
+	left, right := treap.SplitGeneric(&r.lines, index-1)
+	left.PushBack(lines...)
+	r.lines = treap.MergeGeneric(&left, &right)
+}
+
+/*
+Appends lines after the last line.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided lines plus height of the treap;
+*/
+func (r *Rope) AppendLines(lines ...string) {
+	r.lines.PushBack(lines...)
+}
+
+/*
+Deletes all lines from index_left to index_right.
+Range is clamped to the existing lines.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (r *Rope) DeleteLines(index_left int, index_right int) {
+	r.lines.Cut(index_left, index_right)
+}
+
+/*
+Replaces text of the line with the given index.
+
+	if index out of range: do nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (r *Rope) ReplaceLine(index int, line string) {
+	if index < 0 || index >= r.lines.Size() {
+		return
+	}
+	r.lines.Delete(index)
+	r.InsertLines(index, line)
+}
+
+/*
+Returns the whole text as a string, same as written by `WriteTo()`.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the text;
+*/
+func (r *Rope) String() string {
+	var sb strings.Builder
+	r.WriteTo(&sb)
+	return sb.String()
+}
@@ -0,0 +1,73 @@
+package rope
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	for _, text := range []string{"", "one", "one\n", "one\r\ntwo\n", "one\n\nthree"} {
+		r, err := Read(strings.NewReader(text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := strings.ReplaceAll(text, "\r\n", "\n")
+		if got := r.String(); got != want {
+			t.Errorf("Read(%q).String() = %q, want %q", text, got, want)
+		}
+	}
+	r, _ := Read(strings.NewReader(strings.Repeat("line\n", 3000)))
+	if r.Lines() != 3000 || r.Line(2999) != "line" {
+		t.Errorf("Read() of 3000 lines has %d lines", r.Lines())
+	}
+}
+
+func TestEdit(t *testing.T) {
+	r := New("a", "b", "c")
+	r.InsertLines(1, "x", "y")
+	r.InsertLines(-5, "first")
+	r.AppendLines("last")
+	r.ReplaceLine(1, "A")
+	r.ReplaceLine(100, "ignored")
+	r.DeleteLines(3, 4)
+	if got, want := r.String(), "first\nA\nx\nc\nlast\n"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if r.Lines() != 5 || r.Line(5) != "" {
+		t.Errorf("Lines() = %d, Line(5) = %q", r.Lines(), r.Line(5))
+	}
+}
+
+func TestSaveAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "text.txt")
+	if err := os.WriteFile(path, []byte("one\r\ntwo\nthree"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.InsertLines(1, "x")
+	if err := r.SaveAtomic(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "one\nx\ntwo\nthree"; got != want {
+		t.Errorf("saved text = %q, want %q", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+		t.Errorf("saved file mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o640))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory has %d files after save, want 1", len(entries))
+	}
+	if _, err := OpenFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("OpenFile() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,145 @@
+package treap
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+/*
+Counters of the allocator.
+Shared by all treaps produced from the same treap by `Split()` and `Merge()`,
+so counters are atomic.
+*/
+type stats struct {
+	allocatedNodes atomic.Int64
+	reusedNodes    atomic.Int64
+	freedNodes     atomic.Int64
+}
+
+/*
+Counts nodes allocated from the heap.
+
+	if s == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *stats) allocated(count int) {
+	if s != nil {
+		s.allocatedNodes.Add(int64(count))
+	}
+}
+
+/*
+Counts nodes taken from the free list.
+
+	if s == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *stats) reused(count int) {
+	if s != nil {
+		s.reusedNodes.Add(int64(count))
+	}
+}
+
+/*
+Counts nodes deleted from the treap.
+
+	if s == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *stats) freed(count int) {
+	if s != nil {
+		s.freedNodes.Add(int64(count))
+	}
+}
+
+/*
+Size of a single node in bytes.
+*/
+const nodeBytes = int64(unsafe.Sizeof(node{}))
+
+/*
+Size of augmentations of a single node in bytes, see `SetAugmented()`.
+*/
+const augmentBytes = int64(unsafe.Sizeof(augment{}))
+
+/*
+Memory usage report of the treap, see `Stats()`.
+*/
+type Stats struct {
+	// Nodes allocated from the heap since accounting was enabled.
+	NodesAllocated int64
+	// Nodes taken from the free list since accounting was enabled.
+	NodesReused int64
+	// Nodes deleted from the treap since accounting was enabled.
+	NodesFreed int64
+	// Nodes currently stored in the treap.
+	NodesLive int
+	// Nodes currently kept in the free list.
+	NodesFree int
+	// Bytes held by the free list.
+	ArenaBytes int64
+	// Estimated bytes the garbage collector has to scan for the treap:
+	// every live and free node consists of pointers and numbers, so all of them are scanned,
+	// together with augmentations of live nodes if the treap is augmented.
+	ScanBytes int64
+}
+
+/*
+Enables or disables accounting of the treap's memory.
+While enabled, allocated, reused and freed nodes are counted and reported by `Stats()`.
+Enabling accounting again resets the counters.
+
+Treaps produced by `Split()` and `Merge()` share counters of the old treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SetAccounting(on bool) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	p := t.allocator()
+	if on {
+		p.stats = &stats{}
+	} else {
+		p.stats = nil
+	}
+	t.trimAllocator()
+}
+
+/*
+Returns memory usage report of the treap.
+Counters are zero if accounting is disabled,
+but live nodes, free nodes and estimated bytes are always reported.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Stats() Stats {
+	if t == nil {
+		nilReceiver()
+		return Stats{}
+	}
+	s := Stats{NodesLive: t.Size()}
+	if p := t.opts.pool; p != nil {
+		s.NodesFree = p.size
+		if p.stats != nil {
+			s.NodesAllocated = p.stats.allocatedNodes.Load()
+			s.NodesReused = p.stats.reusedNodes.Load()
+			s.NodesFreed = p.stats.freedNodes.Load()
+		}
+	}
+	s.ArenaBytes = int64(s.NodesFree) * nodeBytes
+	s.ScanBytes = int64(s.NodesLive+s.NodesFree) * nodeBytes
+	if augmented(t.root) {
+		s.ScanBytes += int64(s.NodesLive) * augmentBytes
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package treap
+
+import "testing"
+
+func TestAccounting(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.SetAccounting(true)
+	tr.SetRecycling(true)
+	for i := range 10 {
+		tr.Insert(i, i)
+	}
+	tr.Cut(0, 3)
+	tr.Delete(0)
+	tr.Insert(0, 7)
+	want := Stats{
+		NodesAllocated: 10,
+		NodesReused:    1,
+		NodesFreed:     5,
+		NodesLive:      9,
+		NodesFree:      4,
+		ArenaBytes:     4 * nodeBytes,
+		ScanBytes:      13 * nodeBytes,
+	}
+	if got := tr.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	tr.SetAugmented(true)
+	if got := tr.Stats().ScanBytes; got != 13*nodeBytes+9*augmentBytes {
+		t.Errorf("ScanBytes of augmented treap = %d, want %d", got, 13*nodeBytes+9*augmentBytes)
+	}
+}
+
+func TestAccountingIsShared(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	tr.SetAccounting(true)
+	l, r := Split(&tr, 1)
+	l.Insert(0, 0)
+	r.Insert(0, 0)
+	if got := l.Stats().NodesAllocated; got != 2 {
+		t.Errorf("NodesAllocated of split treaps = %d, want 2", got)
+	}
+	r.SetAccounting(true)
+	if got := r.Stats().NodesAllocated; got != 0 {
+		t.Errorf("NodesAllocated after enabling accounting again = %d, want 0", got)
+	}
+	r.SetAccounting(false)
+	r.Insert(0, 0)
+	if got := r.Stats(); got.NodesAllocated != 0 || got.NodesLive != 4 {
+		t.Errorf("Stats() without accounting = %+v", got)
+	}
+}
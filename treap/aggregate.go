@@ -0,0 +1,119 @@
+package treap
+
+/*
+Correctly initialize a treap with user defined aggregation, same as `New()`.
+Combine must be associative: combine(combine(a, b), c) == combine(a, combine(b, c)),
+for example sum, minimum, greatest common divisor or a fold of the values packed into int.
+It is not required to be commutative, elements are always combined in order.
+Configuration is copied by `Merge()` and `Split()`,
+merged treaps must use the same aggregation, otherwise results of the 2nd treap are mixed in.
+
+	if combine == nil: same as `New()`
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func NewAggregated(combine func(int, int) int, values ...int) Treap {
+	t := Treap{}
+	t.opts.combine = combine
+	t.PushBack(values...)
+	return t
+}
+
+/*
+Returns aggregation of the elements from index_left to index_right, see `NewAggregated()`.
+Each node caches result of its subtree, that is recalculated only after the subtree is changed,
+so repeated queries walk only 2 paths from the root.
+Range updates (`RangeAdd()`, `RangeAssign()`, `ReverseRange()`) cannot change an arbitrary aggregation at once,
+so the 1st query after them recalculates the whole updated range.
+
+	if treap has no aggregation: return sum, same as `RangeSum()`
+	if index_left > index_right: return 0
+	if any index out of range: return aggregation of only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus amount of changed nodes since the previous query;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) RangeQuery(index_left int, index_right int) int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	} else if t.opts.combine == nil {
+		return t.RangeSum(index_left, index_right)
+	}
+	t.checkRange(index_left, index_right)
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size(t.root)-1)
+	if index_left > index_right {
+		return 0
+	}
+	v := t.enter()
+	t.augment()
+	result := query(t.root, index_left, index_right+1, t.opts.combine)
+	t.exit(v)
+	return result
+}
+
+/*
+Returns aggregation of the elements of the subtree with indexes from lo to hi-1, same as `bounds()`.
+Range must intersect the subtree.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus amount of changed nodes;
+*/
+func query(n *node, lo int, hi int, combine func(int, int) int) int {
+	if lo <= 0 && hi >= n.size {
+		return fold(n, combine)
+	}
+	push(n)
+	lsize := size(n.lson)
+	var result int
+	found := false
+	if lo < lsize {
+		result, found = query(n.lson, lo, min(hi, lsize), combine), true
+	}
+	if lo <= lsize && lsize < hi {
+		if found {
+			result = combine(result, n.value)
+		} else {
+			result, found = n.value, true
+		}
+	}
+	if hi > lsize+1 {
+		r := query(n.rson, max(lo-lsize-1, 0), hi-lsize-1, combine)
+		if found {
+			result = combine(result, r)
+		} else {
+			result = r
+		}
+	}
+	return result
+}
+
+/*
+Returns aggregation of the whole subtree and caches it in the node.
+Only nodes changed since the previous call are visited.
+
+# Time complexity:
+  - Constant - if the node was not changed;
+  - Linear - time complexity is equal to amount of changed nodes of the subtree;
+*/
+func fold(n *node, combine func(int, int) int) int {
+	a := n.aug
+	if a.folded {
+		return a.fold
+	}
+	push(n)
+	a.fold = n.value
+	if n.lson != nil {
+		a.fold = combine(fold(n.lson, combine), a.fold)
+	}
+	if n.rson != nil {
+		a.fold = combine(a.fold, fold(n.rson, combine))
+	}
+	a.folded = true
+	return a.fold
+}
@@ -0,0 +1,99 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestRangeQueryModel(t *testing.T) {
+	combines := map[string]func(int, int) int{
+		"first": func(a int, b int) int { return a },
+		"last":  func(a int, b int) int { return b },
+		"min":   func(a int, b int) int { return min(a, b) },
+		"xor":   func(a int, b int) int { return a ^ b },
+	}
+	r := rand.New(rand.NewPCG(37, 38))
+	for name, combine := range combines {
+		var model []int
+		tr := NewAggregated(combine)
+		for step := range 2000 {
+			n := len(model)
+			switch r.IntN(6) {
+			case 0, 1:
+				i, value := r.IntN(n+1), r.IntN(100)
+				tr.Insert(i, value)
+				model = slices.Insert(model, i, value)
+			case 2:
+				if n > 0 {
+					i := r.IntN(n)
+					tr.Delete(i)
+					model = slices.Delete(model, i, i+1)
+				}
+			case 3:
+				if n > 0 {
+					l := r.IntN(n)
+					h := l + r.IntN(n-l)
+					tr.ReverseRange(l, h)
+					slices.Reverse(model[l : h+1])
+				}
+			case 4:
+				if n > 0 {
+					l := r.IntN(n)
+					h := l + r.IntN(n-l)
+					value := r.IntN(100)
+					if r.IntN(2) == 0 {
+						tr.RangeAssign(l, h, value)
+						for i := l; i <= h; i++ {
+							model[i] = value
+						}
+					} else {
+						tr.RangeAdd(l, h, value)
+						for i := l; i <= h; i++ {
+							model[i] += value
+						}
+					}
+				}
+			case 5:
+				if n > 0 {
+					l := r.IntN(n)
+					h := l + r.IntN(n-l)
+					want := model[l]
+					for _, value := range model[l+1 : h+1] {
+						want = combine(want, value)
+					}
+					if got := tr.RangeQuery(l, h); got != want {
+						t.Fatalf("%s, step %d: RangeQuery(%d, %d) = %d, want %d", name, step, l, h, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRangeQuery(t *testing.T) {
+	tr := NewAggregated(func(a int, b int) int { return max(a, b) }, 3, 1, 4, 1, 5)
+	if got := tr.RangeQuery(-2, 3); got != 4 {
+		t.Errorf("RangeQuery(-2, 3) = %d, want 4", got)
+	}
+	if got := tr.RangeQuery(3, 1); got != 0 {
+		t.Errorf("RangeQuery(3, 1) = %d, want 0", got)
+	}
+	l, r := Split(&tr, 2)
+	if got := r.RangeQuery(0, 2); got != 5 {
+		t.Errorf("RangeQuery() after Split() = %d, want 5", got)
+	}
+	merged := Merge(&l, &r)
+	if got := merged.RangeQuery(0, 1); got != 3 {
+		t.Errorf("RangeQuery() after Merge() = %d, want 3", got)
+	}
+	plain := New(1, 2, 3)
+	if got := plain.RangeQuery(0, 2); got != 6 {
+		t.Errorf("RangeQuery() without aggregation = %d, want sum 6", got)
+	}
+	ordered := NewAggregated(func(a int, b int) int { return a }, 1, 2, 3)
+	ordered.ReverseRange(0, 2)
+	if got := ordered.RangeQuery(0, 2); got != 3 {
+		t.Errorf("RangeQuery() of a reversed treap = %d, want 3", got)
+	}
+}
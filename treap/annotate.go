@@ -0,0 +1,583 @@
+package treap
+
+import (
+	"cmp"
+	"slices"
+)
+
+/*
+Tag attached to the range of elements, for example syntax highlighting span or comment.
+Range is inclusive from Left to Right, same as in `Cut()`.
+*/
+type Annotation struct {
+	ID    int
+	Left  int
+	Right int
+	Tag   any
+}
+
+/*
+Node of the interval treap of annotations.
+Nodes are ordered by start of the range, ranges are stored half-open.
+Shift is the pending offset of both sons, that is pushed down when sons are reached.
+Parent is kept only for nodes inside of the treap, so pending offsets of a node can be found by its id.
+*/
+type anode struct {
+	id       int
+	start    int
+	end      int
+	tag      any
+	removed  bool
+	maxEnd   int
+	shift    int
+	priority int
+	tiebreak uint64
+	lson     *anode
+	rson     *anode
+	parent   *anode
+}
+
+/*
+Shifts the whole subtree by provided offset.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *anode) move(offset int) {
+	if n == nil {
+		return
+	}
+	n.start += offset
+	n.end += offset
+	n.maxEnd += offset
+	n.shift += offset
+}
+
+/*
+Pushes pending offset into the sons.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *anode) push() {
+	if n.shift != 0 {
+		n.lson.move(n.shift)
+		n.rson.move(n.shift)
+		n.shift = 0
+	}
+}
+
+/*
+Recalculate the biggest end in the subtree and link the sons to the node.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func async(n *anode) {
+	n.maxEnd = n.end
+	if n.lson != nil {
+		n.maxEnd = max(n.maxEnd, n.lson.maxEnd)
+		n.lson.parent = n
+	}
+	if n.rson != nil {
+		n.maxEnd = max(n.maxEnd, n.rson.maxEnd)
+		n.rson.parent = n
+	}
+}
+
+/*
+Merges 2 annotation subtrees, all ranges of the 1st one must start not after ranges of the 2nd one.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the annotation treap;
+*/
+func amerge(n1 *anode, n2 *anode) *anode {
+	if n1 == nil {
+		return n2
+	} else if n2 == nil {
+		return n1
+	}
+	if n1.priority > n2.priority || (n1.priority == n2.priority && n1.tiebreak > n2.tiebreak) {
+		n1.push()
+		n1.rson = amerge(n1.rson, n2)
+		async(n1)
+		return n1
+	}
+	n2.push()
+	n2.lson = amerge(n1, n2.lson)
+	async(n2)
+	return n2
+}
+
+/*
+Splits annotation subtree into ranges starting before the key and all others.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the annotation treap;
+*/
+func asplit(n *anode, key int) (l *anode, r *anode) {
+	if n == nil {
+		return nil, nil
+	}
+	n.push()
+	if n.start < key {
+		n.rson, r = asplit(n.rson, key)
+		async(n)
+		return n, r
+	}
+	l, n.lson = asplit(n.lson, key)
+	async(n)
+	return l, n
+}
+
+/*
+Annotation layer of the treap.
+Ranges are kept in a companion interval treap and their offsets are adjusted on every edit of the treap:
+  - elements inserted before the range shift it, inserted inside of the range extend it;
+  - elements deleted before the range shift it, deleted inside of the range shrink it;
+  - range, all elements of which were deleted, is removed.
+
+Coverage of every element is kept in a 2nd companion treap of runs of equally covered elements,
+so amount of annotations of the element and length of their union are found without visiting them.
+
+Elements inserted right before the 1st element of the range or right after the last one are not annotated.
+Layer is not carried over by `Merge()` and `Split()` and must not be used after the treap is consumed.
+*/
+type Annotations struct {
+	t     *Treap
+	root  *anode
+	runs  *cnode
+	nodes map[int]*anode
+	next  int
+	// Amount of removed nodes, that are still in the interval treap.
+	removed int
+}
+
+/*
+Creates new empty annotation layer, that follows all edits of the treap.
+
+	if t == nil: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Annotate() *Annotations {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	a := &Annotations{t: t, nodes: make(map[int]*anode)}
+	if count := size(t.root); count > 0 {
+		a.runs = newRun(count, 0)
+	}
+	t.observe(a)
+	return a
+}
+
+/*
+Stops following edits of the treap.
+Annotations can be still read, but their offsets are no longer adjusted.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of layers of the treap;
+*/
+func (a *Annotations) Detach() {
+	if a.t != nil {
+		a.t.unobserve(a)
+		a.t = nil
+	}
+}
+
+/*
+Attaches tag to all elements from index_left to index_right. Returns id of the annotation.
+Annotations may overlap.
+
+	if layer is detached: return ErrNilTreap
+	if index_left > index_right or any index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the annotation treap;
+*/
+func (a *Annotations) Add(index_left int, index_right int, tag any) (int, error) {
+	if a.t == nil {
+		return 0, ErrNilTreap
+	} else if err := a.t.validRange(index_left, index_right); err != nil {
+		return 0, err
+	}
+	n := &anode{
+		id:       a.next,
+		start:    index_left,
+		end:      index_right + 1,
+		maxEnd:   index_right + 1,
+		tag:      tag,
+		priority: randomPriority(),
+		tiebreak: mix(created.Add(1)),
+	}
+	a.next++
+	a.nodes[n.id] = n
+	l, r := asplit(a.root, n.start+1)
+	a.root = amerge(amerge(l, n), r)
+	a.cover(n.start, n.end, 1)
+	return n.id, nil
+}
+
+/*
+Removes annotation with provided id.
+
+	if id is unknown: return false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of both companion treaps,
+    removed nodes are dropped from the interval treap in bulk;
+*/
+func (a *Annotations) Remove(id int) bool {
+	n, ok := a.nodes[id]
+	if !ok {
+		return false
+	}
+	start, end := a.bounds(n)
+	a.cover(start, end, -1)
+	a.drop(n)
+	a.compact()
+	return true
+}
+
+/*
+Returns half-open range of the node inside of the interval treap,
+pending offsets of all its ancestors are added to the stored one.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the annotation treap;
+*/
+func (a *Annotations) bounds(n *anode) (start int, end int) {
+	offset := 0
+	for p := n; p != a.root; {
+		p = p.parent
+		offset += p.shift
+	}
+	return n.start + offset, n.end + offset
+}
+
+/*
+Changes coverage of all elements of the half-open range by provided delta.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func (a *Annotations) cover(start int, end int, delta int) {
+	l, k := csplit(a.runs, start)
+	m, r := csplit(k, end-start)
+	m.shift(delta)
+	a.runs = cjoin(cjoin(l, m), r)
+}
+
+/*
+Marks node as removed, it stays in the interval treap until the compaction.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (a *Annotations) drop(n *anode) {
+	if n.removed {
+		return
+	}
+	n.removed = true
+	delete(a.nodes, n.id)
+	a.removed++
+}
+
+/*
+Rebuilds the interval treap without removed nodes,
+if they are more than annotations left.
+
+# Time complexity:
+  - Loglinear - amortized constant for every removed node;
+*/
+func (a *Annotations) compact() {
+	if a.removed <= len(a.nodes) {
+		return
+	}
+	var root *anode
+	a.each(a.root, func(n *anode) {
+		if !n.removed {
+			n.lson, n.rson = nil, nil
+			n.maxEnd = n.end
+			root = amerge(root, n)
+		}
+	})
+	a.root, a.removed = root, 0
+}
+
+/*
+Calls function for every node of the subtree in order, including removed ones.
+Pending offsets are pushed into all nodes.
+Sons are read before calling the function, so it may relink the node.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func (a *Annotations) each(n *anode, f func(n *anode)) {
+	for n != nil {
+		n.push()
+		a.each(n.lson, f)
+		next := n.rson
+		f(n)
+		n = next
+	}
+}
+
+/*
+Calls function for every node of the subtree with range ending after the given offset.
+Function may change the range, but the order of starts must be kept.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of matching nodes multiplied by height of the annotation treap;
+*/
+func (a *Annotations) remap(n *anode, from int, f func(n *anode)) {
+	if n == nil || n.maxEnd <= from {
+		return
+	}
+	n.push()
+	a.remap(n.lson, from, f)
+	if n.end > from {
+		f(n)
+	}
+	a.remap(n.rson, from, f)
+	async(n)
+}
+
+/*
+Adjusts ranges after insertion.
+
+# Time complexity:
+  - Logarithmic - plus amount of ranges containing the insertion point multiplied by height of the annotation treap;
+*/
+func (a *Annotations) inserted(index int, count int) {
+	l, r := asplit(a.root, index)
+	r.move(count)
+	cover := 0
+	a.remap(l, index, func(n *anode) {
+		n.end += count
+		if !n.removed {
+			cover++
+		}
+	})
+	a.root = amerge(l, r)
+	// New elements are covered by the ranges extended by them, so the run of the neighbour is extended if possible.
+	if (index > 0 && cgrow(a.runs, index-1, count, cover)) || cgrow(a.runs, index, count, cover) {
+		return
+	}
+	lr, rr := csplit(a.runs, index)
+	a.runs = cmerge(cmerge(lr, newRun(count, cover)), rr)
+}
+
+/*
+Adjusts ranges after deletion, every bound inside of the deleted elements is moved to the index.
+
+# Time complexity:
+  - Logarithmic - plus amount of ranges touching the deleted elements multiplied by height of the annotation treap;
+*/
+func (a *Annotations) deleted(index int, count int) {
+	last := index + count
+	bound := func(b int) int {
+		if b <= index {
+			return b
+		} else if b <= last {
+			return index
+		}
+		return b - count
+	}
+	f := func(n *anode) {
+		n.start, n.end = bound(n.start), bound(n.end)
+		if n.start == n.end {
+			a.drop(n)
+		}
+	}
+	l, k := asplit(a.root, index+1)
+	m, r := asplit(k, last+1)
+	r.move(-count)
+	a.remap(l, index, f)
+	a.remap(m, index, f)
+	a.root = amerge(amerge(l, m), r)
+	a.compact()
+	lr, kr := csplit(a.runs, index)
+	_, rr := csplit(kr, count)
+	a.runs = cjoin(lr, rr)
+}
+
+/*
+Values do not move the elements, so ranges are not adjusted.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (a *Annotations) updated(index int, count int) {}
+
+/*
+Adjusts ranges after reversal of the elements:
+  - ranges inside of the reversed elements are mirrored;
+  - ranges partially covering the reversed elements are extended to cover all their elements;
+  - ranges covering all reversed elements are not changed.
+
+Coverage of the reversed elements is rebuilt from the adjusted ranges.
+
+# Time complexity:
+  - Loglinear - plus amount of ranges overlapping the reversed elements multiplied by height of the annotation treap;
+*/
+func (a *Annotations) mirrored(index int, count int) {
+	last := index + count
+	l, k := asplit(a.root, index)
+	m, r := asplit(k, last)
+	a.remap(l, index, func(n *anode) {
+		n.end = max(n.end, last)
+	})
+	var nodes []*anode
+	a.each(m, func(n *anode) {
+		if n.removed {
+			a.removed--
+			return
+		} else if n.end <= last {
+			n.start, n.end = index+last-n.end, index+last-n.start
+		} else {
+			n.start = index
+		}
+		n.lson, n.rson = nil, nil
+		n.maxEnd = n.end
+		nodes = append(nodes, n)
+	})
+	slices.SortFunc(nodes, func(n1 *anode, n2 *anode) int {
+		return cmp.Compare(n1.start, n2.start)
+	})
+	m = nil
+	for _, n := range nodes {
+		m = amerge(m, n)
+	}
+	a.root = amerge(amerge(l, m), r)
+	var ranges [][2]int
+	a.overlapping(a.root, index, last, func(n *anode) {
+		ranges = append(ranges, [2]int{max(n.start, index) - index, min(n.end, last) - index})
+	})
+	lr, kr := csplit(a.runs, index)
+	_, rr := csplit(kr, count)
+	a.runs = cjoin(cjoin(lr, buildRuns(count, ranges)), rr)
+}
+
+/*
+Returns amount of annotations.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (a *Annotations) Len() int {
+	return len(a.nodes)
+}
+
+/*
+Returns all annotations ordered by their left index.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of annotations;
+*/
+func (a *Annotations) All() []Annotation {
+	result := make([]Annotation, 0, len(a.nodes))
+	a.each(a.root, func(n *anode) {
+		if !n.removed {
+			result = append(result, n.annotation())
+		}
+	})
+	return result
+}
+
+/*
+Returns all annotations attached to the element with the given index, ordered by their left index.
+
+# Time complexity:
+  - Logarithmic - plus amount of found annotations multiplied by height of the annotation treap;
+*/
+func (a *Annotations) At(index int) []Annotation {
+	return a.Overlapping(index, index)
+}
+
+/*
+Returns all annotations attached to at least 1 element from index_left to index_right,
+ordered by their left index.
+
+# Time complexity:
+  - Logarithmic - plus amount of found annotations multiplied by height of the annotation treap;
+*/
+func (a *Annotations) Overlapping(index_left int, index_right int) []Annotation {
+	var result []Annotation
+	a.overlapping(a.root, index_left, index_right+1, func(n *anode) {
+		result = append(result, n.annotation())
+	})
+	return result
+}
+
+/*
+Calls function for every annotation of the subtree with range intersecting half-open range from lo to hi,
+in order of their starts. Subtrees ending before lo are skipped, as well as nodes starting after hi.
+
+# Time complexity:
+  - Logarithmic - plus amount of found annotations multiplied by height of the annotation treap;
+*/
+func (a *Annotations) overlapping(n *anode, lo int, hi int, f func(n *anode)) {
+	for n != nil && n.maxEnd > lo {
+		n.push()
+		a.overlapping(n.lson, lo, hi, f)
+		if n.start >= hi {
+			return
+		}
+		if !n.removed && n.end > lo {
+			f(n)
+		}
+		n = n.rson
+	}
+}
+
+/*
+Returns amount of annotations attached to the element with the given index,
+for example amount of reservations conflicting at the given moment.
+Unlike `At()`, annotations are not visited, coverage is read from the run containing the element.
+
+	if index out of range: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func (a *Annotations) CoverageAt(index int) int {
+	if index < 0 || index >= a.runs.elements() {
+		return 0
+	}
+	return ccover(a.runs, index)
+}
+
+/*
+Returns amount of elements from index_left to index_right, that have at least 1 annotation.
+Overlapping annotations are counted once, so it is the length of their union inside of the range.
+
+Range is clamped to the existing elements.
+
+	if index_left > index_right: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func (a *Annotations) TotalCoveredLength(index_left int, index_right int) int {
+	index_left, index_right = max(index_left, 0), min(index_right, a.runs.elements()-1)
+	if index_left > index_right {
+		return 0
+	}
+	return ccovered(a.runs, index_right+1) - ccovered(a.runs, index_left)
+}
+
+/*
+Returns annotation stored in the node.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *anode) annotation() Annotation {
+	return Annotation{ID: n.id, Left: n.start, Right: n.end - 1, Tag: n.tag}
+}
@@ -0,0 +1,199 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// Returns coverage of every element computed from all annotations of the layer.
+func coverageOf(a *Annotations, count int) []int {
+	coverage := make([]int, count)
+	for _, annotation := range a.All() {
+		for i := annotation.Left; i <= annotation.Right; i++ {
+			coverage[i]++
+		}
+	}
+	return coverage
+}
+
+// Returns amount of runs of the coverage treap and reports whether adjacent runs differ.
+func checkRuns(n *cnode) (runs int, ok bool) {
+	last, ok := -1, true
+	var walk func(n *cnode)
+	walk = func(n *cnode) {
+		if n == nil {
+			return
+		}
+		n.push()
+		walk(n.lson)
+		if n.cover == last || n.run <= 0 {
+			ok = false
+		}
+		last = n.cover
+		runs++
+		walk(n.rson)
+	}
+	walk(n)
+	return runs, ok
+}
+
+func TestCoverage(t *testing.T) {
+	tr := New(make([]int, 10)...)
+	a := tr.Annotate()
+	a.Add(1, 4, nil)
+	id, _ := a.Add(3, 6, nil)
+	a.Add(9, 9, nil)
+	for index, want := range []int{0, 1, 1, 2, 2, 1, 1, 0, 0, 1} {
+		if got := a.CoverageAt(index); got != want {
+			t.Errorf("CoverageAt(%d) = %d, want %d", index, got, want)
+		}
+	}
+	if got := a.TotalCoveredLength(0, 9); got != 7 {
+		t.Errorf("TotalCoveredLength(0, 9) = %d, want 7", got)
+	}
+	if got := a.TotalCoveredLength(-5, 3); got != 3 {
+		t.Errorf("TotalCoveredLength(-5, 3) = %d, want 3", got)
+	}
+	if got := a.TotalCoveredLength(5, 4); got != 0 {
+		t.Errorf("TotalCoveredLength(5, 4) = %d, want 0", got)
+	}
+	a.Remove(id)
+	if got := a.TotalCoveredLength(0, 9); got != 5 {
+		t.Errorf("TotalCoveredLength(0, 9) after Remove() = %d, want 5", got)
+	}
+	if got := a.CoverageAt(10); got != 0 {
+		t.Errorf("CoverageAt(10) = %d, want 0", got)
+	}
+}
+
+func TestCoverageModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	tr := New(make([]int, 50)...)
+	a := tr.Annotate()
+	var ids []int
+	for step := range 5000 {
+		n := tr.Size()
+		switch r.IntN(8) {
+		case 0, 1:
+			if n > 0 {
+				l := r.IntN(n)
+				id, err := a.Add(l, l+r.IntN(min(10, n-l)), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, id)
+			}
+		case 2:
+			if len(ids) > 0 {
+				i := r.IntN(len(ids))
+				a.Remove(ids[i])
+				ids[i] = ids[len(ids)-1]
+				ids = ids[:len(ids)-1]
+			}
+		case 3:
+			tr.InsertSlice(r.IntN(n+1), make([]int, 1+r.IntN(3))...)
+		case 4:
+			if n > 0 {
+				l := r.IntN(n)
+				tr.Cut(l, l+r.IntN(min(4, n-l)))
+			}
+		case 5:
+			if n > 0 {
+				l := r.IntN(n)
+				tr.ReverseRange(l, l+r.IntN(n-l))
+			}
+		case 6, 7:
+			coverage := coverageOf(a, n)
+			for i, want := range coverage {
+				if got := a.CoverageAt(i); got != want {
+					t.Fatalf("step %d: CoverageAt(%d) = %d, want %d", step, i, got, want)
+				}
+			}
+			if n > 0 {
+				l := r.IntN(n)
+				h := l + r.IntN(n-l)
+				want := 0
+				for _, c := range coverage[l : h+1] {
+					if c > 0 {
+						want++
+					}
+				}
+				if got := a.TotalCoveredLength(l, h); got != want {
+					t.Fatalf("step %d: TotalCoveredLength(%d, %d) = %d, want %d", step, l, h, got, want)
+				}
+			}
+			if runs, ok := checkRuns(a.runs); !ok || runs > 2*a.Len()+1 {
+				t.Fatalf("step %d: %d runs for %d annotations, adjacent runs differ: %v", step, runs, a.Len(), ok)
+			}
+		}
+	}
+}
+
+// Returns ranges of the annotations as pairs of inclusive bounds.
+func boundsOf(annotations []Annotation) [][2]int {
+	result := make([][2]int, len(annotations))
+	for i, annotation := range annotations {
+		result[i] = [2]int{annotation.Left, annotation.Right}
+	}
+	return result
+}
+
+func TestAnnotations(t *testing.T) {
+	tr := New(make([]int, 10)...)
+	a := tr.Annotate()
+	first, _ := a.Add(2, 4, "first")
+	second, _ := a.Add(3, 8, "second")
+	if _, err := a.Add(5, 4, nil); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Add(5, 4) = %v, want ErrIndexOutOfRange", err)
+	}
+	if _, err := a.Add(0, 10, nil); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Add(0, 10) = %v, want ErrIndexOutOfRange", err)
+	}
+	if got := a.At(3); len(got) != 2 || got[0].ID != first || got[0].Tag != "first" || got[1].ID != second {
+		t.Errorf("At(3) = %v", got)
+	}
+	if got := a.Overlapping(5, 9); len(got) != 1 || got[0].ID != second {
+		t.Errorf("Overlapping(5, 9) = %v", got)
+	}
+	if got := a.Overlapping(0, 1); got != nil {
+		t.Errorf("Overlapping(0, 1) = %v", got)
+	}
+	if !a.Remove(first) || a.Remove(first) || a.Len() != 1 {
+		t.Errorf("Remove() does not remove the annotation once, %d left", a.Len())
+	}
+}
+
+func TestAnnotationsFollowEdits(t *testing.T) {
+	tr := New(make([]int, 10)...)
+	a := tr.Annotate()
+	a.Add(2, 4, nil)
+	a.Add(6, 7, nil)
+	a.Add(8, 9, nil)
+	tr.Insert(3, 0)
+	tr.Insert(2, 0)
+	if got, want := boundsOf(a.All()), [][2]int{{3, 6}, {8, 9}, {10, 11}}; !slices.Equal(got, want) {
+		t.Fatalf("ranges after insertions = %v, want %v", got, want)
+	}
+	tr.Cut(5, 8)
+	if got, want := boundsOf(a.All()), [][2]int{{3, 4}, {5, 5}, {6, 7}}; !slices.Equal(got, want) {
+		t.Fatalf("ranges after deletion = %v, want %v", got, want)
+	}
+	tr.Cut(5, 5)
+	if got, want := boundsOf(a.All()), [][2]int{{3, 4}, {5, 6}}; !slices.Equal(got, want) {
+		t.Fatalf("ranges after deletion of a whole range = %v, want %v", got, want)
+	}
+	tr.ReverseRange(0, 5)
+	if got, want := boundsOf(a.All()), [][2]int{{0, 6}, {1, 2}}; !slices.Equal(got, want) {
+		t.Fatalf("ranges after reversal = %v, want %v", got, want)
+	}
+	a.Detach()
+	tr.Insert(0, 0)
+	if got, want := boundsOf(a.All()), [][2]int{{0, 6}, {1, 2}}; !slices.Equal(got, want) {
+		t.Errorf("ranges of a detached layer = %v, want %v", got, want)
+	}
+	if _, err := a.Add(0, 0, nil); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("Add() to a detached layer = %v, want ErrNilTreap", err)
+	}
+}
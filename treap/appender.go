@@ -0,0 +1,84 @@
+package treap
+
+/*
+Amount of buffered values used by `Appender()` if batch size is not positive.
+*/
+const defaultAppendBatch = 1024
+
+/*
+Buffer of values appended to the back of the treap.
+Values are built into a subtree in a linear time and merged into the root once per batch,
+so single appends in a loop cost amortized constant time instead of a merge each.
+Buffered values are not part of the treap until flushed.
+*/
+type Appender struct {
+	t     *Treap
+	batch int
+	buf   []int
+}
+
+/*
+Returns new appender to the back of the treap, that flushes every batch of values.
+Merge of a batch costs height of the treap, so batch should be bigger than the height.
+
+	if batch <= 0: batch size is 1024
+	if t == nil: return nil
+
+# Time complexity:
+  - Linear - time complexity is equal to batch size, buffer is allocated upfront;
+*/
+func (t *Treap) Appender(batch int) *Appender {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	if batch <= 0 {
+		batch = defaultAppendBatch
+	}
+	return &Appender{t: t, batch: batch, buf: make([]int, 0, batch)}
+}
+
+/*
+Buffers values and flushes the buffer if the batch is full.
+Returns error of the flush, see `Flush()`.
+
+# Time complexity:
+  - Constant - amortized, if batch is bigger than height of the treap;
+*/
+func (a *Appender) Append(values ...int) error {
+	a.buf = append(a.buf, values...)
+	if len(a.buf) < a.batch {
+		return nil
+	}
+	return a.Flush()
+}
+
+/*
+Appends all buffered values to the back of the treap in a single merge.
+If values do not fit into the treap, nothing is appended and values stay in the buffer.
+
+	if treap was consumed: return ErrConsumedTreap
+	if not all values fit into the treap: return ErrSizeLimit
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of buffered values plus height of the treap;
+*/
+func (a *Appender) Flush() error {
+	if len(a.buf) == 0 {
+		return nil
+	} else if err := a.t.TryPushBack(a.buf...); err != nil {
+		return err
+	}
+	a.buf = a.buf[:0]
+	return nil
+}
+
+/*
+Returns amount of buffered values, that are not flushed yet.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (a *Appender) Buffered() int {
+	return len(a.buf)
+}
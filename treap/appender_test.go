@@ -0,0 +1,56 @@
+package treap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAppender(t *testing.T) {
+	tr := New(0)
+	a := tr.Appender(4)
+	for i := 1; i <= 10; i++ {
+		if err := a.Append(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tr.Size() != 9 || a.Buffered() != 2 {
+		t.Fatalf("Size() = %d and Buffered() = %d, want 9 and 2", tr.Size(), a.Buffered())
+	}
+	a.Append(11, 12, 13, 14, 15)
+	if tr.Size() != 16 || a.Buffered() != 0 {
+		t.Fatalf("Size() = %d and Buffered() = %d after a long append, want 16 and 0", tr.Size(), a.Buffered())
+	}
+	a.Append(16)
+	if err := a.Flush(); err != nil || a.Flush() != nil {
+		t.Fatal(err)
+	}
+	want := make([]int, 17)
+	for i := range want {
+		want[i] = i
+	}
+	if got := tr.Export(); !slices.Equal(got, want) {
+		t.Errorf("Export() = %v, want %v", got, want)
+	}
+	checkShape(t, tr.root)
+	if got := tr.Appender(0); cap(got.buf) != defaultAppendBatch {
+		t.Errorf("Appender(0) buffers %d values, want %d", cap(got.buf), defaultAppendBatch)
+	}
+}
+
+func TestAppenderKeepsValuesThatDoNotFit(t *testing.T) {
+	tr := New(1, 2)
+	tr.SetLimit(3)
+	a := tr.Appender(2)
+	if err := a.Append(3, 4); !errors.Is(err, ErrSizeLimit) {
+		t.Fatalf("Append() over the limit = %v, want ErrSizeLimit", err)
+	}
+	if tr.Size() != 2 || a.Buffered() != 2 {
+		t.Errorf("Size() = %d and Buffered() = %d, want 2 and 2", tr.Size(), a.Buffered())
+	}
+	other := New(5)
+	Merge(&tr, &other)
+	if err := a.Flush(); !errors.Is(err, ErrConsumedTreap) {
+		t.Errorf("Flush() into a consumed treap = %v, want ErrConsumedTreap", err)
+	}
+}
@@ -0,0 +1,152 @@
+package treap
+
+/*
+Augmentations of a subtree, kept only by nodes of augmented treaps, see `SetAugmented()`.
+Hashes of the values are kept in both directions, so reversed subtree only swaps them.
+Pow is the base of the hash in the power of size, geo is the sum of all smaller powers,
+so adding to all values of the subtree changes both hashes by geo multiplied by the delta.
+Fill, negation and add are pending changes of the sons, see `push()`.
+*/
+type augment struct {
+	sum     int
+	min     int
+	max     int
+	hash    uint64
+	rhash   uint64
+	pow     uint64
+	geo     uint64
+	fold    int
+	add     int
+	fill    int
+	folded  bool
+	filled  bool
+	negated bool
+}
+
+/*
+Enables or disables augmentations of the treap.
+Plain treap keeps only sizes in its nodes, so its insertions, deletions and lookups cost the same
+as if the augmentations did not exist.
+Augmented treap also keeps sum, minimum, maximum, hashes and pending range updates of every subtree,
+they are stored in a separate record of every node.
+
+Treap is plain after `New()`, methods that need augmentations (range sums, minimums and maximums,
+hashes, range additions and assignments, aggregations and weights) enable them by themselves,
+so the 1st such call is linear and following calls have their usual complexity.
+Nodes inserted into an augmented treap are augmented, when they are merged into it.
+Disabling augmentations frees them and applies all pending range updates.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+  - Constant - if the treap is already in the requested state;
+*/
+func (t *Treap) SetAugmented(on bool) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	v := t.enter()
+	if on {
+		augmentAll(t.root)
+	} else {
+		stripAll(t.root)
+	}
+	t.exit(v)
+}
+
+/*
+Augments the whole treap, if it is not augmented yet.
+Called by every method that reads or changes augmentations.
+
+# Time complexity:
+  - Constant - if the treap is augmented;
+  - Linear - time complexity is equal to amount of nodes that are not augmented;
+*/
+func (t *Treap) augment() {
+	augmentAll(t.root)
+}
+
+/*
+Reports whether the subtree keeps augmentations.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func augmented(n *node) bool {
+	return n != nil && n.aug != nil
+}
+
+/*
+Attaches augmentations to all nodes of the subtree that do not have them.
+Subtree of an augmented node is always augmented, so such subtrees are skipped.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of nodes that are not augmented;
+*/
+func augmentAll(n *node) {
+	if n == nil || n.aug != nil {
+		return
+	}
+	push(n)
+	augmentAll(n.lson)
+	augmentAll(n.rson)
+	n.aug = &augment{}
+	resum(n)
+}
+
+/*
+Removes augmentations from all nodes of the subtree,
+pending range updates are pushed to the leaves before.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func stripAll(n *node) {
+	if n == nil {
+		return
+	}
+	push(n)
+	stripAll(n.lson)
+	stripAll(n.rson)
+	n.aug = nil
+}
+
+/*
+Recalculates augmentations of the node by checking all children's augmentations.
+Sons, that are not augmented yet, are augmented before, so subtree of an augmented node stays augmented.
+Result of the aggregation is not known here, so it is only marked to be recalculated, see `RangeQuery()`.
+Node must be augmented, its pending changes must be pushed before.
+
+# Time complexity:
+  - Constant - if both sons are augmented;
+  - Linear - time complexity is equal to amount of nodes that are not augmented;
+*/
+func resum(n *node) {
+	a := n.aug
+	a.folded = false
+	a.sum = n.value
+	a.min, a.max = n.value, n.value
+	a.hash, a.rhash, a.pow, a.geo = leafHash(n.value), leafHash(n.value), hashBase, 1
+	if l := n.lson; l != nil {
+		augmentAll(l)
+		a.sum += l.aug.sum
+		a.min, a.max = min(a.min, l.aug.min), max(a.max, l.aug.max)
+		a.hash = hadd(hmul(l.aug.hash, a.pow), a.hash)
+		a.rhash = hadd(hmul(a.rhash, l.aug.pow), l.aug.rhash)
+		a.geo = hadd(hmul(l.aug.geo, a.pow), a.geo)
+		a.pow = hmul(a.pow, l.aug.pow)
+	}
+	if r := n.rson; r != nil {
+		augmentAll(r)
+		a.sum += r.aug.sum
+		a.min, a.max = min(a.min, r.aug.min), max(a.max, r.aug.max)
+		a.hash = hadd(hmul(a.hash, r.aug.pow), r.aug.hash)
+		a.rhash = hadd(hmul(r.aug.rhash, a.pow), a.rhash)
+		a.geo = hadd(hmul(a.geo, r.aug.pow), r.aug.geo)
+		a.pow = hmul(a.pow, r.aug.pow)
+	}
+}
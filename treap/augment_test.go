@@ -0,0 +1,184 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+// Returns amount of augmented nodes and amount of all nodes of the subtree.
+func countAugmented(n *node) (augmented int, all int) {
+	if n == nil {
+		return 0, 0
+	}
+	la, ln := countAugmented(n.lson)
+	ra, rn := countAugmented(n.rson)
+	augmented, all = la+ra, ln+rn+1
+	if n.aug != nil {
+		augmented++
+	}
+	return augmented, all
+}
+
+func TestPlainTreapHasNoAugmentations(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5, 6, 7, 8)
+	tr.Insert(3, 10)
+	tr.Delete(0)
+	tr.ReverseRange(1, 5)
+	tr.Set(2, 20)
+	tr.Find(4)
+	tr.IndexOf(20)
+	tr.Count(7)
+	if augmented, _ := countAugmented(tr.root); augmented != 0 {
+		t.Errorf("plain treap has %d augmented nodes", augmented)
+	}
+	if got := tr.IndexOf(20); got != 2 {
+		t.Errorf("IndexOf(20) = %d, want 2", got)
+	}
+}
+
+func TestNodeSize(t *testing.T) {
+	// Stamp is not empty only in debug builds.
+	if size := unsafe.Sizeof(node{}) - unsafe.Sizeof(stamp{}); size > 64 {
+		t.Errorf("node takes %d bytes, want at most 64", size)
+	}
+}
+
+func TestAugmentedOnFirstUse(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	if got := tr.RangeSum(1, 3); got != 9 {
+		t.Fatalf("RangeSum(1, 3) = %d, want 9", got)
+	}
+	if augmented, all := countAugmented(tr.root); augmented != all {
+		t.Fatalf("%d of %d nodes are augmented after RangeSum()", augmented, all)
+	}
+	for i := range 100 {
+		tr.Insert(i%7, i)
+	}
+	tr.RangeMin(0, 0)
+	if augmented, all := countAugmented(tr.root); augmented != all {
+		t.Fatalf("%d of %d nodes are augmented after insertions", augmented, all)
+	}
+}
+
+func TestSetAugmented(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.SetAugmented(true)
+	if augmented, all := countAugmented(tr.root); augmented != all {
+		t.Fatalf("%d of %d nodes are augmented", augmented, all)
+	}
+	tr.RangeAdd(1, 3, 10)
+	tr.RangeAssign(4, 4, 7)
+	tr.SetAugmented(false)
+	if augmented, _ := countAugmented(tr.root); augmented != 0 {
+		t.Fatalf("%d nodes keep augmentations", augmented)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{1, 12, 13, 14, 7}) {
+		t.Errorf("pending updates were lost: %v", got)
+	}
+	if got := tr.RangeMax(0, 4); got != 14 {
+		t.Errorf("RangeMax() = %d, want 14", got)
+	}
+}
+
+func TestMergeOfPlainAndAugmented(t *testing.T) {
+	for _, plainFirst := range []bool{false, true} {
+		a, b := New(1, 2, 3), New(make([]int, 200)...)
+		a.RangeAdd(0, 2, 1)
+		if plainFirst {
+			a, b = b, a
+		}
+		m := Merge(&a, &b)
+		if got := m.RangeSum(0, m.Size()-1); got != 9 {
+			t.Errorf("RangeSum() of merged treaps = %d, want 9", got)
+		}
+	}
+}
+
+func TestAugmentationsModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	tr := New()
+	var model []int
+	for step := range 3000 {
+		n := len(model)
+		switch r.IntN(9) {
+		case 0, 1:
+			index, value := r.IntN(n+1), r.IntN(100)-50
+			tr.Insert(index, value)
+			model = slices.Insert(model, index, value)
+		case 2:
+			if n > 0 {
+				index := r.IntN(n)
+				tr.Delete(index)
+				model = slices.Delete(model, index, index+1)
+			}
+		case 3:
+			if n > 0 {
+				l := r.IntN(n)
+				h := l + r.IntN(n-l)
+				delta := r.IntN(10) - 5
+				tr.RangeAdd(l, h, delta)
+				for i := l; i <= h; i++ {
+					model[i] += delta
+				}
+			}
+		case 4:
+			if n > 0 {
+				l := r.IntN(n)
+				h := l + r.IntN(n-l)
+				tr.ReverseRange(l, h)
+				slices.Reverse(model[l : h+1])
+			}
+		case 5:
+			if n > 0 {
+				l := r.IntN(n)
+				h := l + r.IntN(n-l)
+				value := r.IntN(20)
+				tr.RangeAssign(l, h, value)
+				for i := l; i <= h; i++ {
+					model[i] = value
+				}
+			}
+		case 6:
+			tr.SetAugmented(r.IntN(2) == 0)
+		case 7:
+			if n > 0 {
+				l := r.IntN(n)
+				h := l + r.IntN(n-l)
+				if got, want := tr.RangeSum(l, h), sumOf(model[l:h+1]); got != want {
+					t.Fatalf("step %d: RangeSum(%d, %d) = %d, want %d", step, l, h, got, want)
+				} else if low, high := tr.RangeMin(l, h), tr.RangeMax(l, h); low != slices.Min(model[l:h+1]) || high != slices.Max(model[l:h+1]) {
+					t.Fatalf("step %d: bounds of [%d, %d] = %d, %d", step, l, h, low, high)
+				}
+			}
+		case 8:
+			fresh := New(model...)
+			if tr.Hash() != fresh.Hash() {
+				t.Fatalf("step %d: hash differs from the hash of the same values", step)
+			}
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatal("values differ from the model")
+	}
+}
+
+// Returns sum of the values.
+func sumOf(values []int) int {
+	result := 0
+	for _, value := range values {
+		result += value
+	}
+	return result
+}
+
+func BenchmarkInsertAugmented(b *testing.B) {
+	b.ReportAllocs()
+	tr := New()
+	tr.SetAugmented(true)
+	tr.PushBack(0)
+	for i := range b.N {
+		tr.Insert(i*7919%(i+1), i)
+	}
+}
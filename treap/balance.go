@@ -0,0 +1,171 @@
+package treap
+
+import (
+	"math"
+	"math/bits"
+)
+
+/*
+Weight of the newest depth in the moving average of observed depths.
+*/
+const balanceDecay = 1.0 / 64
+
+/*
+State of the balance monitoring, see `SetBalanceCheck()`.
+*/
+type balance struct {
+	observed   float64
+	rebalanced int
+}
+
+/*
+Returns new balance monitoring with empty history.
+
+	if b == nil: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (b *balance) fork() *balance {
+	if b == nil {
+		return nil
+	}
+	return &balance{}
+}
+
+/*
+Report of the balance monitoring.
+*/
+type Balance struct {
+	// Expected depth of the element in a random treap of the same size, equal to 2 ln(size).
+	Expected float64
+	// Moving average of depths reached by recent `Find()` calls.
+	Observed float64
+	// Amount of re-randomized subtrees.
+	Rebalanced int
+}
+
+/*
+Enables or disables balance monitoring.
+While enabled, every `Find()` measures the depth of its path and compares it with the bound of a random treap.
+If subtree on the path is much deeper than a random treap of the same size could be,
+for example after its structure was built by a broken or hostile source,
+priorities of the whole subtree are re-randomized, so its expected depth is logarithmic again.
+Re-randomized subtree keeps all its nodes, only its shape is changed,
+but such `Find()` counts as a change, so `Version()` grows and iterators made before it are invalidated.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SetBalanceCheck(on bool) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if !on {
+		t.opts.balance = nil
+	} else if t.opts.balance == nil {
+		t.opts.balance = &balance{}
+	}
+}
+
+/*
+Returns report of the balance monitoring.
+Observed depth and amount of re-randomized subtrees are zero if monitoring is disabled.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Balance() Balance {
+	if t == nil {
+		nilReceiver()
+		return Balance{}
+	}
+	var report Balance
+	if n := size(t.root); n > 1 {
+		report.Expected = 2 * math.Log(float64(n))
+	}
+	if b := t.opts.balance; b != nil {
+		report.Observed, report.Rebalanced = b.observed, b.rebalanced
+	}
+	return report
+}
+
+/*
+Returns the biggest depth, that is still acceptable for a random treap of provided size.
+Random treap exceeds it with a negligible probability.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func depthBound(size int) int {
+	return 4*bits.Len(uint(size)) + 4
+}
+
+/*
+Returns the node on the given index, same as `find()`,
+but checks the depth of the path if balance monitoring is enabled.
+Also reports whether a subtree was re-randomized,
+in that case the shape of the treap was changed, so the caller must finish with `leave()` instead of `exit()`.
+Index must be inside of the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+  - Linear - if subtree is re-randomized, time complexity is equal to its size;
+*/
+func (t *Treap) lookup(index int) (*node, bool) {
+	b := t.opts.balance
+	if b == nil {
+		return find(t.root, index), false
+	}
+	var stack [64]*node
+	n, path := descend(t.root, index, stack[:0], true)
+	b.observed += (float64(len(path)-1) - b.observed) * balanceDecay
+	for i, p := range path {
+		if len(path)-1-i > depthBound(p.size) {
+			t.rerandomize(path, i)
+			return n, true
+		}
+	}
+	return n, false
+}
+
+/*
+Re-randomizes priorities of the subtree rooted in path[i] and rebuilds its shape.
+New priorities are lower than priority of the parent, so heap order above the subtree is kept.
+Values are not changed, but the change is counted, so iterators over the old shape are invalidated.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func (t *Treap) rerandomize(path []*node, i int) {
+	bound := math.MaxInt
+	if i > 0 {
+		bound = path[i-1].priority
+	}
+	if bound <= 0 {
+		// Parent has the lowest priority, so the whole treap is rebuilt instead.
+		i, bound = 0, math.MaxInt
+	}
+	nodes := make([]*node, 0, path[i].size)
+	walk := walker{}
+	walk.seek(path[i], 0, false)
+	for n := walk.next(); n != nil; n = walk.next() {
+		nodes = append(nodes, n)
+	}
+	b := builder{spine: make([]*node, 0, 64)}
+	for _, n := range nodes {
+		n.priority = randomN(bound)
+		n.lson, n.rson = nil, nil
+		b.attach(n)
+	}
+	root := b.finish()
+	if i == 0 {
+		t.root = root
+	} else if parent := path[i-1]; parent.lson == path[i] {
+		parent.lson = root
+	} else {
+		parent.rson = root
+	}
+	t.opts.balance.rebalanced++
+	t.version++
+}
@@ -0,0 +1,94 @@
+package treap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Returns treap of values 0..n-1 shaped as a single path, which is the deepest treap possible.
+func degenerate(n int) Treap {
+	values := make([]int, n)
+	priorities := make([]int, n)
+	for i := range values {
+		values[i] = i
+		priorities[i] = n - i
+	}
+	return NewWithPriorities(values, priorities)
+}
+
+// Returns height of the subtree.
+func height(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(height(n.lson), height(n.rson))
+}
+
+func TestBalanceRerandomizes(t *testing.T) {
+	const n = 2000
+	tr := degenerate(n)
+	tr.SetBalanceCheck(true)
+	if got := tr.Find(n - 1); got != n-1 {
+		t.Fatalf("Find(%d) = %d", n-1, got)
+	}
+	report := tr.Balance()
+	if report.Rebalanced == 0 {
+		t.Fatal("deep path was not re-randomized")
+	} else if h := height(tr.root); h > depthBound(n) {
+		t.Errorf("height after re-randomization is %d, bound is %d", h, depthBound(n))
+	}
+	checkShape(t, tr.root)
+	want := make([]int, n)
+	for i := range want {
+		want[i] = i
+	}
+	if got := tr.Export(); !slices.Equal(got, want) {
+		t.Error("re-randomization changed values")
+	}
+	// Treap is still usable by mutations, which restamp it in debug builds.
+	tr.PushBack(n)
+	if got := tr.Find(n); got != n {
+		t.Errorf("Find(%d) after PushBack = %d", n, got)
+	}
+}
+
+func TestBalanceDisabled(t *testing.T) {
+	tr := degenerate(500)
+	tr.Find(499)
+	if report := tr.Balance(); report.Rebalanced != 0 || report.Observed != 0 {
+		t.Errorf("disabled monitoring reported %+v", report)
+	}
+}
+
+func TestBalanceInvalidatesIterators(t *testing.T) {
+	const n = 2000
+	tr := degenerate(n)
+	tr.SetBalanceCheck(true)
+	version := tr.Version()
+	it := tr.Iterator(n / 2)
+	tr.Find(n - 1)
+	if tr.Version() == version {
+		t.Fatal("re-randomization did not change the version")
+	}
+	r := recovered(func() { it.Next() })
+	if err, ok := r.(error); !ok || !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("iterator over the old shape panicked with %v, want ErrConcurrentModification", r)
+	}
+	it = tr.Iterator(n / 2)
+	if !it.Valid() || it.Value() != n/2 {
+		t.Errorf("new iterator is on %d", it.Value())
+	}
+}
+
+func TestBalanceKeepsRandomTreap(t *testing.T) {
+	tr := New(make([]int, 5000)...)
+	tr.SetBalanceCheck(true)
+	version := tr.Version()
+	for i := range 5000 {
+		tr.Find(i)
+	}
+	if report := tr.Balance(); report.Rebalanced != 0 || tr.Version() != version {
+		t.Errorf("random treap was re-randomized: %+v", report)
+	}
+}
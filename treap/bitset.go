@@ -0,0 +1,212 @@
+package treap
+
+/*
+Negates all values of the subtree: the node and its augmentations are changed at once,
+negation of the sons is left pending, see `push()`.
+Pending addition of the sons is made after the negation, so it is negated as well.
+Subtree is augmented before, if it is not yet.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Constant - if the subtree is augmented;
+*/
+func negate(n *node) {
+	if n == nil {
+		return
+	}
+	augmentAll(n)
+	a := n.aug
+	n.value = -n.value
+	a.sum = -a.sum
+	a.min, a.max = -a.max, -a.min
+	// Hash of every value is shifted by the offset, so the offset of all values is added twice.
+	a.hash = hsub(hmul(2*hashOffset%hashModulus, a.geo), a.hash)
+	a.rhash = hsub(hmul(2*hashOffset%hashModulus, a.geo), a.rhash)
+	a.negated = !a.negated
+	a.add = -a.add
+	a.folded = false
+}
+
+/*
+Dynamic sequence of bits with rank and select queries.
+Unlike a static succinct bitset, bits can be inserted and deleted in any position,
+and whole ranges can be inverted in a logarithmic time.
+Bits are kept as values 0 and 1 of an augmented treap, so amount of set bits of every subtree is its sum,
+and inversion of a range is its negation followed by addition of 1.
+
+# Structure is unsafe to be used in parallel goroutines.
+*/
+type Bitset struct {
+	root *node
+}
+
+/*
+Returns bit as a value of the treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func bitValue(bit bool) int {
+	if bit {
+		return 1
+	}
+	return 0
+}
+
+/*
+Correctly initialize a bitset with provided bits in order.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided bits;
+*/
+func NewBitset(bits ...bool) Bitset {
+	values := make([]int, len(bits))
+	for i, bit := range bits {
+		values[i] = bitValue(bit)
+	}
+	b := Bitset{root: build(values, false, nil)}
+	augmentAll(b.root)
+	return b
+}
+
+/*
+Returns amount of bits.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (b *Bitset) Len() int {
+	return size(b.root)
+}
+
+/*
+Returns amount of set bits.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (b *Bitset) Ones() int {
+	return sum(b.root)
+}
+
+/*
+Returns the bit with the given index.
+
+	if index out of range: return false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) Get(index int) bool {
+	if index < 0 || index >= size(b.root) {
+		return false
+	}
+	return find(b.root, index).value == 1
+}
+
+/*
+Inserts the bit, so it gets the given index.
+
+	if index <= 0: insert before the 1st bit
+	if index >= amount of bits: insert after the last bit
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) Insert(index int, bit bool) {
+	l, r := split(b.root, index-1)
+	b.root = merge(merge(l, newNode(bitValue(bit))), r)
+	// New node stays without augmentations, if it became the root.
+	augmentAll(b.root)
+}
+
+/*
+Deletes the bit with the given index.
+
+	if index out of range: do nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) Delete(index int) {
+	if index < 0 || index >= size(b.root) {
+		return
+	}
+	l, k := split(b.root, index-1)
+	_, r := split(k, 0)
+	b.root = merge(l, r)
+}
+
+/*
+Changes the bit with the given index.
+
+	if index out of range: do nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) Set(index int, bit bool) {
+	if index >= 0 && index < size(b.root) {
+		set(b.root, index, bitValue(bit))
+	}
+}
+
+/*
+Inverts all bits from index_left to index_right.
+Range is clamped to the existing bits.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) FlipRange(index_left int, index_right int) {
+	index_left, index_right = max(index_left, 0), min(index_right, size(b.root)-1)
+	if index_left > index_right {
+		return
+	}
+	l, k := split(b.root, index_left-1)
+	m, r := split(k, index_right-index_left)
+	negate(m)
+	increase(m, 1)
+	b.root = merge(merge(l, m), r)
+}
+
+/*
+Returns amount of set bits with indexes less than the given one.
+
+	if index <= 0: return 0
+	if index >= amount of bits: return amount of all set bits
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) Rank1(index int) int {
+	return prefixSum(b.root, index)
+}
+
+/*
+Returns amount of unset bits with indexes less than the given one, same as `Rank1()`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) Rank0(index int) int {
+	index = max(0, min(index, size(b.root)))
+	return index - b.Rank1(index)
+}
+
+/*
+Returns index of the k-th set bit, counting from 0.
+Set bits are weights 1 and unset bits are weights 0, so it is the same descent as `SampleWeighted()`.
+
+	if k < 0 or k >= amount of set bits: return -1
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (b *Bitset) Select1(k int) int {
+	if k < 0 || k >= sum(b.root) {
+		return -1
+	}
+	return sample(b.root, k)
+}
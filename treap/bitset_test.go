@@ -0,0 +1,119 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestBitsetModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	b := NewBitset(true, false, true)
+	model := []bool{true, false, true}
+	for step := range 5000 {
+		switch r.IntN(4) {
+		case 0:
+			index, bit := r.IntN(len(model)+1), r.IntN(2) == 0
+			b.Insert(index, bit)
+			model = slices.Insert(model, index, bit)
+		case 1:
+			if len(model) > 0 {
+				index := r.IntN(len(model))
+				b.Delete(index)
+				model = slices.Delete(model, index, index+1)
+			}
+		case 2:
+			l, h := r.IntN(len(model)+2)-1, r.IntN(len(model)+2)-1
+			b.FlipRange(l, h)
+			for i := max(l, 0); i <= min(h, len(model)-1); i++ {
+				model[i] = !model[i]
+			}
+		case 3:
+			if len(model) > 0 {
+				index, bit := r.IntN(len(model)), r.IntN(2) == 0
+				b.Set(index, bit)
+				model[index] = bit
+			}
+		}
+
+		q := r.IntN(len(model) + 2)
+		ones := 0
+		for _, bit := range model[:min(q, len(model))] {
+			if bit {
+				ones++
+			}
+		}
+		if b.Len() != len(model) {
+			t.Fatalf("step %d: Len() = %d, want %d", step, b.Len(), len(model))
+		} else if got := b.Rank1(q); got != ones {
+			t.Fatalf("step %d: Rank1(%d) = %d, want %d", step, q, got, ones)
+		} else if got := b.Rank0(q); got != min(q, len(model))-ones {
+			t.Fatalf("step %d: Rank0(%d) = %d", step, q, got)
+		}
+
+		k, want := r.IntN(len(model)+1), -1
+		for i, seen := 0, 0; i < len(model) && want < 0; i++ {
+			if model[i] && seen == k {
+				want = i
+			} else if model[i] {
+				seen++
+			}
+		}
+		if got := b.Select1(k); got != want {
+			t.Fatalf("step %d: Select1() = %d, want %d", step, got, want)
+		}
+		if len(model) > 0 {
+			index := r.IntN(len(model))
+			if b.Get(index) != model[index] {
+				t.Fatalf("step %d: Get(%d) = %v", step, index, b.Get(index))
+			}
+		}
+	}
+	if fresh := NewBitset(model...); hash(b.root) != hash(fresh.root) {
+		t.Error("hash differs from the hash of the same bits")
+	}
+}
+
+func TestBitsetSelect(t *testing.T) {
+	b := NewBitset(false, true, true, false, true)
+	for k, want := range []int{1, 2, 4, -1} {
+		if got := b.Select1(k); got != want {
+			t.Errorf("Select1(%d) = %d, want %d", k, got, want)
+		}
+	}
+	if got := b.Select1(-1); got != -1 {
+		t.Errorf("Select1(-1) = %d, want -1", got)
+	}
+	b.FlipRange(0, 4)
+	if b.Ones() != 2 || b.Select1(0) != 0 || b.Select1(1) != 3 {
+		t.Errorf("after FlipRange() Ones() = %d, Select1(0) = %d, Select1(1) = %d", b.Ones(), b.Select1(0), b.Select1(1))
+	}
+}
+
+func TestBitsetUsesTreapNodes(t *testing.T) {
+	bits := make([]bool, 1000)
+	for i := range bits {
+		bits[i] = i%3 == 0
+	}
+	b := NewBitset(bits...)
+	checkShape(t, b.root)
+	if augmented, all := countAugmented(b.root); augmented != all {
+		t.Errorf("%d of %d bitset nodes are augmented", augmented, all)
+	}
+	if b.Ones() != 334 {
+		t.Errorf("Ones() = %d, want 334", b.Ones())
+	}
+	var empty Bitset
+	if empty.Len() != 0 || empty.Ones() != 0 || empty.Get(0) || empty.Select1(0) != -1 {
+		t.Error("zero bitset is not empty")
+	}
+}
+
+func TestNegatedRangeKeepsHash(t *testing.T) {
+	b := NewBitset(true, false, false, true, true)
+	b.FlipRange(1, 3)
+	want := NewBitset(true, true, true, false, true)
+	if hash(b.root) != hash(want.root) {
+		t.Error("hash of the flipped range differs from the hash of the same bits")
+	}
+}
@@ -0,0 +1,68 @@
+package treap
+
+/*
+Behaviour of the treap with size limit, when inserted elements do not fit into it.
+*/
+type Policy int
+
+const (
+	// Inserted elements are rejected, same as in a treap with `SetLimit()`.
+	EvictNone Policy = iota
+	// Oldest elements from the front are deleted, so the treap keeps the most recent values pushed back.
+	EvictFront
+	// Elements from the back are deleted.
+	EvictBack
+)
+
+/*
+Correctly initialize an empty treap, that never holds more than max elements.
+Inserts beyond the bound are made in full, then the overflow is deleted according to the policy,
+so the treap can be used as a capped buffer without manual trimming.
+Evicted elements are deleted as by `Cut()`, so the journal, marks and annotations see the deletion.
+
+	if max <= 0: treap is unlimited and nothing is evicted
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func NewBounded(max int, evict Policy) Treap {
+	t := New()
+	t.SetLimit(max)
+	t.opts.evict = evict
+	return t
+}
+
+/*
+Returns behaviour of the treap when its size limit is reached.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) EvictionPolicy() Policy {
+	if t == nil {
+		nilReceiver()
+		return EvictNone
+	}
+	return t.opts.evict
+}
+
+/*
+Deletes elements exceeding the size limit according to the eviction policy.
+
+	if treap does not exceed the limit: do nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) evict() {
+	limit, n := t.opts.limit, size(t.root)
+	if limit <= 0 || n <= limit {
+		return
+	}
+	switch t.opts.evict {
+	case EvictFront:
+		t.cut(0, n-limit-1)
+	case EvictBack:
+		t.cut(limit, n-1)
+	}
+}
@@ -0,0 +1,62 @@
+package treap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewBounded(t *testing.T) {
+	front := NewBounded(3, EvictFront)
+	back := NewBounded(3, EvictBack)
+	for i := range 5 {
+		front.PushBack(i)
+		back.PushBack(i)
+	}
+	if got := front.Export(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("EvictFront keeps %v, want [2 3 4]", got)
+	}
+	if got := back.Export(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("EvictBack keeps %v, want [0 1 2]", got)
+	}
+	// Values pushed to the front are the 1st to be evicted from it.
+	front.PushFront(7, 8, 9, 10)
+	if got := front.Export(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("EvictFront after PushFront() keeps %v, want [2 3 4]", got)
+	}
+	back.PushFront(7, 8)
+	if got := back.Export(); !slices.Equal(got, []int{8, 7, 0}) {
+		t.Errorf("EvictBack after PushFront() keeps %v, want [8 7 0]", got)
+	}
+	if err := back.TryInsert(1, 5); err != nil {
+		t.Errorf("TryInsert() into full evicting treap = %v", err)
+	}
+	if err := back.Apply(InsertOp(0, 1, 2, 3, 4)); err != nil || back.Size() != 3 {
+		t.Errorf("Apply() into full evicting treap = %v, size %d", err, back.Size())
+	}
+	if front.EvictionPolicy() != EvictFront || back.EvictionPolicy() != EvictBack {
+		t.Error("EvictionPolicy() does not report the policy")
+	}
+}
+
+func TestNewBoundedObservers(t *testing.T) {
+	tr := NewBounded(4, EvictFront)
+	tr.PushBack(0, 1, 2, 3)
+	m := tr.Mark(2)
+	deleted := 0
+	tr.AddHooks(&Hooks{OnDelete: func(index int, count int) { deleted += count }})
+	tr.PushBack(4)
+	if m.Index() != 1 || deleted != 1 {
+		t.Errorf("eviction moved mark to %d and deleted %d elements, want 1 and 1", m.Index(), deleted)
+	}
+	none := NewBounded(2, EvictNone)
+	none.PushBack(1, 2)
+	if err := none.TryPushBack(3); !errors.Is(err, ErrSizeLimit) {
+		t.Errorf("TryPushBack() into full treap = %v, want ErrSizeLimit", err)
+	}
+	unlimited := NewBounded(0, EvictFront)
+	unlimited.PushBack(make([]int, 100)...)
+	if unlimited.Size() != 100 {
+		t.Errorf("unlimited treap holds %d elements", unlimited.Size())
+	}
+}
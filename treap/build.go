@@ -0,0 +1,146 @@
+package treap
+
+import rand "math/rand/v2"
+
+/*
+Amount of priorities generated by the builder at once.
+*/
+const priorityBatch = 64
+
+/*
+Builds treap from the sequence of values in a linear time.
+Keeps the right spine of the already built part on its stack,
+every new node is attached to the spine as a Cartesian tree.
+
+Priorities are generated in batches by a local generator,
+and tie-breaking words are reserved for the whole batch by a single atomic operation.
+*/
+type builder struct {
+	spine      []*node
+	pool       *pool
+	rng        *rand.PCG
+	priorities [priorityBatch]int
+	tiebreak   uint64
+	next       int
+}
+
+/*
+Generates next batch of priorities and reserves tie-breaking words for it.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (b *builder) refill() {
+	if b.rng == nil {
+		b.rng = rand.NewPCG(entropy.Uint64(), entropy.Uint64())
+	}
+	for i := range b.priorities {
+		// Same range as rand.Int() returns.
+		b.priorities[i] = int(uint(b.rng.Uint64()) >> 1)
+	}
+	b.tiebreak = created.Add(priorityBatch) - priorityBatch
+	b.next = 0
+}
+
+/*
+Appends value to the back of the built sequence.
+
+# Time complexity:
+  - Constant - amortized, each node is pushed and popped from the spine only once;
+*/
+func (b *builder) push(value int) {
+	if b.next == 0 || b.next == priorityBatch {
+		b.refill()
+	}
+	n := b.pool.take()
+	b.next++
+	initNode(n, value, b.priorities[b.next-1], mix(b.tiebreak+uint64(b.next)))
+	b.attach(n)
+}
+
+/*
+Appends node to the back of the built sequence, same as `push()`, but with the priority of the node.
+
+# Time complexity:
+  - Constant - amortized, each node is pushed and popped from the spine only once;
+*/
+func (b *builder) attach(n *node) {
+	var last *node
+	for len(b.spine) > 0 && outranks(n, b.spine[len(b.spine)-1]) {
+		last = b.spine[len(b.spine)-1]
+		b.spine = b.spine[:len(b.spine)-1]
+		// Popped node will never get new sons.
+		sync(last)
+	}
+	n.lson = last
+	if len(b.spine) > 0 {
+		b.spine[len(b.spine)-1].rson = n
+	}
+	b.spine = append(b.spine, n)
+}
+
+/*
+Returns the root of the built treap and resets the builder.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the right spine;
+*/
+func (b *builder) finish() *node {
+	if len(b.spine) == 0 {
+		return nil
+	}
+	for i := len(b.spine) - 1; i >= 0; i-- {
+		sync(b.spine[i])
+	}
+	root := b.spine[0]
+	b.spine = b.spine[:0]
+	return root
+}
+
+/*
+Builds treap from all provided values.
+
+	if reversed: values are taken from the last to the first
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func build(values []int, reversed bool, p *pool) *node {
+	if len(values) == 0 {
+		return nil
+	} else if len(values) == 1 {
+		return p.get(values[0])
+	}
+	b := builder{spine: make([]*node, 0, 64), pool: p}
+	if reversed {
+		for i := len(values) - 1; i >= 0; i-- {
+			b.push(values[i])
+		}
+	} else {
+		for _, value := range values {
+			b.push(value)
+		}
+	}
+	return b.finish()
+}
+
+/*
+Correctly initialize a treap of count copies of the value, same as `New()` with repeated values,
+but without the slice of values.
+
+	if count <= 0: return empty treap
+
+# Time complexity:
+  - Linear - time complexity is equal to count;
+*/
+func Repeat(value int, count int) Treap {
+	t := Treap{}
+	b := builder{spine: make([]*node, 0, 64)}
+	for range count {
+		b.push(value)
+	}
+	v := t.enter()
+	t.root = b.finish()
+	t.leave(v)
+	return t
+}
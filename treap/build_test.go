@@ -0,0 +1,79 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+// Checks heap order and stored sizes of the subtree, returns its size.
+func checkShape(t *testing.T, n *node) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	for _, son := range []*node{n.lson, n.rson} {
+		if son != nil && outranks(son, n) {
+			t.Fatalf("son with priority %d outranks parent with priority %d", son.priority, n.priority)
+		}
+	}
+	size := 1 + checkShape(t, n.lson) + checkShape(t, n.rson)
+	if size != n.size {
+		t.Fatalf("node stores size %d, but subtree has %d nodes", n.size, size)
+	}
+	return size
+}
+
+func TestBuild(t *testing.T) {
+	for _, count := range []int{0, 1, 2, priorityBatch - 1, priorityBatch, priorityBatch + 1, 1000} {
+		values := make([]int, count)
+		for i := range values {
+			values[i] = i*31%17 - 8
+		}
+		built := New(values...)
+		checkShape(t, built.root)
+		if got := built.Export(); !slices.Equal(got, values) && count > 0 {
+			t.Fatalf("New() of %d values = %v", count, got)
+		}
+
+		pushed := New()
+		for _, value := range values {
+			pushed.PushBack(value)
+		}
+		if built.Hash() != pushed.Hash() || built.RangeSum(0, count-1) != pushed.RangeSum(0, count-1) {
+			t.Errorf("built and pushed treaps of %d values have different augmentations", count)
+		}
+	}
+}
+
+func TestBuildReversed(t *testing.T) {
+	tr := New(1, 2)
+	tr.PushFront(3, 4, 5)
+	checkShape(t, tr.root)
+	if got := tr.Export(); !slices.Equal(got, []int{5, 4, 3, 1, 2}) {
+		t.Errorf("PushFront(3, 4, 5) = %v", got)
+	}
+}
+
+func TestBuildUniqueTiebreaks(t *testing.T) {
+	tr := New(make([]int, 3*priorityBatch)...)
+	seen := make(map[uint64]bool)
+	w := walker{}
+	w.seek(tr.root, 0, false)
+	for n := w.next(); n != nil; n = w.next() {
+		if seen[n.tiebreak] {
+			t.Fatalf("tie-breaking word %#x is used twice", n.tiebreak)
+		}
+		seen[n.tiebreak] = true
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	tr := Repeat(7, 100)
+	checkShape(t, tr.root)
+	if tr.Size() != 100 || tr.Count(7) != 100 {
+		t.Errorf("Repeat(7, 100) has size %d and %d sevens", tr.Size(), tr.Count(7))
+	}
+	if empty := Repeat(7, -1); empty.Size() != 0 {
+		t.Errorf("Repeat(7, -1) has size %d", empty.Size())
+	}
+}
@@ -0,0 +1,74 @@
+package treap
+
+/*
+Merges all provided treaps in order. Returns resulted treap.
+Treaps are merged by tree reduction instead of a left fold,
+each range of treaps is divided where its total size is split in halves,
+so big treaps are merged as few times as possible.
+
+Old treaps are left empty and marked as consumed, they must not be used afterwards.
+Resulted treap uses configuration of the 1st non-nil treap.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of treaps multiplied by height of the highest treap;
+*/
+func ConcatBalanced(ts []*Treap) (t Treap) {
+	roots := make([]*node, 0, len(ts))
+	visits := make([]visit, 0, len(ts))
+	first := true
+	for _, ti := range ts {
+		if ti == nil {
+			continue
+		}
+		if first {
+			t.opts = ti.opts
+			first = false
+		}
+		visits = append(visits, ti.enter())
+		if ti.root != nil {
+			roots = append(roots, ti.root)
+		}
+	}
+	// prefix[i] is the total size of the first i roots.
+	prefix := make([]int, len(roots)+1)
+	for i, root := range roots {
+		prefix[i+1] = prefix[i] + root.size
+	}
+	t.root = concat(roots, prefix, 0, len(roots))
+	for _, v := range visits {
+		t.leave(v)
+	}
+	for _, ti := range ts {
+		ti.consume()
+	}
+	return t
+}
+
+/*
+Merges roots with indexes from lo to hi-1.
+Range is divided by the middle of its total size,
+but each part always gets at least 1 root.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of roots multiplied by height of the highest treap;
+*/
+func concat(roots []*node, prefix []int, lo int, hi int) *node {
+	if hi-lo == 0 {
+		return nil
+	} else if hi-lo == 1 {
+		return roots[lo]
+	}
+	half := prefix[lo] + (prefix[hi]-prefix[lo])/2
+	mid := hi - 1
+	// Binary search of the first prefix that is not less than the half.
+	for l, r := lo+1, hi-1; l <= r; {
+		m := l + (r-l)/2
+		if prefix[m] < half {
+			l = m + 1
+		} else {
+			mid = m
+			r = m - 1
+		}
+	}
+	return merge(concat(roots, prefix, lo, mid), concat(roots, prefix, mid, hi))
+}
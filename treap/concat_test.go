@@ -0,0 +1,47 @@
+package treap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestConcatBalanced(t *testing.T) {
+	var ts []*Treap
+	var want []int
+	for i := range 40 {
+		values := make([]int, i*i%17)
+		for j := range values {
+			values[j] = len(want) + j
+		}
+		want = append(want, values...)
+		tr := New(values...)
+		ts = append(ts, &tr)
+		if i%7 == 0 {
+			ts = append(ts, nil)
+		}
+	}
+	result := ConcatBalanced(ts)
+	if got := result.Export(); !slices.Equal(got, want) {
+		t.Fatalf("ConcatBalanced() = %v, want %v", got, want)
+	}
+	checkShape(t, result.root)
+	for _, tr := range ts {
+		if tr == nil {
+			continue
+		}
+		if _, err := tr.TryFind(0); !errors.Is(err, ErrConsumedTreap) {
+			t.Fatalf("TryFind() of a concatenated treap = %v, want ErrConsumedTreap", err)
+		}
+	}
+}
+
+func TestConcatBalancedEmpty(t *testing.T) {
+	if result := ConcatBalanced(nil); result.Size() != 0 {
+		t.Errorf("ConcatBalanced(nil) has size %d", result.Size())
+	}
+	empty := New()
+	if result := ConcatBalanced([]*Treap{nil, &empty}); result.Size() != 0 {
+		t.Errorf("ConcatBalanced() of empty treaps has size %d", result.Size())
+	}
+}
@@ -0,0 +1,61 @@
+package treap
+
+/*
+Returns new treap with elements of both treaps, same as `Merge()`, but both treaps are left intact.
+Values are copied and built into a new treap in a linear time, since nodes cannot be shared between treaps.
+Resulted treap uses configuration of the 1st treap, same as the 2nd result of `Split()`.
+
+	if a == nil or b == nil: nil treap is treated as empty
+
+# Time complexity:
+  - Linear - time complexity is equal to size of both treaps;
+*/
+func Concat(a *Treap, b *Treap) Treap {
+	if a == nil && b == nil {
+		return Treap{}
+	} else if a == nil {
+		a, b = b, nil
+	}
+	ra, rb, exit := enterBoth(a, b)
+	values := make([]int, size(ra)+size(rb))
+	export(values, 0, ra)
+	export(values[size(ra):], 0, rb)
+	exit()
+	t := a.derive(nil)
+	v := t.enter()
+	t.root = build(values, false, t.opts.pool)
+	t.leave(v)
+	return t
+}
+
+/*
+Returns 2 new treaps with elements of the treap, same as `Split()`, but the treap is left intact:
+
+	1st: treap index <= given index
+	2nd: treap index >  given index
+
+Values are copied and built into new treaps in a linear time.
+Both resulted treaps use configuration of the treap, same as results of `Split()`.
+
+	if t == nil: return 2 empty treaps
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func SplitCopy(t *Treap, index int) (tl Treap, tr Treap) {
+	if t == nil {
+		return
+	}
+	v := t.enter()
+	values := make([]int, size(t.root))
+	export(values, 0, t.root)
+	t.exit(v)
+	index = max(-1, min(index, len(values)-1))
+	tl, tr = t.derive(nil), t.derive(nil)
+	vl, vr := tl.enter(), tr.enter()
+	tl.root = build(values[:index+1], false, tl.opts.pool)
+	tr.root = build(values[index+1:], false, tr.opts.pool)
+	tl.leave(vl)
+	tr.leave(vr)
+	return tl, tr
+}
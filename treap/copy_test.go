@@ -0,0 +1,57 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	a, b := New(1, 2, 3), New(4, 5)
+	a.ReverseRange(0, 2)
+	a.SetLimit(10)
+	c := Concat(&a, &b)
+	if got := c.Export(); !slices.Equal(got, []int{3, 2, 1, 4, 5}) {
+		t.Fatalf("Concat() = %v", got)
+	}
+	checkShape(t, c.root)
+	if c.Limit() != 10 {
+		t.Errorf("Concat() has limit %d, want the limit 10 of the 1st treap", c.Limit())
+	}
+	c.Set(0, 0)
+	if !slices.Equal(a.Export(), []int{3, 2, 1}) || !slices.Equal(b.Export(), []int{4, 5}) {
+		t.Errorf("Concat() changed the treaps to %v and %v", a.Export(), b.Export())
+	}
+	if self := Concat(&b, &b); !slices.Equal(self.Export(), []int{4, 5, 4, 5}) {
+		t.Errorf("Concat() of the same treap = %v", self.Export())
+	}
+	if got := Concat(nil, &b); !slices.Equal(got.Export(), []int{4, 5}) {
+		t.Errorf("Concat(nil, b) = %v", got.Export())
+	}
+	if got := Concat(nil, nil); got.Size() != 0 {
+		t.Errorf("Concat(nil, nil) has size %d", got.Size())
+	}
+}
+
+func TestSplitCopy(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.SetLimit(8)
+	for index := -2; index <= 6; index++ {
+		l, r := SplitCopy(&tr, index)
+		cut := max(0, min(index+1, 5))
+		if !slices.Equal(l.Export(), clamped([]int{1, 2, 3, 4, 5}, 0, cut-1)) || !slices.Equal(r.Export(), clamped([]int{1, 2, 3, 4, 5}, cut, 4)) {
+			t.Errorf("SplitCopy(%d) = %v, %v", index, l.Export(), r.Export())
+		}
+		if l.Limit() != 8 || r.Limit() != 8 {
+			t.Errorf("SplitCopy(%d) has limits %d and %d, want 8", index, l.Limit(), r.Limit())
+		}
+		l.PushBack(0)
+		checkShape(t, l.root)
+		checkShape(t, r.root)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("SplitCopy() changed the treap to %v", got)
+	}
+	if l, r := SplitCopy(nil, 0); l.Size() != 0 || r.Size() != 0 {
+		t.Error("SplitCopy(nil) returns non-empty treaps")
+	}
+}
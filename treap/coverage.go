@@ -0,0 +1,334 @@
+package treap
+
+import (
+	"cmp"
+	"slices"
+)
+
+/*
+Run of consecutive elements covered by the same amount of annotations,
+node of the coverage treap of the annotation layer.
+Runs are ordered by position and cover all elements of the treap, adjacent runs always differ in coverage.
+Length is amount of elements of the subtree, low is the smallest coverage of its elements,
+and lowLength is amount of elements with such coverage, so uncovered elements are counted without a traversal.
+Add is the pending change of coverage of both sons.
+*/
+type cnode struct {
+	run       int
+	cover     int
+	length    int
+	low       int
+	lowLength int
+	add       int
+	priority  int
+	tiebreak  uint64
+	lson      *cnode
+	rson      *cnode
+}
+
+/*
+Correctly initialize a run with provided length and coverage.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func newRun(run int, cover int) *cnode {
+	return &cnode{
+		run:       run,
+		cover:     cover,
+		length:    run,
+		low:       cover,
+		lowLength: run,
+		priority:  randomPriority(),
+		tiebreak:  mix(created.Add(1)),
+	}
+}
+
+/*
+Returns amount of elements of the subtree.
+
+	if n == nil: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *cnode) elements() int {
+	if n == nil {
+		return 0
+	}
+	return n.length
+}
+
+/*
+Returns amount of elements of the subtree, that have at least 1 annotation.
+
+	if n == nil: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *cnode) covered() int {
+	if n == nil {
+		return 0
+	} else if n.low == 0 {
+		return n.length - n.lowLength
+	}
+	return n.length
+}
+
+/*
+Changes coverage of all elements of the subtree by provided delta.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *cnode) shift(delta int) {
+	if n == nil {
+		return
+	}
+	n.cover += delta
+	n.low += delta
+	n.add += delta
+}
+
+/*
+Pushes pending change of coverage into the sons.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *cnode) push() {
+	if n.add != 0 {
+		n.lson.shift(n.add)
+		n.rson.shift(n.add)
+		n.add = 0
+	}
+}
+
+/*
+Recalculate length and the smallest coverage of the subtree.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func csync(n *cnode) {
+	n.length, n.low, n.lowLength = n.run, n.cover, n.run
+	for _, son := range [2]*cnode{n.lson, n.rson} {
+		if son == nil {
+			continue
+		}
+		n.length += son.length
+		if son.low < n.low {
+			n.low, n.lowLength = son.low, son.lowLength
+		} else if son.low == n.low {
+			n.lowLength += son.lowLength
+		}
+	}
+}
+
+/*
+Merges 2 coverage subtrees, runs of the 1st one are placed before runs of the 2nd one.
+Runs are not joined, use `cjoin()` to keep adjacent runs different.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func cmerge(n1 *cnode, n2 *cnode) *cnode {
+	if n1 == nil {
+		return n2
+	} else if n2 == nil {
+		return n1
+	}
+	if n1.priority > n2.priority || (n1.priority == n2.priority && n1.tiebreak > n2.tiebreak) {
+		n1.push()
+		n1.rson = cmerge(n1.rson, n2)
+		csync(n1)
+		return n1
+	}
+	n2.push()
+	n2.lson = cmerge(n1, n2.lson)
+	csync(n2)
+	return n2
+}
+
+/*
+Splits coverage subtree into the 1st elements of provided amount and all others.
+Run containing both the last element of the 1st part and the 1st element of the 2nd part is cut in 2 runs.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func csplit(n *cnode, count int) (l *cnode, r *cnode) {
+	if n == nil {
+		return nil, nil
+	}
+	n.push()
+	before := n.lson.elements()
+	if count <= before {
+		l, n.lson = csplit(n.lson, count)
+		csync(n)
+		return l, n
+	} else if count >= before+n.run {
+		n.rson, r = csplit(n.rson, count-before-n.run)
+		csync(n)
+		return n, r
+	}
+	cut := newRun(before+n.run-count, n.cover)
+	n.run = count - before
+	r, n.rson = n.rson, nil
+	csync(n)
+	return n, cmerge(cut, r)
+}
+
+/*
+Extends the run containing the element with the given index by provided amount of elements,
+if the run has provided coverage. Reports whether the run was extended.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func cgrow(n *cnode, index int, count int, cover int) bool {
+	if n == nil {
+		return false
+	}
+	n.push()
+	before := n.lson.elements()
+	ok := false
+	if index < before {
+		ok = cgrow(n.lson, index, count, cover)
+	} else if index < before+n.run {
+		ok = n.cover == cover
+		if ok {
+			n.run += count
+		}
+	} else {
+		ok = cgrow(n.rson, index-before-n.run, count, cover)
+	}
+	if ok {
+		csync(n)
+	}
+	return ok
+}
+
+/*
+Detaches the 1st run of the subtree. Returns the subtree without it and the run itself.
+
+	if n == nil: return nil, nil
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func cpopFirst(n *cnode) (rest *cnode, first *cnode) {
+	if n == nil {
+		return nil, nil
+	}
+	n.push()
+	if n.lson == nil {
+		rest, n.rson = n.rson, nil
+		csync(n)
+		return rest, n
+	}
+	n.lson, first = cpopFirst(n.lson)
+	csync(n)
+	return n, first
+}
+
+/*
+Returns coverage of the element with the given index, index must be inside of the subtree.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func ccover(n *cnode, index int) int {
+	for {
+		n.push()
+		before := n.lson.elements()
+		if index < before {
+			n = n.lson
+		} else if index < before+n.run {
+			return n.cover
+		} else {
+			index -= before + n.run
+			n = n.rson
+		}
+	}
+}
+
+/*
+Merges 2 coverage subtrees same as `cmerge()`,
+but the last run of the 1st one and the 1st run of the 2nd one are joined, if their coverage is same.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func cjoin(n1 *cnode, n2 *cnode) *cnode {
+	if n1 == nil || n2 == nil {
+		return cmerge(n1, n2)
+	}
+	if ccover(n1, n1.length-1) == ccover(n2, 0) {
+		rest, first := cpopFirst(n2)
+		cgrow(n1, n1.length-1, first.run, first.cover)
+		n2 = rest
+	}
+	return cmerge(n1, n2)
+}
+
+/*
+Returns amount of elements with at least 1 annotation among the 1st elements of provided amount.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the coverage treap;
+*/
+func ccovered(n *cnode, count int) int {
+	result := 0
+	for n != nil && count > 0 {
+		if count >= n.length {
+			return result + n.covered()
+		}
+		n.push()
+		before := n.lson.elements()
+		if count <= before {
+			n = n.lson
+			continue
+		}
+		result += n.lson.covered()
+		if n.cover > 0 {
+			result += min(n.run, count-before)
+		}
+		count -= before + n.run
+		n = n.rson
+	}
+	return result
+}
+
+/*
+Builds coverage subtree of provided amount of elements, covered by provided half-open ranges,
+offsets of which are counted from the 1st element of the subtree.
+Adjacent runs of the result have different coverage.
+
+# Time complexity:
+  - Loglinear - time complexity is equal to amount of ranges multiplied by its logarithm;
+*/
+func buildRuns(count int, ranges [][2]int) *cnode {
+	type bound struct{ at, delta int }
+	bounds := make([]bound, 0, 2*len(ranges)+1)
+	for _, r := range ranges {
+		bounds = append(bounds, bound{r[0], 1}, bound{r[1], -1})
+	}
+	bounds = append(bounds, bound{count, 0})
+	slices.SortFunc(bounds, func(b1 bound, b2 bound) int {
+		return cmp.Compare(b1.at, b2.at)
+	})
+	var root *cnode
+	at, cover := 0, 0
+	for _, b := range bounds {
+		if b.at > at {
+			root = cjoin(root, newRun(b.at-at, cover))
+			at = b.at
+		}
+		cover += b.delta
+	}
+	return root
+}
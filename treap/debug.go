@@ -0,0 +1,151 @@
+//go:build treapdebug
+
+package treap
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+/*
+Generation embedded into every treap and node.
+Root node is stamped with the generation of the treap that owns it,
+treap is valid only if its generation is equal to the generation of its root.
+All other nodes keep 0.
+
+Field exists only in debug builds, see `release.go`.
+*/
+type stamp struct {
+	gen uint64
+}
+
+/*
+State of the treap captured before an operation.
+*/
+type visit struct {
+	root  *node
+	gen   uint64
+	guard *guard
+}
+
+/*
+Last used generation.
+Shared by all treaps, so it is changed atomically.
+*/
+var generation atomic.Uint64
+
+/*
+Called before every operation on the treap.
+Panics if the treap holds nodes that were restamped by another treap,
+meaning they were absorbed by `Merge()`, `Split()` or a copy of this treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) enter() visit {
+	t.opts.guard.acquire()
+	if t.root != nil && t.root.gen != t.gen {
+		panic(fmt.Errorf("%w: treap has generation %d, but its nodes are owned by generation %d",
+			ErrConsumedTreap, t.gen, t.root.gen))
+	}
+	return visit{t.root, t.gen, t.opts.guard}
+}
+
+/*
+Called after every mutating operation on the treap.
+Stamps the root with a new generation and clears the stamp of the old root,
+so every other treap that still holds old nodes is detected as stale.
+After that checks all invariants of the treap.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap, since all invariants are checked;
+*/
+func (t *Treap) leave(v visit) {
+	if v.root != nil && v.root != t.root && v.root.gen == v.gen {
+		v.root.gen = 0
+	}
+	if t.root != nil {
+		t.gen = generation.Add(1)
+		t.root.gen = t.gen
+	}
+	verify(t.root)
+	v.guard.release()
+}
+
+/*
+Called after every read-only operation on the treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) exit(v visit) {
+	v.guard.release()
+}
+
+/*
+Panics if any invariant of the subtree is broken:
+  - stored size is not equal to the actual amount of nodes;
+  - augmented node has a son without augmentations;
+  - stored sum is not equal to the sum of the node and its sons;
+  - stored minimum or maximum is not equal to the one of the node and its sons;
+  - stored hashes are not equal to the hashes of the node and its sons;
+  - child outranks its parent (heap order).
+
+Pending changes of the node are pushed into copies of its sons before the check.
+Returns actual size of the subtree.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func verify(n *node) int {
+	if n == nil {
+		return 0
+	}
+	for _, son := range [2]*node{n.lson, n.rson} {
+		if son != nil && outranks(son, n) {
+			panic(fmt.Sprintf("treap: heap order broken: child priority %d outranks parent priority %d",
+				son.priority, n.priority))
+		} else if son != nil && n.aug != nil && son.aug == nil {
+			panic("treap: augmentations broken: augmented node has a son without augmentations")
+		}
+	}
+	size := 1 + verify(n.lson) + verify(n.rson)
+	if size != n.size {
+		panic(fmt.Sprintf("treap: size broken: node stores %d, but subtree has %d nodes", n.size, size))
+	} else if n.aug == nil {
+		return size
+	}
+	// Node and its sons are copied, so pending changes are pushed without changing the treap.
+	c := clone(n)
+	c.lson, c.rson = clone(n.lson), clone(n.rson)
+	push(c)
+	sync(c)
+	if a, b := n.aug, c.aug; a.sum != b.sum {
+		panic(fmt.Sprintf("treap: sum broken: node stores %d, but subtree has %d", a.sum, b.sum))
+	} else if a.min != b.min || a.max != b.max {
+		panic(fmt.Sprintf("treap: bounds broken: node stores [%d, %d], but subtree has [%d, %d]", a.min, a.max, b.min, b.max))
+	} else if a.hash != b.hash || a.rhash != b.rhash || a.pow != b.pow || a.geo != b.geo {
+		panic(fmt.Sprintf("treap: hash broken: node stores %#x, but subtree has %#x", a.hash, b.hash))
+	}
+	return size
+}
+
+/*
+Returns copy of the node together with its augmentations, sons are shared.
+
+	if n == nil: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func clone(n *node) *node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	if n.aug != nil {
+		a := *n.aug
+		c.aug = &a
+	}
+	return &c
+}
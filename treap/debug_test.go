@@ -0,0 +1,129 @@
+//go:build treapdebug
+
+package treap
+
+import (
+	"errors"
+	"strings"
+	gosync "sync"
+	"testing"
+)
+
+func TestStaleCopyPanics(t *testing.T) {
+	tr := New(1, 2, 3)
+	stale := tr
+	tr.PushBack(4)
+	r := recovered(func() { stale.PushBack(5) })
+	if err, ok := r.(error); !ok || !errors.Is(err, ErrConsumedTreap) {
+		t.Fatalf("mutation of a stale copy panicked with %v, want ErrConsumedTreap", r)
+	}
+}
+
+func TestStaleRootPanics(t *testing.T) {
+	// Old root stays inside of the treap, but is no longer its root.
+	for range 20 {
+		tr := New()
+		for i := range 50 {
+			tr.PushBack(i)
+		}
+		stale := tr
+		old := tr.root
+		for tr.root == old {
+			tr.PushFront(0)
+		}
+		if r := recovered(func() { stale.Find(0) }); r == nil {
+			t.Fatal("read of a stale copy did not panic")
+		}
+	}
+}
+
+func TestGenerationsOfSeparateTreaps(t *testing.T) {
+	var wg gosync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tr := New()
+			for i := range 200 {
+				tr.Insert(i/2, i)
+				tl, tr2 := Split(&tr, i/3)
+				tr = Merge(&tl, &tr2)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGenerationOnlyOnRoot(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.Delete(2)
+	w := walker{}
+	w.seek(tr.root, 0, false)
+	for n := w.next(); n != nil; n = w.next() {
+		if n != tr.root && n.gen != 0 {
+			t.Fatalf("inner node keeps generation %d", n.gen)
+		}
+	}
+	if tr.root.gen != tr.gen {
+		t.Fatalf("root has generation %d, treap has %d", tr.root.gen, tr.gen)
+	}
+}
+
+func TestVerifyReportsBrokenSize(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.root.size++
+	r := recovered(func() { verify(tr.root) })
+	if s, ok := r.(string); !ok || !strings.Contains(s, "size broken") {
+		t.Fatalf("broken size was reported as %v", r)
+	}
+}
+
+func TestVerifyReportsBrokenInvariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		corrupt func(tr *Treap)
+	}{
+		{"heap order", "heap order broken", func(tr *Treap) {
+			tr.root.lson.priority = tr.root.priority + 1
+		}},
+		{"sum", "sum broken", func(tr *Treap) {
+			tr.root.aug.sum++
+		}},
+		{"bounds", "bounds broken", func(tr *Treap) {
+			tr.root.aug.max = 100
+		}},
+		{"hash", "hash broken", func(tr *Treap) {
+			tr.root.aug.hash++
+		}},
+		{"augmentations", "augmentations broken", func(tr *Treap) {
+			tr.root.lson.aug = nil
+		}},
+	}
+	for _, tt := range tests {
+		tr := New(1, 2, 3, 4, 5, 6, 7, 8)
+		tr.SetAugmented(true)
+		for tr.root.lson == nil {
+			tr = New(1, 2, 3, 4, 5, 6, 7, 8)
+			tr.SetAugmented(true)
+		}
+		verify(tr.root)
+		tt.corrupt(&tr)
+		r := recovered(func() { verify(tr.root) })
+		if s, ok := r.(string); !ok || !strings.Contains(s, tt.want) {
+			t.Errorf("broken %s was reported as %v", tt.name, r)
+		}
+	}
+}
+
+func TestMutationVerifiesTreap(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5, 6, 7, 8)
+	for tr.root.rson == nil {
+		tr = New(1, 2, 3, 4, 5, 6, 7, 8)
+	}
+	// Right son is not on the path to the 1st element, so the mutation does not repair it.
+	tr.root.rson.priority = tr.root.priority + 1
+	if r := recovered(func() { tr.Set(0, 5) }); r == nil {
+		t.Fatal("mutation of a broken treap did not panic")
+	}
+}
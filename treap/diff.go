@@ -0,0 +1,489 @@
+package treap
+
+import "cmp"
+
+/*
+Returns the shortest edit script that turns treap a into treap b.
+Operations must be applied in order by `Apply()`, indexes are exact.
+Consecutive deletions and insertions are joined into single operations.
+
+Common prefix and suffix are skipped by walking both treaps,
+only the rest is compared by the Myers diff algorithm.
+Treaps are not changed.
+
+	if a == nil or b == nil: nil treap is treated as empty
+
+# Time complexity:
+  - Linear - time complexity is equal to total size multiplied by amount of edits (plus height of the treaps);
+*/
+func EditScript(a *Treap, b *Treap) []Op {
+	if a == b {
+		return nil
+	}
+	var ra, rb *node
+	if a != nil {
+		v := a.enter()
+		defer a.exit(v)
+		ra = a.root
+	}
+	if b != nil {
+		v := b.enter()
+		defer b.exit(v)
+		rb = b.root
+	}
+	n, m := size(ra), size(rb)
+	prefix := commonRun(ra, rb, 0, 0, min(n, m), false)
+	suffix := commonRun(ra, rb, n-1, m-1, min(n, m)-prefix, true)
+
+	// Only the middle parts are copied, since Myers diff needs random access.
+	x := collect(ra, prefix, n-suffix)
+	y := collect(rb, prefix, m-suffix)
+	var ops []Op
+	for _, e := range myers(x, y) {
+		index := prefix + e.y
+		last := len(ops) - 1
+		if e.insert {
+			if last >= 0 && ops[last].Kind == OpInsert && ops[last].Index+len(ops[last].Values) == index {
+				ops[last].Values = append(ops[last].Values, y[e.y])
+			} else {
+				ops = append(ops, InsertOp(index, y[e.y]))
+			}
+		} else {
+			if last >= 0 && ops[last].Kind == OpDelete && ops[last].Index == index {
+				ops[last].Count++
+			} else {
+				ops = append(ops, DeleteOp(index, 1))
+			}
+		}
+	}
+	return ops
+}
+
+/*
+Reports whether both treaps have the same values in the same order.
+Sizes and hashes are compared first, see `Hash()`,
+then treaps are walked in pairs and the walk stops on the first difference.
+
+	if a == nil or b == nil: nil treap is treated as empty
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the common prefix plus height of the treaps;
+  - Constant - if sizes or hashes of the treaps are different;
+*/
+func Equal(a *Treap, b *Treap) bool {
+	if a == b {
+		return true
+	}
+	ra, rb, exit := enterBoth(a, b)
+	defer exit()
+	n := size(ra)
+	if n != size(rb) {
+		return false
+	} else if augmented(ra) && augmented(rb) && hash(ra) != hash(rb) {
+		return false
+	}
+	return commonRun(ra, rb, 0, 0, n, false) == n
+}
+
+/*
+Reports whether both treaps have the same values in the same order by the provided comparator, same as slices.EqualFunc.
+Hashes cannot be used with a custom comparator, so only sizes are compared before the walk in pairs.
+
+	if a == nil or b == nil: nil treap is treated as empty
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the common prefix plus height of the treaps;
+  - Constant - if sizes of the treaps are different;
+*/
+func EqualFunc(a *Treap, b *Treap, eq func(x int, y int) bool) bool {
+	ra, rb, exit := enterBoth(a, b)
+	defer exit()
+	if size(ra) != size(rb) {
+		return false
+	}
+	na, _ := mismatch(ra, rb, func(x int, y int) bool { return !eq(x, y) })
+	return na == nil
+}
+
+/*
+Compares treaps lexicographically, same as slices.Compare.
+Result is 0 if treaps are equal, -1 if a is less than b and +1 if a is greater than b.
+Treaps are walked in pairs and the walk stops on the first difference.
+
+	if a == nil or b == nil: nil treap is treated as empty
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the common prefix plus height of the treaps;
+*/
+func Compare(a *Treap, b *Treap) int {
+	if a == b {
+		return 0
+	}
+	ra, rb, exit := enterBoth(a, b)
+	defer exit()
+	na, nb := mismatch(ra, rb, func(x int, y int) bool { return x != y })
+	return order(na, nb, cmp.Compare[int])
+}
+
+/*
+Compares treaps lexicographically by the provided comparator, same as slices.CompareFunc.
+Result is the result of the comparator for the 1st pair of values, for which it is not 0,
+otherwise -1 if a is shorter than b, +1 if a is longer than b, and 0 if they have the same size.
+
+	if a == nil or b == nil: nil treap is treated as empty
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the common prefix plus height of the treaps;
+*/
+func CompareFunc(a *Treap, b *Treap, compare func(x int, y int) int) int {
+	ra, rb, exit := enterBoth(a, b)
+	defer exit()
+	result := 0
+	na, nb := mismatch(ra, rb, func(x int, y int) bool {
+		result = compare(x, y)
+		return result != 0
+	})
+	if na != nil && nb != nil {
+		return result
+	}
+	return order(na, nb, compare)
+}
+
+/*
+Returns result of the comparison of the 1st mismatching nodes, see `mismatch()`.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func order(na *node, nb *node, compare func(x int, y int) int) int {
+	if na == nil && nb == nil {
+		return 0
+	} else if na == nil {
+		return -1
+	} else if nb == nil {
+		return +1
+	}
+	return compare(na.value, nb.value)
+}
+
+/*
+Walks both subtrees in pairs and returns the 1st pair of nodes, whose values differ.
+If one subtree ends before, its node is nil.
+
+	if no pair differs and subtrees have the same size: return nil, nil
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the common prefix plus height of the subtrees;
+*/
+func mismatch(ra *node, rb *node, differ func(x int, y int) bool) (na *node, nb *node) {
+	var wa, wb walker
+	wa.seek(ra, 0, false)
+	wb.seek(rb, 0, false)
+	for {
+		na, nb = wa.next(), wb.next()
+		if na == nil || nb == nil || differ(na.value, nb.value) {
+			return na, nb
+		}
+	}
+}
+
+/*
+Starts read-only operation on both treaps and returns their roots
+together with the function that finishes the operation.
+
+	if a == nil or b == nil: nil treap has nil root
+	if a == b: treap is entered once and both roots are its root
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func enterBoth(a *Treap, b *Treap) (ra *node, rb *node, exit func()) {
+	var va, vb visit
+	if a != nil {
+		va = a.enter()
+		ra = a.root
+	}
+	if a == b {
+		b, rb = nil, ra
+	} else if b != nil {
+		vb = b.enter()
+		rb = b.root
+	}
+	return ra, rb, func() {
+		if a != nil {
+			a.exit(va)
+		}
+		if b != nil {
+			b.exit(vb)
+		}
+	}
+}
+
+/*
+Index range of the new treap, that differs from the old treap.
+Left and Right are inclusive indexes of the new treap,
+Removed is amount of elements of the old treap replaced by the range.
+If elements were only deleted, Right is equal to Left-1 and the range marks the place of deletion.
+*/
+type Range struct {
+	Left    int
+	Right   int
+	Removed int
+}
+
+/*
+Returns ranges of the new treap that differ from the old treap, in increasing order.
+Ranges are joined if they touch each other, so every range can be repainted at once.
+Both treaps are augmented, see `SetAugmented()`, so equal prefix and suffix are found by comparing hashes
+of whole subtrees without visiting their elements, and only the rest is compared by the Myers diff algorithm.
+Hashes are computed modulo a prime with a random base, see `Hash()`, so no chosen input is known to collide,
+but a change of n values is still missed with probability of at most n / 2^61.
+Values of the treaps are not changed.
+
+	if old == nil or new == nil: nil treap is treated as empty
+
+# Time complexity:
+  - Logarithmic - squared height of the treaps, if the treaps differ only in 1 range;
+  - Linear - time complexity is equal to size of the differing middle parts multiplied by amount of edits;
+*/
+func ChangedRanges(old *Treap, new *Treap) []Range {
+	ra, rb, exit := enterBoth(old, new)
+	defer exit()
+	augmentAll(ra)
+	augmentAll(rb)
+	n, m := size(ra), size(rb)
+	if n == m && hash(ra) == hash(rb) {
+		return nil
+	}
+	prefix := longest(min(n, m), func(count int) bool {
+		return prefixHash(ra, count) == prefixHash(rb, count)
+	})
+	suffix := longest(min(n, m)-prefix, func(count int) bool {
+		return suffixHash(ra, count) == suffixHash(rb, count)
+	})
+
+	x := collect(ra, prefix, n-suffix)
+	y := collect(rb, prefix, m-suffix)
+	var ranges []Range
+	for _, e := range myers(x, y) {
+		index := prefix + e.y
+		last := len(ranges) - 1
+		if last < 0 || ranges[last].Right+1 < index {
+			ranges = append(ranges, Range{Left: index, Right: index - 1})
+			last++
+		}
+		if e.insert {
+			ranges[last].Right++
+		} else {
+			ranges[last].Removed++
+		}
+	}
+	return ranges
+}
+
+/*
+Returns the biggest count from 0 to limit, for which the check holds.
+Check must hold for all counts up to some point and for no count after it.
+
+# Time complexity:
+  - Logarithmic - amount of checks is equal to logarithm of the limit;
+*/
+func longest(limit int, check func(count int) bool) int {
+	lo, hi := 0, limit
+	for lo < hi {
+		mid := lo + (hi-lo+1)/2
+		if check(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
+
+/*
+Returns size of the subtree.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func size(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+/*
+Returns length of the common run of 2 treaps, but not more than limit.
+Run starts from provided indexes and goes backward if reverse is true.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the run plus height of the treaps;
+*/
+func commonRun(ra *node, rb *node, ia int, ib int, limit int, reverse bool) int {
+	var wa, wb walker
+	wa.seek(ra, ia, reverse)
+	wb.seek(rb, ib, reverse)
+	count := 0
+	for count < limit {
+		na, nb := wa.next(), wb.next()
+		if na == nil || nb == nil || na.value != nb.value {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+/*
+Returns values of the subtree with indexes from lo to hi-1.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of values plus height of the treap;
+*/
+func collect(root *node, lo int, hi int) []int {
+	if lo >= hi {
+		return nil
+	}
+	values := make([]int, 0, hi-lo)
+	var w walker
+	w.seek(root, lo, false)
+	for len(values) < hi-lo {
+		values = append(values, w.next().value)
+	}
+	return values
+}
+
+/*
+Single step of the edit script.
+Either x[x] is deleted or y[y] is inserted, y is also the index in the edited sequence.
+*/
+type edit struct {
+	insert bool
+	x      int
+	y      int
+}
+
+/*
+State of the linear space Myers diff of 2 sequences.
+Forward and backward contain the furthest reached offsets of every diagonal,
+they are shared by all steps of the recursion.
+*/
+type differ struct {
+	x        []int
+	y        []int
+	forward  []int
+	backward []int
+	edits    []edit
+}
+
+/*
+Myers diff algorithm in linear space, returns the shortest list of edits in order.
+Instead of saving states of every step, middle snake of the shortest path is found
+and both parts around it are compared recursively.
+
+# Time complexity:
+  - Linear - time complexity is equal to total length multiplied by amount of edits;
+*/
+func myers(x []int, y []int) []edit {
+	half := (len(x)+len(y)+1)/2 + 1
+	d := differ{x: x, y: y, forward: make([]int, 2*half+1), backward: make([]int, 2*half+1)}
+	d.compare(0, len(x), 0, len(y))
+	return d.edits
+}
+
+/*
+Appends edits, that turn x[x0:x1] into y[y0:y1], in order.
+Common prefix and suffix are skipped, so the rest either has an empty side or at least 2 edits,
+which are split by the middle snake.
+
+# Time complexity:
+  - Linear - time complexity is equal to total length multiplied by amount of edits;
+*/
+func (d *differ) compare(x0 int, x1 int, y0 int, y1 int) {
+	for x0 < x1 && y0 < y1 && d.x[x0] == d.y[y0] {
+		x0, y0 = x0+1, y0+1
+	}
+	for x0 < x1 && y0 < y1 && d.x[x1-1] == d.y[y1-1] {
+		x1, y1 = x1-1, y1-1
+	}
+	if x0 == x1 {
+		for j := y0; j < y1; j++ {
+			d.edits = append(d.edits, edit{insert: true, x: x0, y: j})
+		}
+		return
+	} else if y0 == y1 {
+		for i := x0; i < x1; i++ {
+			d.edits = append(d.edits, edit{insert: false, x: i, y: y0})
+		}
+		return
+	}
+	sx, sy, fx, fy := d.middle(x0, x1, y0, y1)
+	d.compare(x0, sx, y0, sy)
+	// Snake itself contains at most 1 edit, so it is solved by skipping the common parts.
+	d.compare(sx, fx, sy, fy)
+	d.compare(fx, x1, fy, y1)
+}
+
+/*
+Returns start and finish of the middle snake of the shortest path from (x0, y0) to (x1, y1).
+Paths are searched from both corners at once, until they overlap on the same diagonal.
+Forward offsets are indexes of x, backward offsets are indexes of y.
+
+# Time complexity:
+  - Linear - time complexity is equal to total length multiplied by amount of edits;
+*/
+func (d *differ) middle(x0 int, x1 int, y0 int, y1 int) (sx int, sy int, fx int, fy int) {
+	delta := (x1 - x0) - (y1 - y0)
+	odd := delta%2 != 0
+	o := len(d.forward) / 2
+	d.forward[o+1], d.backward[o+1] = x0, y1
+	for s := 0; ; s++ {
+		for k := s; k >= -s; k -= 2 {
+			var px, x int
+			if k == -s || (k != s && d.forward[o+k-1] < d.forward[o+k+1]) {
+				px = d.forward[o+k+1]
+				x = px
+			} else {
+				px = d.forward[o+k-1]
+				x = px + 1
+			}
+			y := y0 + (x - x0) - k
+			py := y
+			if s > 0 && x == px {
+				py--
+			}
+			for x < x1 && y < y1 && d.x[x] == d.y[y] {
+				x, y = x+1, y+1
+			}
+			d.forward[o+k] = x
+			if c := k - delta; odd && c >= -(s-1) && c <= s-1 && y >= d.backward[o+c] {
+				return px, py, x, y
+			}
+		}
+		for c := s; c >= -s; c -= 2 {
+			var py, y int
+			if c == -s || (c != s && d.backward[o+c-1] > d.backward[o+c+1]) {
+				py = d.backward[o+c+1]
+				y = py
+			} else {
+				py = d.backward[o+c-1]
+				y = py - 1
+			}
+			k := c + delta
+			x := x0 + (y - y0) + k
+			px := x
+			if s > 0 && y == py {
+				px++
+			}
+			for x > x0 && y > y0 && d.x[x-1] == d.y[y-1] {
+				x, y = x-1, y-1
+			}
+			d.backward[o+c] = y
+			if !odd && k >= -s && k <= s && x <= d.forward[o+k] {
+				return x, y, px, py
+			}
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// Returns length of the longest common subsequence of the values.
+func lcs(x []int, y []int) int {
+	row := make([]int, len(y)+1)
+	for i := range x {
+		prev := 0
+		for j := range y {
+			next := row[j+1]
+			if x[i] == y[j] {
+				row[j+1] = prev + 1
+			} else {
+				row[j+1] = max(row[j+1], row[j])
+			}
+			prev = next
+		}
+	}
+	return row[len(y)]
+}
+
+// Returns random values and their copy with a few random edits.
+func editedPair(r *rand.Rand, size int, edits int) (x []int, y []int) {
+	x = make([]int, r.IntN(size+1))
+	for i := range x {
+		x[i] = r.IntN(4)
+	}
+	y = slices.Clone(x)
+	for range r.IntN(edits + 1) {
+		i := r.IntN(len(y) + 1)
+		if r.IntN(2) == 0 || i == len(y) {
+			y = slices.Insert(y, i, r.IntN(4))
+		} else {
+			y = slices.Delete(y, i, i+1)
+		}
+	}
+	return x, y
+}
+
+func TestMyersIsShortest(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	for range 2000 {
+		x, y := editedPair(r, 30, 8)
+		if r.IntN(4) == 0 {
+			y = make([]int, r.IntN(30))
+			for i := range y {
+				y[i] = r.IntN(4)
+			}
+		}
+		edits := myers(x, y)
+		if want := len(x) + len(y) - 2*lcs(x, y); len(edits) != want {
+			t.Fatalf("myers(%v, %v) has %d edits, want %d", x, y, len(edits), want)
+		}
+	}
+}
+
+func TestEditScript(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	for range 1000 {
+		x, y := editedPair(r, 40, 6)
+		a, b := New(x...), New(y...)
+		ops := EditScript(&a, &b)
+		for _, op := range ops {
+			if err := a.Apply(op); err != nil {
+				t.Fatalf("Apply(%v) = %v", op, err)
+			}
+		}
+		if got := a.Export(); !slices.Equal(got, y) {
+			t.Fatalf("edit script of %v and %v gives %v", x, y, got)
+		}
+	}
+}
+
+func TestEditScriptJoinsOperations(t *testing.T) {
+	a, b := New(1, 2, 3, 4, 5, 6), New(1, 7, 8, 9, 6)
+	b.ReverseRange(1, 3)
+	want := []Op{DeleteOp(1, 4), InsertOp(1, 9, 8, 7)}
+	got := EditScript(&a, &b)
+	if len(got) != len(want) {
+		t.Fatalf("EditScript() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Kind != want[i].Kind || got[i].Index != want[i].Index || got[i].Count != want[i].Count || !slices.Equal(got[i].Values, want[i].Values) {
+			t.Fatalf("EditScript() = %v, want %v", got, want)
+		}
+	}
+	if !slices.Equal(a.Export(), []int{1, 2, 3, 4, 5, 6}) || !slices.Equal(b.Export(), []int{1, 9, 8, 7, 6}) {
+		t.Errorf("EditScript() changed the treaps to %v and %v", a.Export(), b.Export())
+	}
+}
+
+func TestEditScriptOfNilTreaps(t *testing.T) {
+	a := New(1, 2)
+	if got := EditScript(&a, &a); got != nil {
+		t.Errorf("EditScript() of the same treap = %v", got)
+	}
+	if got := EditScript(nil, &a); len(got) != 1 || got[0].Kind != OpInsert || !slices.Equal(got[0].Values, []int{1, 2}) {
+		t.Errorf("EditScript(nil, a) = %v", got)
+	}
+	if got := EditScript(&a, nil); len(got) != 1 || got[0].Kind != OpDelete || got[0].Count != 2 {
+		t.Errorf("EditScript(a, nil) = %v", got)
+	}
+}
+
+// Returns changed ranges built from the edit script.
+func rangesOf(ops []Op) []Range {
+	var ranges []Range
+	for _, op := range ops {
+		last := len(ranges) - 1
+		if last < 0 || ranges[last].Right+1 < op.Index {
+			ranges = append(ranges, Range{Left: op.Index, Right: op.Index - 1})
+			last++
+		}
+		if op.Kind == OpInsert {
+			ranges[last].Right += len(op.Values)
+		} else {
+			ranges[last].Removed += op.Count
+		}
+	}
+	return ranges
+}
+
+func TestChangedRanges(t *testing.T) {
+	old, new := New(1, 2, 3, 4, 5, 6, 7), New(1, 9, 9, 3, 4, 6, 7, 8)
+	want := []Range{{Left: 1, Right: 2, Removed: 1}, {Left: 5, Right: 4, Removed: 1}, {Left: 7, Right: 7}}
+	if got := ChangedRanges(&old, &new); !slices.Equal(got, want) {
+		t.Errorf("ChangedRanges() = %v, want %v", got, want)
+	}
+	if got := ChangedRanges(&old, &old); got != nil {
+		t.Errorf("ChangedRanges() of the same treap = %v", got)
+	}
+	if got := ChangedRanges(nil, &old); !slices.Equal(got, []Range{{Left: 0, Right: 6}}) {
+		t.Errorf("ChangedRanges(nil, old) = %v", got)
+	}
+
+	r := rand.New(rand.NewPCG(9, 10))
+	for range 1000 {
+		x, y := editedPair(r, 40, 6)
+		a, b := New(x...), New(y...)
+		if got, want := ChangedRanges(&a, &b), rangesOf(EditScript(&a, &b)); !slices.Equal(got, want) {
+			t.Fatalf("ChangedRanges(%v, %v) = %v, want %v", x, y, got, want)
+		}
+	}
+}
+
+func TestChangedRangesOfLargeTreaps(t *testing.T) {
+	const n = 1 << 16
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i
+	}
+	old, new := New(values...), New(values...)
+	new.Set(n/3, -1)
+	new.Insert(n/2, -2)
+	want := []Range{{Left: n / 3, Right: n / 3, Removed: 1}, {Left: n / 2, Right: n / 2}}
+	if got := ChangedRanges(&old, &new); !slices.Equal(got, want) {
+		t.Errorf("ChangedRanges() = %v, want %v", got, want)
+	}
+}
+
+func TestChangedRangesOfThueMorse(t *testing.T) {
+	x, y := thueMorse(1 << 10)
+	old, new := New(x...), New(y...)
+	want := rangesOf(EditScript(&old, &new))
+	if len(want) == 0 {
+		t.Fatal("EditScript() found no difference")
+	}
+	if got := ChangedRanges(&old, &new); !slices.Equal(got, want) {
+		t.Errorf("ChangedRanges() = %v, want %v", got, want)
+	}
+	if Equal(&old, &new) {
+		t.Error("Equal() of Thue–Morse sequence and its complement")
+	}
+}
+
+func TestEqualAndCompare(t *testing.T) {
+	r := rand.New(rand.NewPCG(21, 22))
+	for range 2000 {
+		x, y := editedPair(r, 12, 2)
+		a, b := New(x...), New(y...)
+		if r.IntN(2) == 0 {
+			a.SetAugmented(true)
+		}
+		if r.IntN(2) == 0 && len(x) > 1 {
+			a.ReverseRange(0, len(x)-1)
+			a.ReverseRange(0, len(x)-1)
+		}
+		if got, want := Equal(&a, &b), slices.Equal(x, y); got != want {
+			t.Fatalf("Equal(%v, %v) = %v, want %v", x, y, got, want)
+		}
+		if got, want := Compare(&a, &b), slices.Compare(x, y); got != want {
+			t.Fatalf("Compare(%v, %v) = %d, want %d", x, y, got, want)
+		}
+	}
+	a, empty := New(1), New()
+	if !Equal(nil, &empty) || Equal(&a, nil) || Compare(nil, &a) != -1 || Compare(&a, &a) != 0 {
+		t.Error("nil treap is not treated as empty")
+	}
+}
+
+func BenchmarkChangedRanges(b *testing.B) {
+	const n = 1 << 16
+	old := New(make([]int, n)...)
+	new := New(make([]int, n)...)
+	new.Set(n/2, 1)
+	ChangedRanges(&old, &new)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		ChangedRanges(&old, &new)
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	eq := func(x int, y int) bool { return x%2 == y%2 }
+	r := rand.New(rand.NewPCG(57, 58))
+	for range 1000 {
+		x, y := editedPair(r, 12, 2)
+		a, b := New(x...), New(y...)
+		if len(x) > 1 && r.IntN(2) == 0 {
+			a.ReverseRange(0, len(x)-1)
+			slices.Reverse(x)
+		}
+		if got, want := EqualFunc(&a, &b, eq), slices.EqualFunc(x, y, eq); got != want {
+			t.Fatalf("EqualFunc(%v, %v) = %v, want %v", x, y, got, want)
+		}
+	}
+	a, b := New(1, 2, 3), New(3, 4, 5)
+	if !EqualFunc(&a, &b, eq) || !EqualFunc(&a, &a, eq) || EqualFunc(&a, nil, eq) {
+		t.Error("EqualFunc() compares more than the comparator")
+	}
+}
+
+func TestCompareFunc(t *testing.T) {
+	// Comparator returns the difference, not its sign, so the result is passed through.
+	compare := func(x int, y int) int { return x/2 - y/2 }
+	r := rand.New(rand.NewPCG(59, 60))
+	for range 1000 {
+		x, y := editedPair(r, 12, 2)
+		a, b := New(x...), New(y...)
+		if got, want := CompareFunc(&a, &b, compare), slices.CompareFunc(x, y, compare); got != want {
+			t.Fatalf("CompareFunc(%v, %v) = %d, want %d", x, y, got, want)
+		}
+	}
+	a, b := New(1, 3), New(0, 2, 9)
+	if got := CompareFunc(&a, &b, compare); got != -1 {
+		t.Errorf("CompareFunc() of a shorter treap with equal prefix = %d, want -1", got)
+	}
+	if got := CompareFunc(&b, &a, func(x int, y int) int { return 10 * (x - y) }); got != -10 {
+		t.Errorf("CompareFunc() = %d, want -10", got)
+	}
+	if CompareFunc(nil, nil, compare) != 0 || CompareFunc(&a, nil, compare) != +1 {
+		t.Error("nil treap is not treated as empty")
+	}
+}
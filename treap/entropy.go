@@ -0,0 +1,103 @@
+package treap
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"sync/atomic"
+)
+
+/*
+Source of random bits for priorities of the nodes and random queries like `SampleWeighted()`.
+Has the same method as rand.Source of math/rand/v2, so its generators can be used directly.
+Source must be safe for concurrent use, if treaps are used by several goroutines.
+*/
+type Entropy interface {
+	Uint64() uint64
+}
+
+/*
+Tiny generator used by default (SplitMix64).
+State is advanced atomically, so it is safe for concurrent use without locks.
+*/
+type splitmix struct {
+	state atomic.Uint64
+}
+
+/*
+Returns next random word.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *splitmix) Uint64() uint64 {
+	return mix(s.state.Add(0x9e3779b97f4a7c15))
+}
+
+/*
+Default source, seeded by the runtime once per process.
+*/
+var defaultEntropy = newSplitmix(maphash.Bytes(maphash.MakeSeed(), nil))
+
+/*
+Current source of all random bits of the package.
+*/
+var entropy Entropy = defaultEntropy
+
+/*
+Returns default generator started from the seed.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func newSplitmix(seed uint64) *splitmix {
+	s := &splitmix{}
+	s.state.Store(seed)
+	return s
+}
+
+/*
+Replaces source of random bits for every treap in the package.
+Package does not use global functions of math/rand, so providing a seeded source
+makes shapes of all treaps reproducible, e.g. in deterministic simulations.
+Source must be set before treaps are used, it is not synchronized with running operations.
+
+	if e == nil: restore default source
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func SetEntropy(e Entropy) {
+	if e == nil {
+		e = defaultEntropy
+	}
+	entropy = e
+}
+
+/*
+Returns random priority, non-negative as rand.Int() of math/rand does.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func randomPriority() int {
+	return int(uint(entropy.Uint64()) >> 1)
+}
+
+/*
+Returns uniformly random number from 0 to n-1, n must be positive.
+Uses multiplication with rejection of the biased low part (Lemire's method).
+
+# Time complexity:
+  - Constant - expected, rejection happens with probability less than n / 2^64;
+*/
+func randomN(n int) int {
+	bound := uint64(n)
+	hi, lo := bits.Mul64(entropy.Uint64(), bound)
+	if lo < bound {
+		threshold := -bound % bound
+		for lo < threshold {
+			hi, lo = bits.Mul64(entropy.Uint64(), bound)
+		}
+	}
+	return int(hi)
+}
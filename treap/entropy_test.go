@@ -0,0 +1,66 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// Returns priorities of all nodes of the subtree.
+func prioritiesOf(n *node) []int {
+	var result []int
+	for _, n := range nodesOf(n) {
+		result = append(result, n.priority)
+	}
+	return result
+}
+
+func TestSetEntropyIsReproducible(t *testing.T) {
+	defer SetEntropy(nil)
+	shape := func() ([]int, []int) {
+		SetEntropy(rand.NewPCG(19, 20))
+		tr := New()
+		for i := range 100 {
+			tr.Insert(i/3, i)
+		}
+		return prioritiesOf(tr.root), RandomPermutation(50)
+	}
+	p1, perm1 := shape()
+	p2, perm2 := shape()
+	if !slices.Equal(p1, p2) || !slices.Equal(perm1, perm2) {
+		t.Error("treaps built with the same seeded source differ")
+	}
+	SetEntropy(nil)
+	if entropy != defaultEntropy {
+		t.Error("SetEntropy(nil) does not restore the default source")
+	}
+}
+
+// Source returning provided words in a loop.
+type words struct {
+	values []uint64
+	next   int
+}
+
+func (w *words) Uint64() uint64 {
+	value := w.values[w.next%len(w.values)]
+	w.next++
+	return value
+}
+
+func TestRandomN(t *testing.T) {
+	defer SetEntropy(nil)
+	// Word 0 is in the biased low part for n = 3 and must be rejected.
+	source := &words{values: []uint64{0, 1 << 63}}
+	SetEntropy(source)
+	if got := randomN(3); got != 1 || source.next != 2 {
+		t.Errorf("randomN(3) = %d after %d words, want 1 after 2 words", got, source.next)
+	}
+	SetEntropy(&words{values: []uint64{^uint64(0)}})
+	if got := randomN(10); got != 9 {
+		t.Errorf("randomN(10) of the largest word = %d, want 9", got)
+	}
+	if got := randomPriority(); got < 0 {
+		t.Errorf("randomPriority() = %d, want non-negative", got)
+	}
+}
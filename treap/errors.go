@@ -0,0 +1,131 @@
+package treap
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Returned when the operation would make treap bigger than its limit.
+See `SetLimit()`.
+*/
+var ErrSizeLimit = errors.New("treap: size limit exceeded")
+
+/*
+Returned when provided index or range is not inside the treap.
+Error message also contains the index and the size of the treap.
+*/
+var ErrIndexOutOfRange = errors.New("treap: index out of range")
+
+/*
+Returned when the operation requires at least 1 element, but treap is empty.
+*/
+var ErrEmptyTreap = errors.New("treap: treap is empty")
+
+/*
+Returned when the treap was already passed to `Merge()` or `Split()`.
+Such treap is left empty and must not be used afterwards.
+*/
+var ErrConsumedTreap = errors.New("treap: treap was consumed by merge or split")
+
+/*
+Returned (or used as a panic value) when treap is used by 2 goroutines at the same time.
+*/
+var ErrConcurrentModification = errors.New("treap: concurrent modification")
+
+/*
+Returned (or used as a panic value) when method is called on a nil treap.
+See `NilReceiver` variable.
+*/
+var ErrNilTreap = errors.New("treap: method called on nil treap")
+
+/*
+Checks that the element with the given index exists.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) validIndex(index int) error {
+	if t == nil {
+		if err := nilReceiver(); err != nil {
+			return err
+		}
+	} else if t.consumed {
+		return ErrConsumedTreap
+	}
+	size := t.Size()
+	if size == 0 {
+		return fmt.Errorf("%w: index %d", ErrEmptyTreap, index)
+	} else if index < 0 || index >= size {
+		return fmt.Errorf("%w: index %d with size %d", ErrIndexOutOfRange, index, size)
+	}
+	return nil
+}
+
+/*
+Checks that all elements with indexes from index_left to index_right exist.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index_left > index_right: return ErrIndexOutOfRange
+	if any index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) validRange(index_left int, index_right int) error {
+	if t == nil {
+		if err := nilReceiver(); err != nil {
+			return err
+		}
+	} else if t.consumed {
+		return ErrConsumedTreap
+	}
+	size := t.Size()
+	if size == 0 {
+		return fmt.Errorf("%w: range [%d, %d]", ErrEmptyTreap, index_left, index_right)
+	} else if index_left > index_right || index_left < 0 || index_right >= size {
+		return fmt.Errorf("%w: range [%d, %d] with size %d", ErrIndexOutOfRange, index_left, index_right, size)
+	}
+	return nil
+}
+
+/*
+Checks that count more elements can be inserted into the treap.
+
+	if t == nil: return ErrNilTreap, or panic if NilReceiver == NilPanic
+	if treap was consumed: return ErrConsumedTreap
+	if treap is full: return ErrSizeLimit
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) validInsert(count int) error {
+	if t == nil {
+		nilReceiver()
+		return ErrNilTreap
+	} else if t.consumed {
+		return ErrConsumedTreap
+	} else if !t.fits(count) {
+		return fmt.Errorf("%w: inserting %d with size %d and limit %d", ErrSizeLimit, count, t.Size(), t.opts.limit)
+	}
+	return nil
+}
+
+/*
+Leaves treap empty and marks it as consumed.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) consume() {
+	if t == nil {
+		return
+	}
+	t.root = nil
+	t.consumed = true
+}
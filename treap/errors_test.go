@@ -0,0 +1,64 @@
+package treap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTryFind(t *testing.T) {
+	tr := New(4, 5, 6)
+	if value, err := tr.TryFind(1); err != nil || value != 5 {
+		t.Errorf("TryFind(1) = %d, %v, want 5, nil", value, err)
+	}
+	for _, index := range []int{-1, 3} {
+		if _, err := tr.TryFind(index); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("TryFind(%d) = %v, want ErrIndexOutOfRange", index, err)
+		}
+	}
+	empty := New()
+	if _, err := empty.TryFind(0); !errors.Is(err, ErrEmptyTreap) {
+		t.Errorf("TryFind(0) on empty treap = %v, want ErrEmptyTreap", err)
+	}
+	a, b := New(1), New(2)
+	Merge(&a, &b)
+	if _, err := b.TryFind(0); !errors.Is(err, ErrConsumedTreap) {
+		t.Errorf("TryFind(0) on consumed treap = %v, want ErrConsumedTreap", err)
+	}
+}
+
+func TestTryCutAndDelete(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	if err := tr.TryCut(2, 5); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TryCut(2, 5) = %v, want ErrIndexOutOfRange", err)
+	}
+	if err := tr.TryCut(2, 1); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TryCut(2, 1) = %v, want ErrIndexOutOfRange", err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("failed TryCut() changed the treap: %v", got)
+	}
+	if err := tr.TryCut(1, 2); err != nil {
+		t.Errorf("TryCut(1, 2) = %v", err)
+	}
+	if err := tr.TryDelete(2); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TryDelete(2) = %v, want ErrIndexOutOfRange", err)
+	}
+	if err := tr.TryDelete(0); err != nil {
+		t.Errorf("TryDelete(0) = %v", err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{4}) {
+		t.Errorf("Export() = %v, want [4]", got)
+	}
+}
+
+func TestErrorsAreDistinct(t *testing.T) {
+	sentinels := []error{ErrSizeLimit, ErrIndexOutOfRange, ErrEmptyTreap, ErrConsumedTreap, ErrConcurrentModification, ErrNilTreap}
+	for i, e1 := range sentinels {
+		for j, e2 := range sentinels {
+			if i != j && errors.Is(e1, e2) {
+				t.Errorf("%v matches %v", e1, e2)
+			}
+		}
+	}
+}
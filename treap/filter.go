@@ -0,0 +1,118 @@
+package treap
+
+/*
+Deletes all elements, whose values do not satisfy the predicate, in a single pass.
+Values are exported, kept ones are built into a new treap in a linear time,
+unlike `Delete()` of every element, which is logarithmic for each of them.
+Every run of deleted elements is seen as a single deletion, that are made from the last one to the first,
+so the journal, marks and annotations see the same indexes as the treap.
+Predicate is called once for every element in order and must not change the treap.
+
+	if t == nil: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) Filter(keep func(value int) bool) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if t.root == nil {
+		return
+	}
+	v := t.enter()
+	values := make([]int, t.root.size)
+	export(values, 0, t.root)
+	// Runs of deleted elements as pairs of the 1st index and the index after the last.
+	var runs []int
+	for i, value := range values {
+		if keep(value) {
+			continue
+		} else if last := len(runs) - 1; last > 0 && runs[last] == i {
+			runs[last]++
+		} else {
+			runs = append(runs, i, i+1)
+		}
+	}
+	// Deletions are logged from the last run, so only the last runs are deleted if the journal fails.
+	first := len(runs)
+	for first > 0 && t.log(DeleteOp(runs[first-2], runs[first-1]-runs[first-2])) {
+		first -= 2
+	}
+	if first == len(runs) {
+		t.exit(v)
+		return
+	}
+	kept := values[:runs[first]]
+	for i := first; i < len(runs); i += 2 {
+		next := len(values)
+		if i+2 < len(runs) {
+			next = runs[i+2]
+		}
+		kept = append(kept, values[runs[i+1]:next]...)
+	}
+	t.opts.pool.discard(t.root)
+	t.root = build(kept, false, t.opts.pool)
+	for i := len(runs) - 2; i >= first; i -= 2 {
+		t.deleted(runs[i], runs[i+1]-runs[i])
+	}
+	t.leave(v)
+}
+
+/*
+Replaces every value of the treap by the result of the function, in a single walk in place.
+Shape of the treap is kept, augmentations of every node are recalculated on the way back.
+Function is called once for every element in order and must not change the treap.
+
+	if t == nil: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) Map(fn func(int) int) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if t.root == nil {
+		return
+	}
+	v := t.enter()
+	n := t.root.size
+	if t.logging() {
+		// Journal and logs need the values before they are written.
+		values := make([]int, n)
+		export(values, 0, t.root)
+		for i, value := range values {
+			values[i] = fn(value)
+		}
+		if !t.logReplace(0, values...) {
+			t.exit(v)
+			return
+		}
+		fn = func(int) int {
+			value := values[0]
+			values = values[1:]
+			return value
+		}
+	}
+	mapValues(t.root, fn)
+	t.updated(0, n)
+	t.leave(v)
+}
+
+/*
+Replaces values of the subtree in order by the results of the function.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func mapValues(n *node, fn func(int) int) {
+	if n == nil {
+		return
+	}
+	push(n)
+	mapValues(n.lson, fn)
+	n.value = fn(n.value)
+	mapValues(n.rson, fn)
+	sync(n)
+}
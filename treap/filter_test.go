@@ -0,0 +1,97 @@
+package treap
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	r := rand.New(rand.NewPCG(55, 56))
+	for range 200 {
+		values := make([]int, r.IntN(60))
+		for i := range values {
+			values[i] = r.IntN(10)
+		}
+		tr := New(values...)
+		var buf bytes.Buffer
+		tr.SetJournal(&buf)
+		a := tr.Annotate()
+		if len(values) > 0 {
+			a.Add(0, len(values)-1, nil)
+		}
+		limit := r.IntN(10)
+		keep := func(x int) bool { return x < limit }
+		want := slices.DeleteFunc(slices.Clone(values), func(x int) bool { return !keep(x) })
+		tr.Filter(keep)
+		if got := tr.Export(); !slices.Equal(got, want) {
+			t.Fatalf("Filter() of %v = %v, want %v", values, got, want)
+		}
+		checkShape(t, tr.root)
+		if recovered, err := Recover(bytes.NewReader(buf.Bytes())); err != nil || !slices.Equal(recovered.Export(), want) {
+			t.Fatalf("Recover() after Filter() = %v, %v", recovered.Export(), err)
+		}
+		if got := a.Len(); (got == 1) != (len(want) > 0) {
+			t.Fatalf("annotation of %d kept elements is kept %v", len(want), got == 1)
+		} else if got == 1 && a.All()[0].Right != len(want)-1 {
+			t.Fatalf("annotation of %d kept elements ends at %d", len(want), a.All()[0].Right)
+		}
+	}
+}
+
+func TestFilterCallsOnce(t *testing.T) {
+	tr := New(5, 1, 4, 2, 3)
+	var visited []int
+	tr.Filter(func(x int) bool {
+		visited = append(visited, x)
+		return true
+	})
+	if !slices.Equal(visited, []int{5, 1, 4, 2, 3}) || !slices.Equal(tr.Export(), visited) {
+		t.Errorf("Filter() visited %v and kept %v", visited, tr.Export())
+	}
+	tr.Filter(func(int) bool { return false })
+	if tr.Size() != 0 {
+		t.Errorf("Filter() of nothing kept %d elements", tr.Size())
+	}
+}
+
+func TestMap(t *testing.T) {
+	values := make([]int, 300)
+	for i := range values {
+		values[i] = i
+	}
+	tr := New(values...)
+	tr.ReverseRange(50, 250)
+	tr.RangeAdd(0, 99, 1000)
+	var buf bytes.Buffer
+	tr.SetJournal(&buf)
+	before := tr.Export()
+	var visited []int
+	tr.Map(func(x int) int {
+		visited = append(visited, x)
+		return -2 * x
+	})
+	if !slices.Equal(visited, before) {
+		t.Fatal("Map() does not visit the values in order")
+	}
+	want := make([]int, len(before))
+	for i, x := range before {
+		want[i] = -2 * x
+	}
+	if got := tr.Export(); !slices.Equal(got, want) {
+		t.Fatalf("Map() = %v, want %v", got, want)
+	}
+	if fresh := New(want...); tr.Hash() != fresh.Hash() || tr.RangeSum(0, 299) != fresh.RangeSum(0, 299) {
+		t.Error("augmentations are not recalculated by Map()")
+	}
+	if recovered, err := Recover(bytes.NewReader(buf.Bytes())); err != nil || !slices.Equal(recovered.Export(), want) {
+		t.Errorf("Recover() after Map() = %v, %v", recovered.Export(), err)
+	}
+	failing := New(1, 2, 3)
+	failing.SetJournal(failingWriter{})
+	failing.Map(func(x int) int { return x + 1 })
+	if got := failing.Export(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Map() with a failing journal changed the treap to %v", got)
+	}
+}
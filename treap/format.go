@@ -0,0 +1,152 @@
+package treap
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+/*
+Amount of values printed by `String()` and `GoString()`, the rest is only counted.
+*/
+const printLimit = 64
+
+/*
+Returns values of the treap in the form of fmt for slices with type name, e.g. `Treap[3 1 4]`,
+so printing the treap with %v shows its contents.
+Only first 64 values are printed, amount of the omitted values is written after them.
+
+	if t == nil: return "<nil>"
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of printed values plus height of the treap;
+*/
+func (t *Treap) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+	v := t.enter()
+	defer t.exit(v)
+	buf, omitted := appendValues([]byte("Treap["), t.root, " ")
+	if omitted > 0 {
+		buf = append(buf, " ... ("...)
+		buf = strconv.AppendInt(buf, int64(omitted), 10)
+		buf = append(buf, " more)"...)
+	}
+	buf = append(buf, ']')
+	return string(buf)
+}
+
+/*
+Returns Go expression that creates the same treap, e.g. `treap.New(1, 2, 3)`,
+so printing the treap with %#v gives a runnable reproduction.
+Only first 64 values are printed, amount of the omitted values is written in a comment.
+Settings of the treap like size limit are not printed.
+
+	if t == nil: return "(*treap.Treap)(nil)"
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of printed values plus height of the treap;
+*/
+func (t *Treap) GoString() string {
+	if t == nil {
+		return "(*treap.Treap)(nil)"
+	}
+	v := t.enter()
+	defer t.exit(v)
+	buf, omitted := appendValues([]byte("treap.New("), t.root, ", ")
+	if omitted > 0 {
+		buf = append(buf, " /* "...)
+		buf = strconv.AppendInt(buf, int64(omitted), 10)
+		buf = append(buf, " more values */"...)
+	}
+	buf = append(buf, ')')
+	return string(buf)
+}
+
+/*
+Appends first values of the subtree divided by the separator, see `printLimit`.
+Returns extended buffer and amount of the omitted values.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of printed values plus height of the treap;
+*/
+func appendValues(buf []byte, root *node, sep string) ([]byte, int) {
+	var w walker
+	w.seek(root, 0, false)
+	printed := 0
+	for n := w.next(); n != nil && printed < printLimit; n = w.next() {
+		if printed > 0 {
+			buf = append(buf, sep...)
+		}
+		buf = strconv.AppendInt(buf, int64(n.value), 10)
+		printed++
+	}
+	return buf, size(root) - printed
+}
+
+/*
+Writes structure of the treap, one node per line in pre-order, sons are indented under their parent.
+Every line holds side of the node ("-" for the root, "l" or "r" for sons), range of the indexes of its subtree,
+value, size, sum (if the treap is augmented), priority and pending changes, so broken augmentations can be found without a debugger.
+Pending changes are not pushed, sons are printed in their actual order.
+Treap is not changed, whole dump is written by a single Write call.
+
+	if t == nil: write nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) DebugDump(w io.Writer) error {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	v := t.enter()
+	buf := dump(nil, t.root, 0, 0, '-', false)
+	t.exit(v)
+	_, err := w.Write(buf)
+	return err
+}
+
+/*
+Appends dump of the subtree, whose first index is offset, see `DebugDump()`.
+Flipped is true if the subtree has pending reversal from its ancestors,
+so its sons are printed in swapped order.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func dump(buf []byte, n *node, depth int, offset int, side byte, flipped bool) []byte {
+	if n == nil {
+		return buf
+	}
+	for range depth {
+		buf = append(buf, "  "...)
+	}
+	buf = fmt.Appendf(buf, "%c [%d, %d] value=%d size=%d", side, offset, offset+n.size-1, n.value, n.size)
+	if n.aug != nil {
+		buf = fmt.Appendf(buf, " sum=%d", n.aug.sum)
+	}
+	buf = fmt.Appendf(buf, " priority=%d", n.priority)
+	if n.flip {
+		buf = append(buf, " flip"...)
+	}
+	if n.aug != nil && n.aug.filled {
+		buf = fmt.Appendf(buf, " fill=%d", n.aug.fill)
+	}
+	if n.aug != nil && n.aug.negated {
+		buf = append(buf, " negate"...)
+	}
+	if n.aug != nil && n.aug.add != 0 {
+		buf = fmt.Appendf(buf, " add=%d", n.aug.add)
+	}
+	buf = append(buf, '\n')
+	lson, rson := n.lson, n.rson
+	if flipped {
+		lson, rson = rson, lson
+	}
+	flipped = flipped != n.flip
+	buf = dump(buf, lson, depth+1, offset, 'l', flipped)
+	return dump(buf, rson, depth+1, offset+size(lson)+1, 'r', flipped)
+}
@@ -0,0 +1,86 @@
+package treap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGoString(t *testing.T) {
+	tr := New(3, -1, 4)
+	if got := fmt.Sprintf("%#v", &tr); got != "treap.New(3, -1, 4)" {
+		t.Errorf("GoString() = %q", got)
+	}
+	empty := New()
+	if got := empty.GoString(); got != "treap.New()" {
+		t.Errorf("GoString() of empty treap = %q", got)
+	}
+	var nilTreap *Treap
+	if got := nilTreap.GoString(); got != "(*treap.Treap)(nil)" {
+		t.Errorf("GoString() of nil treap = %q", got)
+	}
+	long := New(make([]int, printLimit+5)...)
+	if got := long.GoString(); !strings.HasSuffix(got, ", 0 /* 5 more values */)") || strings.Count(got, "0") != printLimit {
+		t.Errorf("GoString() of long treap = %q", got)
+	}
+}
+
+func TestDebugDump(t *testing.T) {
+	tr := NewWithPriorities([]int{1, 2, 3}, []int{1, 3, 2})
+	var buf strings.Builder
+	if err := tr.DebugDump(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "- [0, 2] value=2 size=3 priority=3\n" +
+		"  l [0, 0] value=1 size=1 priority=1\n" +
+		"  r [2, 2] value=3 size=1 priority=2\n"
+	if buf.String() != want {
+		t.Errorf("DebugDump() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	tr.ReverseRange(0, 2)
+	buf.Reset()
+	tr.DebugDump(&buf)
+	want = "- [0, 2] value=2 size=3 priority=3 flip\n" +
+		"  l [0, 0] value=3 size=1 priority=2\n" +
+		"  r [2, 2] value=1 size=1 priority=1\n"
+	if buf.String() != want {
+		t.Errorf("DebugDump() with pending reversal =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	tr.RangeAdd(0, 2, 5)
+	buf.Reset()
+	tr.DebugDump(&buf)
+	// Pending addition of the sons is printed on the root, sons are not changed yet.
+	want = "- [0, 2] value=7 size=3 sum=21 priority=3 add=5\n" +
+		"  l [0, 0] value=3 size=1 sum=3 priority=2\n" +
+		"  r [2, 2] value=1 size=1 sum=1 priority=1\n"
+	if buf.String() != want {
+		t.Errorf("DebugDump() with pending changes =\n%s\nwant\n%s", buf.String(), want)
+	}
+	var nilTreap *Treap
+	buf.Reset()
+	if err := nilTreap.DebugDump(&buf); err != nil || buf.Len() != 0 {
+		t.Errorf("DebugDump() of nil treap = %q, %v", buf.String(), err)
+	}
+}
+
+func TestString(t *testing.T) {
+	tr := New(3, -1, 4)
+	tr.ReverseRange(0, 2)
+	if got := fmt.Sprintf("%v", &tr); got != "Treap[4 -1 3]" {
+		t.Errorf("String() = %q", got)
+	}
+	empty := New()
+	if got := empty.String(); got != "Treap[]" {
+		t.Errorf("String() of empty treap = %q", got)
+	}
+	var nilTreap *Treap
+	if got := nilTreap.String(); got != "<nil>" {
+		t.Errorf("String() of nil treap = %q", got)
+	}
+	long := New(make([]int, printLimit+5)...)
+	if got := long.String(); !strings.HasSuffix(got, " 0 ... (5 more)]") || strings.Count(got, "0") != printLimit {
+		t.Errorf("String() of long treap = %q", got)
+	}
+}
@@ -0,0 +1,215 @@
+package treap
+
+import "iter"
+
+/*
+Immutable read-only form of the treap.
+Nodes are packed into flat slices in pre-order,
+so the left son of a node is stored right after it,
+and the right son is stored after the whole left subtree.
+
+For every node only its value and the size of its left subtree are stored,
+that requires far less memory than the editable treap and gives better cache behavior.
+
+Frozen treap is safe to be read from parallel goroutines.
+Frozen treap can be saved by `WriteTo()` and later opened by `OpenFrozen()`.
+*/
+type Frozen struct {
+	values []int
+	lsizes []int
+	mapped []byte
+}
+
+/*
+Read-only access to a sequence, that cannot be changed by its holder.
+Implemented by `*Frozen`, so returned sequence can be shared as a constant between goroutines.
+*/
+type ReadOnlyTreap interface {
+	Size() int
+	Find(index int) int
+	Export() []int
+	All() iter.Seq2[int, int]
+}
+
+/*
+Returns immutable treap of all provided values, same as `New()` followed by `Freeze()`.
+Values are built into a treap by a linear builder once and packed right away,
+so the result can be declared as a shared lookup table and read from parallel goroutines without synchronization.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func Of(values ...int) ReadOnlyTreap {
+	f := &Frozen{values: make([]int, 0, len(values)), lsizes: make([]int, 0, len(values))}
+	freeze(f, build(values, false, nil))
+	return f
+}
+
+/*
+Returns frozen copy of the treap.
+Treap itself is not changed and can be used afterwards.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) Freeze() *Frozen {
+	if t == nil {
+		nilReceiver()
+		return &Frozen{}
+	}
+	v := t.enter()
+	size := t.Size()
+	f := &Frozen{values: make([]int, 0, size), lsizes: make([]int, 0, size)}
+	freeze(f, t.root)
+	t.exit(v)
+	return f
+}
+
+/*
+Appends all nodes of the subtree to the frozen treap in pre-order.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func freeze(f *Frozen, n *node) {
+	for n != nil {
+		push(n)
+		lsize := 0
+		if n.lson != nil {
+			lsize = n.lson.size
+		}
+		f.values = append(f.values, n.value)
+		f.lsizes = append(f.lsizes, lsize)
+		freeze(f, n.lson)
+		n = n.rson
+	}
+}
+
+/*
+Returns new editable treap with the same values.
+Frozen treap is not changed and can be used afterwards.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (f *Frozen) Thaw() Treap {
+	t := Treap{}
+	b := builder{}
+	for _, value := range f.All() {
+		b.push(value)
+	}
+	v := t.enter()
+	t.root = b.finish()
+	t.leave(v)
+	return t
+}
+
+/*
+Returns size of a frozen treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (f *Frozen) Size() int {
+	return len(f.values)
+}
+
+/*
+Return the element on the given index.
+
+	if index out of range: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (f *Frozen) Find(index int) int {
+	if index < 0 || index >= len(f.values) {
+		return 0
+	}
+	for i := 0; ; {
+		lsize := f.lsizes[i]
+		if index < lsize {
+			i++
+		} else if index > lsize {
+			index -= lsize + 1
+			i += lsize + 1
+		} else {
+			return f.values[i]
+		}
+	}
+}
+
+/*
+Returns all values of the frozen treap as slice of the integers.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (f *Frozen) Export() []int {
+	values := make([]int, 0, len(f.values))
+	for _, value := range f.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+/*
+Returns iterator over all indexes and values of the frozen treap in order.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (f *Frozen) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		f.walk(0, len(f.values), 0, yield)
+	}
+}
+
+/*
+Yields all values of the subtree stored from the position i with provided size.
+First yielded index is equal to offset.
+Returns false if iteration was stopped.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func (f *Frozen) walk(i int, size int, offset int, yield func(int, int) bool) bool {
+	for size > 0 {
+		lsize := f.lsizes[i]
+		if !f.walk(i+1, lsize, offset, yield) {
+			return false
+		}
+		if !yield(offset+lsize, f.values[i]) {
+			return false
+		}
+		offset += lsize + 1
+		i += lsize + 1
+		size -= lsize + 1
+	}
+	return true
+}
+
+/*
+Yields all values of the subtree stored from the position i with provided size,
+whose indexes are inside [lo, hi) range.
+First index of the subtree is equal to offset.
+Returns false if iteration was stopped.
+
+# Time complexity:
+  - Linear - time complexity is equal to height of the treap plus size of the range;
+*/
+func (f *Frozen) walkRange(i int, size int, offset int, lo int, hi int, yield func(int, int) bool) bool {
+	for size > 0 && offset < hi && lo < offset+size {
+		lsize := f.lsizes[i]
+		if lo < offset+lsize && !f.walkRange(i+1, lsize, offset, lo, hi, yield) {
+			return false
+		}
+		if index := offset + lsize; lo <= index && index < hi && !yield(index, f.values[i]) {
+			return false
+		}
+		offset += lsize + 1
+		i += lsize + 1
+		size -= lsize + 1
+	}
+	return true
+}
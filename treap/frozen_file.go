@@ -0,0 +1,264 @@
+package treap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"unsafe"
+)
+
+/*
+Header of the frozen treap file.
+File layout (all numbers are 64-bit little-endian):
+
+	magic [8]byte
+	size  uint64
+	values [size]int64
+	lsizes [size]int64
+*/
+const frozenMagic = "TREAPFZ1"
+
+/*
+Size of the frozen treap file header in bytes.
+Keeps the following slices aligned to 8 bytes.
+*/
+const frozenHeader = 16
+
+/*
+Returned when the file is not a frozen treap or it is damaged.
+*/
+var ErrBadFrozen = errors.New("treap: bad frozen treap file")
+
+/*
+Writes frozen treap in the format that can be opened by `OpenFrozen()` or read by `ReadFrozen()`.
+Implements io.WriterTo interface.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (f *Frozen) WriteTo(w io.Writer) (int64, error) {
+	fw := newFrozenWriter(w, len(f.values))
+	for _, s := range [2][]int{f.values, f.lsizes} {
+		for _, x := range s {
+			fw.put(x)
+		}
+	}
+	return fw.finish()
+}
+
+/*
+Buffered writer of the frozen treap file.
+First error is remembered and returned by `finish()`.
+*/
+type frozenWriter struct {
+	bw      *bufio.Writer
+	written int64
+	buf     [8]byte
+	err     error
+}
+
+/*
+Creates writer and writes the header of the frozen treap with provided size.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func newFrozenWriter(w io.Writer, size int) *frozenWriter {
+	fw := &frozenWriter{bw: bufio.NewWriter(w)}
+	n, err := fw.bw.WriteString(frozenMagic)
+	fw.written, fw.err = int64(n), err
+	fw.put(size)
+	return fw
+}
+
+/*
+Writes single 64-bit number.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (fw *frozenWriter) put(x int) {
+	if fw.err != nil {
+		return
+	}
+	binary.LittleEndian.PutUint64(fw.buf[:], uint64(x))
+	n, err := fw.bw.Write(fw.buf[:])
+	fw.written += int64(n)
+	fw.err = err
+}
+
+/*
+Flushes the buffer, returns amount of written bytes and the first error.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (fw *frozenWriter) finish() (int64, error) {
+	if fw.err != nil {
+		return fw.written, fw.err
+	}
+	return fw.written, fw.bw.Flush()
+}
+
+/*
+Reads frozen treap written by `WriteTo()` into the heap memory.
+Sizes of all subtrees are validated, so damaged file is reported by ErrBadFrozen instead of breaking lookups.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func ReadFrozen(r io.Reader) (*Frozen, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, frozenHeader)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadFrozen, err)
+	}
+	size, err := parseFrozenHeader(header, -1)
+	if err != nil {
+		return nil, err
+	}
+	// Size is not trusted until all values are read, so slices grow gradually.
+	f := &Frozen{values: make([]int, 0, min(size, 1<<16)), lsizes: make([]int, 0, min(size, 1<<16))}
+	buf := make([]byte, 8)
+	for _, s := range [2]*[]int{&f.values, &f.lsizes} {
+		for range size {
+			if _, err := io.ReadFull(br, buf); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrBadFrozen, err)
+			}
+			*s = append(*s, int(int64(binary.LittleEndian.Uint64(buf))))
+		}
+	}
+	if err := checkLsizes(f.lsizes); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+/*
+Opens frozen treap file written by `WriteTo()`.
+On platforms that support it, file is memory-mapped and its contents are used without copying,
+so the same file can be queried by many processes without loading it into the heap.
+Otherwise file is read into the heap memory.
+
+Returned frozen treap must be closed by `Close()` when it is no longer needed.
+Sizes of all subtrees are validated same as by `ReadFrozen()`, values are never copied.
+File must not be changed while it is opened, since mapped contents are not validated again.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func OpenFrozen(path string) (*Frozen, error) {
+	if !zeroCopy {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return ReadFrozen(file)
+	}
+	data, err := mmapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	size, err := parseFrozenHeader(data, len(data))
+	if err != nil {
+		munmap(data)
+		return nil, err
+	}
+	f := &Frozen{mapped: data}
+	if size > 0 {
+		f.values = unsafe.Slice((*int)(unsafe.Pointer(&data[frozenHeader])), size)
+		f.lsizes = unsafe.Slice((*int)(unsafe.Pointer(&data[frozenHeader+8*size])), size)
+	}
+	if err := checkLsizes(f.lsizes); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+/*
+Releases memory-mapped file of the frozen treap.
+Frozen treap must not be used afterwards.
+
+	if frozen treap is not memory-mapped: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (f *Frozen) Close() error {
+	if f.mapped == nil {
+		return nil
+	}
+	data := f.mapped
+	f.values, f.lsizes, f.mapped = nil, nil, nil
+	return munmap(data)
+}
+
+/*
+Checks the header and returns size of the frozen treap.
+
+	if length >= 0: also checks that file length matches the size
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func parseFrozenHeader(data []byte, length int) (int, error) {
+	if len(data) < frozenHeader || string(data[:8]) != frozenMagic {
+		return 0, fmt.Errorf("%w: wrong header", ErrBadFrozen)
+	}
+	size := binary.LittleEndian.Uint64(data[8:frozenHeader])
+	if size > uint64(maxFrozenSize) {
+		return 0, fmt.Errorf("%w: size %d is too big", ErrBadFrozen, size)
+	}
+	if length >= 0 && uint64(length-frozenHeader) != 16*size {
+		return 0, fmt.Errorf("%w: length %d does not match size %d", ErrBadFrozen, length, size)
+	}
+	return int(size), nil
+}
+
+/*
+Checks that sizes of left subtrees describe a tree stored in pre-order:
+size of the left subtree of every node is not negative and is less than size of the whole subtree.
+Otherwise lookups could leave the slices or never stop.
+Pending subtrees are kept on an explicit stack, so degenerate trees do not deepen the call stack.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func checkLsizes(lsizes []int) error {
+	var pending []int
+	if len(lsizes) > 0 {
+		pending = append(pending, len(lsizes))
+	}
+	for i, lsize := range lsizes {
+		size := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if lsize < 0 || lsize >= size {
+			return fmt.Errorf("%w: node %d has left subtree of size %d in subtree of size %d", ErrBadFrozen, i, lsize, size)
+		}
+		// Left son is stored first, so it is pushed last.
+		if rsize := size - 1 - lsize; rsize > 0 {
+			pending = append(pending, rsize)
+		}
+		if lsize > 0 {
+			pending = append(pending, lsize)
+		}
+	}
+	return nil
+}
+
+/*
+Maximum size of the frozen treap that can be addressed by the file layout.
+*/
+const maxFrozenSize = (1<<(strconv.IntSize-1) - 1 - frozenHeader) / 16
+
+/*
+Reports whether file contents can be used as int slices directly:
+int must be 64-bit and byte order must be little-endian.
+*/
+var zeroCopy = strconv.IntSize == 64 && mmapSupported && binary.NativeEndian.Uint16([]byte{1, 0}) == 1
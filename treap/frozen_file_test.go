@@ -0,0 +1,95 @@
+package treap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Returns file contents of the frozen treap with the left subtree size of the node replaced.
+func damagedFrozen(t *testing.T, values []int, node int, lsize int) []byte {
+	t.Helper()
+	tr := New(values...)
+	var buf bytes.Buffer
+	if _, err := tr.Freeze().WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint64(data[frozenHeader+8*(len(values)+node):], uint64(lsize))
+	return data
+}
+
+func TestFrozenRoundTrip(t *testing.T) {
+	values := []int{5, -3, 8, 0, 1 << 40}
+	tr := New(values...)
+	var buf bytes.Buffer
+	if _, err := tr.Freeze().WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "values.fz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadFrozen(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := OpenFrozen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer opened.Close()
+	for _, f := range []*Frozen{read, opened} {
+		if got := f.Export(); !slices.Equal(got, values) {
+			t.Errorf("Export() = %v, want %v", got, values)
+		}
+		if got := f.Find(2); got != 8 {
+			t.Errorf("Find(2) = %d, want 8", got)
+		}
+	}
+}
+
+func TestFrozenDamagedSizes(t *testing.T) {
+	values := make([]int, 100)
+	for _, lsize := range []int{-1, 100, 1 << 40} {
+		for _, node := range []int{0, 1, 57} {
+			data := damagedFrozen(t, values, node, lsize)
+			if _, err := ReadFrozen(bytes.NewReader(data)); !errors.Is(err, ErrBadFrozen) {
+				t.Errorf("ReadFrozen() with lsize %d of node %d = %v, want ErrBadFrozen", lsize, node, err)
+			}
+			path := filepath.Join(t.TempDir(), "damaged.fz")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := OpenFrozen(path); !errors.Is(err, ErrBadFrozen) {
+				t.Errorf("OpenFrozen() with lsize %d of node %d = %v, want ErrBadFrozen", lsize, node, err)
+			}
+		}
+	}
+}
+
+func TestCheckLsizes(t *testing.T) {
+	tests := []struct {
+		lsizes []int
+		valid  bool
+	}{
+		{nil, true},
+		{[]int{0}, true},
+		{[]int{1, 0}, true},
+		{[]int{0, 0}, true},
+		{[]int{2, 1, 0}, true},
+		{[]int{1, 0, 0}, true},
+		{[]int{1, 1}, false},
+		{[]int{0, 1}, false},
+		{[]int{1, 0, 1}, false},
+	}
+	for _, tt := range tests {
+		if err := checkLsizes(tt.lsizes); (err == nil) != tt.valid {
+			t.Errorf("checkLsizes(%v) = %v, want valid %v", tt.lsizes, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	values := make([]int, 300)
+	for i := range values {
+		values[i] = i*7 - 100
+	}
+	tr := New(values...)
+	tr.ReverseRange(10, 20)
+	slices.Reverse(values[10:21])
+	f := tr.Freeze()
+	if f.Size() != len(values) {
+		t.Fatalf("Size() = %d, want %d", f.Size(), len(values))
+	}
+	for i, want := range values {
+		if got := f.Find(i); got != want {
+			t.Fatalf("Find(%d) = %d, want %d", i, got, want)
+		}
+	}
+	if got := f.Find(-1) + f.Find(len(values)); got != 0 {
+		t.Errorf("Find() out of range = %d, want 0", got)
+	}
+	if got := f.Export(); !slices.Equal(got, values) {
+		t.Errorf("Export() = %v", got)
+	}
+	for i, value := range f.All() {
+		if value != values[i] {
+			t.Fatalf("All() yields %d at %d, want %d", value, i, values[i])
+		}
+		if i == 50 {
+			break
+		}
+	}
+	tr.Set(0, 1000)
+	if f.Find(0) != values[0] {
+		t.Error("frozen treap changed together with the treap")
+	}
+}
+
+func TestThaw(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	thawed := tr.Freeze().Thaw()
+	thawed.Insert(2, 9)
+	checkShape(t, thawed.root)
+	if got := thawed.Export(); !slices.Equal(got, []int{1, 2, 9, 3, 4}) {
+		t.Errorf("thawed treap = %v", got)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("original treap = %v", got)
+	}
+}
+
+func TestFrozenWalkRange(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	f := tr.Freeze()
+	var got []int
+	f.walkRange(0, f.Size(), 0, 3, 7, func(index int, value int) bool {
+		got = append(got, value)
+		return true
+	})
+	if !slices.Equal(got, []int{3, 4, 5, 6}) {
+		t.Errorf("walkRange(3, 7) = %v", got)
+	}
+}
+
+func TestOf(t *testing.T) {
+	values := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	table := Of(values...)
+	values[0] = 0
+	if got := table.Export(); !slices.Equal(got, []int{3, 1, 4, 1, 5, 9, 2, 6}) {
+		t.Errorf("Of() = %v", got)
+	}
+	done := make(chan bool)
+	for range 4 {
+		go func() {
+			ok := table.Size() == 8
+			for i, value := range table.All() {
+				ok = ok && table.Find(i) == value
+			}
+			done <- ok
+		}()
+	}
+	for range 4 {
+		if !<-done {
+			t.Error("parallel reads of Of() disagree")
+		}
+	}
+	if empty := Of(); empty.Size() != 0 || len(empty.Export()) != 0 {
+		t.Error("Of() without values is not empty")
+	}
+}
@@ -0,0 +1,351 @@
+package treap
+
+/*
+Node of the generic treap.
+Value is stored inline in the node, so small value types are not boxed
+and no additional pointer indirection is made, same as in the integer treap.
+*/
+type gnode[T any] struct {
+	value    T
+	size     int
+	priority int
+	tiebreak uint64
+	lson     *gnode[T]
+	rson     *gnode[T]
+}
+
+/*
+Creates single generic node with random priority, same as `newNode()`.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func newGnode[T any](value T) *gnode[T] {
+	return &gnode[T]{value: value, size: 1, priority: randomPriority(), tiebreak: mix(created.Add(1))}
+}
+
+/*
+Returns size of the generic subtree.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func gsize[T any](n *gnode[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+/*
+Recalculate generic node's size, same as `sync()`.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func gsync[T any](n *gnode[T]) {
+	n.size = 1 + gsize(n.lson) + gsize(n.rson)
+}
+
+/*
+Merges 2 generic nodes, same as `merge()`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func gmerge[T any](n1 *gnode[T], n2 *gnode[T]) *gnode[T] {
+	if n1 == nil {
+		return n2
+	} else if n2 == nil {
+		return n1
+	}
+	if n1.priority > n2.priority || (n1.priority == n2.priority && n1.tiebreak > n2.tiebreak) {
+		n1.rson = gmerge(n1.rson, n2)
+		gsync(n1)
+		return n1
+	}
+	n2.lson = gmerge(n1, n2.lson)
+	gsync(n2)
+	return n2
+}
+
+/*
+Splits generic node into 2 by provided index, same as `split()`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func gsplit[T any](n *gnode[T], index int) (l *gnode[T], r *gnode[T]) {
+	if n == nil {
+		return nil, nil
+	} else if index < 0 {
+		return nil, n
+	} else if index >= n.size {
+		return n, nil
+	}
+	lsize := gsize(n.lson)
+	if index < lsize {
+		l, n.lson = gsplit(n.lson, index)
+		gsync(n)
+		return l, n
+	}
+	n.rson, r = gsplit(n.rson, index-lsize-1)
+	gsync(n)
+	return n, r
+}
+
+/*
+Builds generic treap from all provided values in a linear time, same as `build()`.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func gbuild[T any](values []T, reversed bool) *gnode[T] {
+	spine := make([]*gnode[T], 0, 64)
+	for i := range values {
+		if reversed {
+			i = len(values) - 1 - i
+		}
+		n := newGnode(values[i])
+		var last *gnode[T]
+		for len(spine) > 0 && (n.priority > spine[len(spine)-1].priority ||
+			(n.priority == spine[len(spine)-1].priority && n.tiebreak > spine[len(spine)-1].tiebreak)) {
+			last = spine[len(spine)-1]
+			spine = spine[:len(spine)-1]
+			gsync(last)
+		}
+		n.lson = last
+		if len(spine) > 0 {
+			spine[len(spine)-1].rson = n
+		}
+		spine = append(spine, n)
+	}
+	for i := len(spine) - 1; i >= 0; i-- {
+		gsync(spine[i])
+	}
+	if len(spine) == 0 {
+		return nil
+	}
+	return spine[0]
+}
+
+/*
+Returns the generic node on the given index.
+
+	if index out of range: return nil
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func gfind[T any](n *gnode[T], index int) *gnode[T] {
+	for n != nil {
+		lsize := gsize(n.lson)
+		if index < lsize {
+			n = n.lson
+		} else if index > lsize {
+			index -= lsize + 1
+			n = n.rson
+		} else {
+			return n
+		}
+	}
+	return nil
+}
+
+/*
+Saves generic nodes values into provided slice, same as `export()`.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func gexport[T any](values []T, position int, n *gnode[T]) {
+	for n != nil {
+		lsize := gsize(n.lson)
+		gexport(values, position, n.lson)
+		values[position+lsize] = n.value
+		position += lsize + 1
+		n = n.rson
+	}
+}
+
+/*
+Treap with implicit keys storing values of any type.
+Works the same way as `Treap`, that is specialized for integers,
+and has the same performance for small value types, since values are stored inline in the nodes.
+
+Configuration options of the `Treap` (limits, strict mode, recycling, etc.) are not supported.
+*/
+type Generic[T any] struct {
+	root *gnode[T]
+}
+
+/*
+Correctly initialize a generic treap.
+Insert all given values to the back by calling `PushBack()` method.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func NewGeneric[T any](values ...T) Generic[T] {
+	t := Generic[T]{}
+	t.PushBack(values...)
+	return t
+}
+
+/*
+Merges 2 generic treaps, same as `Merge()`.
+Old treaps are left empty.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func MergeGeneric[T any](t1 *Generic[T], t2 *Generic[T]) Generic[T] {
+	var t Generic[T]
+	if t1 != nil {
+		t.root, t1.root = t1.root, nil
+	}
+	if t2 != nil {
+		t.root, t2.root = gmerge(t.root, t2.root), nil
+	}
+	return t
+}
+
+/*
+Split generic treap by provided index, same as `Split()`.
+Old treap is left empty.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func SplitGeneric[T any](t *Generic[T], index int) (tl Generic[T], tr Generic[T]) {
+	if t != nil {
+		tl.root, tr.root = gsplit(t.root, index)
+		t.root = nil
+	}
+	return
+}
+
+/*
+Insert value into provided index, same as `Insert()` of the `Treap`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Generic[T]) Insert(index int, value T) {
+	if t == nil {
+		return
+	}
+	if index <= 0 {
+		t.root = gmerge(newGnode(value), t.root)
+		return
+	} else if index >= gsize(t.root) {
+		t.root = gmerge(t.root, newGnode(value))
+		return
+	}
+	l, r := gsplit(t.root, index-1)
+	t.root = gmerge(gmerge(l, newGnode(value)), r)
+}
+
+/*
+Insert all provided values to the front, same as `PushFront()` of the `Treap`.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
+*/
+func (t *Generic[T]) PushFront(values ...T) {
+	if t == nil {
+		return
+	}
+	t.root = gmerge(gbuild(values, true), t.root)
+}
+
+/*
+Insert all provided values to the back, same as `PushBack()` of the `Treap`.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
+*/
+func (t *Generic[T]) PushBack(values ...T) {
+	if t == nil {
+		return
+	}
+	t.root = gmerge(t.root, gbuild(values, false))
+}
+
+/*
+Delete all elements in the given range, same as `Cut()` of the `Treap`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Generic[T]) Cut(index_left int, index_right int) {
+	if t == nil || t.root == nil {
+		return
+	} else if index_left > index_right || index_right < 0 || index_left >= t.root.size {
+		return
+	}
+	index_left = max(index_left, 0)
+	index_right = min(index_right, t.root.size-1)
+	l, k := gsplit(t.root, index_left-1)
+	_, r := gsplit(k, index_right-index_left)
+	t.root = gmerge(l, r)
+}
+
+/*
+Delete 1 element by provided index, same as `Delete()` of the `Treap`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Generic[T]) Delete(index int) {
+	if t == nil || index < 0 || index >= gsize(t.root) {
+		return
+	}
+	t.Cut(index, index)
+}
+
+/*
+Returns size of a generic treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Generic[T]) Size() int {
+	if t == nil {
+		return 0
+	}
+	return gsize(t.root)
+}
+
+/*
+Return the element on the given index.
+
+	if index out of range: return zero value
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Generic[T]) Find(index int) (value T) {
+	if t == nil {
+		return
+	}
+	if n := gfind(t.root, index); n != nil {
+		return n.value
+	}
+	return
+}
+
+/*
+Returns all values of the generic treap as a slice.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Generic[T]) Export() []T {
+	if t == nil || t.root == nil {
+		return nil
+	}
+	values := make([]T, t.root.size)
+	gexport(values, 0, t.root)
+	return values
+}
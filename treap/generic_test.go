@@ -0,0 +1,72 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestGenericModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(13, 14))
+	g := NewGeneric("a", "b", "c")
+	model := []string{"a", "b", "c"}
+	for step := range 2000 {
+		n := len(model)
+		value := string(rune('a' + step%26))
+		switch r.IntN(5) {
+		case 0:
+			index := r.IntN(n+3) - 1
+			g.Insert(index, value)
+			model = slices.Insert(model, min(max(index, 0), n), value)
+		case 1:
+			g.PushFront(value, value)
+			model = slices.Insert(model, 0, value, value)
+		case 2:
+			g.PushBack(value)
+			model = append(model, value)
+		case 3:
+			l := r.IntN(n+2) - 1
+			h := l + r.IntN(4)
+			g.Cut(l, h)
+			if l <= h && h >= 0 && l < n {
+				model = slices.Delete(model, max(l, 0), min(h, n-1)+1)
+			}
+		case 4:
+			index := r.IntN(n+2) - 1
+			g.Delete(index)
+			if index >= 0 && index < n {
+				model = slices.Delete(model, index, index+1)
+			}
+		}
+		if g.Size() != len(model) {
+			t.Fatalf("step %d: Size() = %d, want %d", step, g.Size(), len(model))
+		}
+	}
+	if got := g.Export(); !slices.Equal(got, model) {
+		t.Fatalf("Export() = %v, want %v", got, model)
+	}
+	for i, want := range model {
+		if got := g.Find(i); got != want {
+			t.Fatalf("Find(%d) = %q, want %q", i, got, want)
+		}
+	}
+	if got := g.Find(len(model)); got != "" {
+		t.Errorf("Find() out of range = %q", got)
+	}
+}
+
+func TestGenericSplitAndMerge(t *testing.T) {
+	g := NewGeneric(1.5, 2.5, 3.5, 4.5)
+	l, r := SplitGeneric(&g, 1)
+	if !slices.Equal(l.Export(), []float64{1.5, 2.5}) || !slices.Equal(r.Export(), []float64{3.5, 4.5}) {
+		t.Fatalf("SplitGeneric() = %v, %v", l.Export(), r.Export())
+	}
+	merged := MergeGeneric(&r, &l)
+	if got := merged.Export(); !slices.Equal(got, []float64{3.5, 4.5, 1.5, 2.5}) {
+		t.Errorf("MergeGeneric() = %v", got)
+	}
+	var empty *Generic[float64]
+	if empty.Size() != 0 || empty.Export() != nil {
+		t.Error("nil generic treap is not empty")
+	}
+}
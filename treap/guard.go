@@ -0,0 +1,71 @@
+package treap
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+/*
+Lightweight try-lock used to detect 2 goroutines using the same treap at the same time.
+Only operations that overlap in time are detected.
+*/
+type guard struct {
+	busy atomic.Bool
+}
+
+/*
+Marks the treap as being used.
+Panics with `ErrConcurrentModification` if it is already used.
+
+	if g == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (g *guard) acquire() {
+	if g == nil {
+		return
+	}
+	if !g.busy.CompareAndSwap(false, true) {
+		panic(fmt.Errorf("%w: treap is already used by another goroutine", ErrConcurrentModification))
+	}
+}
+
+/*
+Marks the treap as not being used.
+
+	if g == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (g *guard) release() {
+	if g == nil {
+		return
+	}
+	g.busy.Store(false)
+}
+
+/*
+Enables or disables ownership checks for the treap.
+While enabled, every operation marks the treap as used,
+and an operation started while another one is still running panics with `ErrConcurrentModification`.
+
+Treap is still unsafe to be used in parallel goroutines,
+this mode only reports such misuse instead of silently corrupting data.
+Treaps returned by `Split()` get their own checks, treap returned by `Merge()` shares checks of the 1st treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SetOwnershipCheck(on bool) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	if !on {
+		t.opts.guard = nil
+	} else if t.opts.guard == nil {
+		t.opts.guard = &guard{}
+	}
+}
@@ -0,0 +1,50 @@
+package treap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOwnershipCheck(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.SetOwnershipCheck(true)
+	tr.Insert(1, 5)
+	tr.Find(0)
+	// Operation of another goroutine is still running.
+	tr.opts.guard.acquire()
+	if err := panicked(t, func() { tr.Find(0) }); !errors.Is(err, ErrConcurrentModification) {
+		t.Errorf("overlapping Find() panicked with %v, want ErrConcurrentModification", err)
+	}
+	tr.opts.guard.release()
+	if err := panicked(t, func() { tr.Insert(0, 1) }); err != nil {
+		t.Errorf("Insert() after the other operation finished panicked with %v", err)
+	}
+}
+
+func TestOwnershipCheckDisabled(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.SetOwnershipCheck(true)
+	g := tr.opts.guard
+	tr.SetOwnershipCheck(false)
+	g.acquire()
+	if err := panicked(t, func() { tr.Find(0) }); err != nil {
+		t.Errorf("Find() without ownership checks panicked with %v", err)
+	}
+}
+
+func TestOwnershipCheckOfSplitAndMerge(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	tr.SetOwnershipCheck(true)
+	l, r := Split(&tr, 1)
+	if l.opts.guard == nil || r.opts.guard == nil || l.opts.guard == r.opts.guard {
+		t.Fatal("split treaps do not get their own checks")
+	}
+	m := Merge(&l, &r)
+	if m.opts.guard == nil {
+		t.Fatal("merged treap lost ownership checks")
+	}
+	m.opts.guard.acquire()
+	if err := panicked(t, func() { m.Delete(0) }); !errors.Is(err, ErrConcurrentModification) {
+		t.Errorf("overlapping Delete() panicked with %v, want ErrConcurrentModification", err)
+	}
+}
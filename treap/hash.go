@@ -0,0 +1,190 @@
+package treap
+
+import (
+	"math/bits"
+	"math/rand/v2"
+)
+
+/*
+Modulus of the polynomial hash of the values, prime 2^61 - 1.
+Polynomial hashes modulo a power of 2 collide for Thue–Morse sequences whatever the base is,
+so hashes are computed modulo a prime.
+*/
+const hashModulus = 1<<61 - 1
+
+/*
+Base of the polynomial hash of the values, chosen at random once per process,
+so sequences with equal hashes cannot be prepared in advance.
+*/
+var hashBase = 2 + rand.Uint64N(hashModulus-3)
+
+/*
+Offset added to every value before hashing, so zeros and empty treaps hash differently.
+*/
+const hashOffset = 0x2545f4914f6cdd1d % hashModulus
+
+/*
+Returns hash of the single value.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func leafHash(value int) uint64 {
+	return hadd(hashOf(value), hashOffset)
+}
+
+/*
+Returns the value as an element of the field of the hash, negative values are counted from the modulus.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func hashOf(value int) uint64 {
+	r := int64(value) % hashModulus
+	if r < 0 {
+		r += hashModulus
+	}
+	return uint64(r)
+}
+
+/*
+Returns sum of 2 hashes modulo the modulus of the hash.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func hadd(a uint64, b uint64) uint64 {
+	if a += b; a >= hashModulus {
+		a -= hashModulus
+	}
+	return a
+}
+
+/*
+Returns difference of 2 hashes modulo the modulus of the hash.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func hsub(a uint64, b uint64) uint64 {
+	return hadd(a, hashModulus-b)
+}
+
+/*
+Returns product of 2 hashes modulo the modulus of the hash.
+Modulus is a Mersenne prime, so the 122-bit product is reduced by shifts instead of a division.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func hmul(a uint64, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	// 2^64 is 8 modulo the modulus, so the high word is shifted into the bits above the 61st one.
+	x := (hi<<3 | lo>>61) + lo&hashModulus
+	x = x&hashModulus + x>>61
+	if x >= hashModulus {
+		x -= hashModulus
+	}
+	return x
+}
+
+/*
+Returns polynomial hash of all values of the subtree.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func hash(n *node) uint64 {
+	if n == nil {
+		return 0
+	}
+	return n.aug.hash
+}
+
+/*
+Returns order-sensitive hash of all values of the treap.
+Polynomial hash of every subtree is stored in its root and kept by every operation of an augmented treap,
+so no traversal is made, and treaps with different hashes are never equal.
+Hash is computed modulo a prime with a random base, so different sequences of n values collide
+with probability of at most n / 2^61 for any values, but it is not cryptographic.
+Equal hashes do not guarantee equal treaps, use `Equal()` to confirm.
+Base is chosen once per process, so hashes must not be saved or compared between processes.
+
+	if t == nil: return hash of the empty treap
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) Hash() uint64 {
+	if t == nil {
+		nilReceiver()
+		return mix(0)
+	}
+	v := t.enter()
+	t.augment()
+	result := mix(hash(t.root) ^ uint64(size(t.root)))
+	t.exit(v)
+	return result
+}
+
+/*
+Returns polynomial hash of the 1st values of the subtree of provided amount.
+Hashes of whole sons are taken from their augmentations, so only 1 path from the root is walked.
+Subtree must be augmented.
+
+	if count <= 0: return 0
+	if count >= size of the subtree: return hash of the subtree
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func prefixHash(n *node, count int) uint64 {
+	var result uint64
+	for n != nil && count > 0 {
+		if count >= n.size {
+			return hadd(hmul(result, n.aug.pow), n.aug.hash)
+		}
+		push(n)
+		lsize := size(n.lson)
+		if count <= lsize {
+			n = n.lson
+			continue
+		}
+		if l := n.lson; l != nil {
+			result = hadd(hmul(result, l.aug.pow), l.aug.hash)
+		}
+		result = hadd(hmul(result, hashBase), leafHash(n.value))
+		count -= lsize + 1
+		n = n.rson
+	}
+	return result
+}
+
+/*
+Returns polynomial hash of the last values of the subtree of provided amount, same as `prefixHash()`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func suffixHash(n *node, count int) uint64 {
+	count = min(count, size(n))
+	return hsub(prefixHash(n, size(n)), hmul(prefixHash(n, size(n)-count), power(count)))
+}
+
+/*
+Returns base of the hash in provided power.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to logarithm of the power;
+*/
+func power(exponent int) uint64 {
+	result, base := uint64(1), hashBase
+	for ; exponent > 0; exponent >>= 1 {
+		if exponent&1 == 1 {
+			result = hmul(result, base)
+		}
+		base = hmul(base, base)
+	}
+	return result
+}
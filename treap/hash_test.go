@@ -0,0 +1,123 @@
+package treap
+
+import (
+	"math/big"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestHashDependsOnlyOnValues(t *testing.T) {
+	a := New(1, 2, 3, 4, 5)
+	b := New()
+	for _, value := range []int{5, 4, 3, 2, 1} {
+		b.PushFront(value)
+	}
+	b.ReverseRange(1, 3)
+	b.ReverseRange(1, 3)
+	if a.Hash() != b.Hash() {
+		t.Error("same values have different hashes")
+	}
+	a.RangeAdd(1, 2, 10)
+	b.Set(1, 12)
+	b.Set(2, 13)
+	if a.Hash() != b.Hash() {
+		t.Error("hash is not kept by RangeAdd()")
+	}
+	a.RangeAssign(0, 4, 7)
+	c := New(7, 7, 7, 7, 7)
+	if a.Hash() != c.Hash() {
+		t.Error("hash is not kept by RangeAssign()")
+	}
+	if shorter := New(7, 7, 7, 7); a.Hash() == shorter.Hash() {
+		t.Error("treaps of different sizes have the same hash")
+	}
+	zeros, empty := New(0), New()
+	var nilTreap *Treap
+	if zeros.Hash() == empty.Hash() || nilTreap.Hash() != empty.Hash() {
+		t.Error("hash of the empty treap is not distinct")
+	}
+}
+
+func TestHashDiffers(t *testing.T) {
+	r := rand.New(rand.NewPCG(23, 24))
+	seen := make(map[uint64][]int)
+	for range 5000 {
+		values := make([]int, r.IntN(6))
+		for i := range values {
+			values[i] = r.IntN(5) - 2
+		}
+		tr := New(values...)
+		h := tr.Hash()
+		if other, ok := seen[h]; ok && !slices.Equal(other, values) {
+			t.Fatalf("%v and %v have the same hash", other, values)
+		}
+		seen[h] = values
+	}
+}
+
+func TestPrefixAndSuffixHash(t *testing.T) {
+	values := []int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3}
+	tr := New(values...)
+	tr.augment()
+	for count := 0; count <= len(values); count++ {
+		prefix, suffix := New(values[:count]...), New(values[len(values)-count:]...)
+		prefix.augment()
+		suffix.augment()
+		if got := prefixHash(tr.root, count); got != hash(prefix.root) {
+			t.Errorf("prefixHash(%d) = %x, want %x", count, got, hash(prefix.root))
+		}
+		if got := suffixHash(tr.root, count); got != hash(suffix.root) {
+			t.Errorf("suffixHash(%d) = %x, want %x", count, got, hash(suffix.root))
+		}
+	}
+}
+
+// Returns 0/1 Thue–Morse sequence of provided length and its complement.
+func thueMorse(n int) (x []int, y []int) {
+	x, y = make([]int, n), make([]int, n)
+	for i := range x {
+		x[i] = bits.OnesCount(uint(i)) % 2
+		y[i] = 1 - x[i]
+	}
+	return x, y
+}
+
+func TestHashOfThueMorse(t *testing.T) {
+	// Complementary Thue–Morse sequences collide for every polynomial hash modulo 2^64.
+	x, y := thueMorse(1024)
+	a, b := New(x...), New(y...)
+	if a.Hash() == b.Hash() {
+		t.Error("Thue–Morse sequence and its complement have the same hash")
+	}
+	for _, count := range []int{256, 512} {
+		a.augment()
+		b.augment()
+		if prefixHash(a.root, count) == prefixHash(b.root, count) {
+			t.Errorf("Thue–Morse prefixes of %d values have the same hash", count)
+		}
+	}
+}
+
+func TestHashArithmetic(t *testing.T) {
+	r := rand.New(rand.NewPCG(63, 64))
+	modulus := big.NewInt(hashModulus)
+	edges := []uint64{0, 1, 2, hashModulus - 1, hashModulus - 2, 1 << 60}
+	for i := range 10000 {
+		a, b := r.Uint64N(hashModulus), r.Uint64N(hashModulus)
+		if i < len(edges)*len(edges) {
+			a, b = edges[i/len(edges)], edges[i%len(edges)]
+		}
+		want := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+		if got := hmul(a, b); got != want.Mod(want, modulus).Uint64() {
+			t.Fatalf("hmul(%d, %d) = %d, want %d", a, b, got, want)
+		}
+		if got := hsub(hadd(a, b), b); got != a {
+			t.Fatalf("hsub(hadd(%d, %d), %d) = %d", a, b, b, got)
+		}
+	}
+	if hashOf(-1) != hashModulus-1 || hadd(hashOf(-5), hashOf(5)) != 0 {
+		t.Error("negative values are not counted from the modulus")
+	}
+}
@@ -0,0 +1,100 @@
+package treap
+
+/*
+Callbacks invoked after every edit of the treap with the affected indexes, see `AddHooks()`.
+Nil callbacks are skipped.
+Callbacks are called inside of the mutating method,
+so they must not call methods of the treap itself.
+*/
+type Hooks struct {
+	// Count elements were inserted, so the 1st of them got the given index.
+	OnInsert func(index int, count int)
+	// Count elements starting from the given index were deleted, following elements moved to it.
+	OnDelete func(index int, count int)
+	// Elements from index_left to index_right got new values or their order was reversed.
+	OnRangeUpdate func(index_left int, index_right int)
+}
+
+/*
+Registers hooks, so they are called after every following edit of the treap,
+e.g. to bind the treap to a view or to replicate changes.
+Indexes are the same as in the treap right after the edit.
+Hooks are not carried over by `Merge()` and `Split()`.
+Hooks registered several times are called several times.
+
+	if h == nil: do nothing
+
+# Time complexity:
+  - Constant - amortized, requires single append;
+*/
+func (t *Treap) AddHooks(h *Hooks) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if h == nil {
+		return
+	}
+	t.observe(h)
+}
+
+/*
+Unregisters hooks, so they are no longer called.
+
+	if hooks are not registered: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of registered hooks, marks and annotations;
+*/
+func (t *Treap) RemoveHooks(h *Hooks) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.unobserve(h)
+}
+
+/*
+Calls insertion hook.
+
+# Time complexity:
+  - Constant - plus the time of the hook;
+*/
+func (h *Hooks) inserted(index int, count int) {
+	if h.OnInsert != nil {
+		h.OnInsert(index, count)
+	}
+}
+
+/*
+Calls deletion hook.
+
+# Time complexity:
+  - Constant - plus the time of the hook;
+*/
+func (h *Hooks) deleted(index int, count int) {
+	if h.OnDelete != nil {
+		h.OnDelete(index, count)
+	}
+}
+
+/*
+Calls range update hook.
+
+# Time complexity:
+  - Constant - plus the time of the hook;
+*/
+func (h *Hooks) updated(index int, count int) {
+	if h.OnRangeUpdate != nil {
+		h.OnRangeUpdate(index, index+count-1)
+	}
+}
+
+/*
+Calls range update hook, since reversed elements got new positions.
+
+# Time complexity:
+  - Constant - plus the time of the hook;
+*/
+func (h *Hooks) mirrored(index int, count int) {
+	h.updated(index, count)
+}
@@ -0,0 +1,67 @@
+package treap
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// Returns hooks recording every call as a string.
+func recordingHooks(calls *[]string) *Hooks {
+	return &Hooks{
+		OnInsert: func(index int, count int) {
+			*calls = append(*calls, fmt.Sprintf("insert %d %d", index, count))
+		},
+		OnDelete: func(index int, count int) {
+			*calls = append(*calls, fmt.Sprintf("delete %d %d", index, count))
+		},
+		OnRangeUpdate: func(index_left int, index_right int) {
+			*calls = append(*calls, fmt.Sprintf("update %d %d", index_left, index_right))
+		},
+	}
+}
+
+func TestHooks(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4)
+	var calls []string
+	h := recordingHooks(&calls)
+	tr.AddHooks(h)
+	tr.AddHooks(nil)
+	tr.Insert(9, 5)
+	tr.PushFront(7, 8)
+	tr.Cut(1, 3)
+	tr.Cut(10, 12)
+	tr.Set(0, 1)
+	tr.RangeAdd(1, 2, 3)
+	tr.ReverseRange(0, 3)
+	want := []string{
+		"insert 5 1",
+		"insert 0 2",
+		"delete 1 3",
+		"update 0 0",
+		"update 1 2",
+		"update 0 3",
+	}
+	if !slices.Equal(calls, want) {
+		t.Errorf("hooks are called as %q, want %q", calls, want)
+	}
+	tr.RemoveHooks(h)
+	tr.Insert(0, 0)
+	if len(calls) != len(want) {
+		t.Errorf("removed hooks are called: %q", calls[len(want):])
+	}
+}
+
+func TestHooksWithNilCallbacks(t *testing.T) {
+	tr := New(1, 2)
+	deleted := 0
+	h := &Hooks{OnDelete: func(index int, count int) { deleted += count }}
+	tr.AddHooks(h)
+	tr.AddHooks(h)
+	tr.Insert(0, 0)
+	tr.Set(0, 5)
+	tr.Delete(0)
+	if deleted != 2 {
+		t.Errorf("hooks registered twice are called for %d deletions, want 2", deleted)
+	}
+}
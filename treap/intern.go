@@ -0,0 +1,217 @@
+package treap
+
+/*
+Stores every distinct value once and gives it a small integer id.
+Intended for big repeated values like strings of logs and texts.
+Values are kept until the interner itself is dropped.
+
+Single interner can be shared by many `Interned` sequences.
+*/
+type Interner[T comparable] struct {
+	ids    map[T]int
+	values []T
+}
+
+/*
+Correctly initialize an empty interner.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func NewInterner[T comparable]() *Interner[T] {
+	return &Interner[T]{ids: make(map[T]int)}
+}
+
+/*
+Returns id of the value, storing the value if it is new.
+
+# Time complexity:
+  - Constant - amortized, requires single map lookup;
+*/
+func (in *Interner[T]) Intern(value T) int {
+	if id, ok := in.ids[value]; ok {
+		return id
+	}
+	id := len(in.values)
+	in.ids[value] = id
+	in.values = append(in.values, value)
+	return id
+}
+
+/*
+Returns value with provided id.
+
+	if id is unknown: return zero value
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (in *Interner[T]) Value(id int) (value T) {
+	if id < 0 || id >= len(in.values) {
+		return
+	}
+	return in.values[id]
+}
+
+/*
+Returns amount of distinct stored values.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (in *Interner[T]) Len() int {
+	return len(in.values)
+}
+
+/*
+Sequence of interned values.
+Treap stores only ids of the values, so identical values share the same memory.
+*/
+type Interned[T comparable] struct {
+	ids    Treap
+	values *Interner[T]
+}
+
+/*
+Correctly initialize a sequence using provided interner.
+Insert all given values to the back.
+
+	if in == nil: new interner is created
+
+# Time complexity:
+  - Loglinear - time complexity is equal to height of the treap multiplied by amount of provided values;
+*/
+func NewInterned[T comparable](in *Interner[T], values ...T) *Interned[T] {
+	if in == nil {
+		in = NewInterner[T]()
+	}
+	s := &Interned[T]{values: in}
+	s.PushBack(values...)
+	return s
+}
+
+/*
+Returns interner used by the sequence.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *Interned[T]) Interner() *Interner[T] {
+	return s.values
+}
+
+/*
+Returns treap of the value ids.
+Changing it changes the sequence.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *Interned[T]) Ids() *Treap {
+	return &s.ids
+}
+
+/*
+Insert value into provided index, same as `Insert()` of the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (s *Interned[T]) Insert(index int, value T) {
+	s.ids.Insert(index, s.values.Intern(value))
+}
+
+/*
+Insert all provided values to the front, same as `PushFront()` of the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (s *Interned[T]) PushFront(values ...T) {
+	s.ids.PushFront(s.intern(values)...)
+}
+
+/*
+Insert all provided values to the back, same as `PushBack()` of the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (s *Interned[T]) PushBack(values ...T) {
+	s.ids.PushBack(s.intern(values)...)
+}
+
+/*
+Returns ids of all provided values.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func (s *Interned[T]) intern(values []T) []int {
+	ids := make([]int, len(values))
+	for i, value := range values {
+		ids[i] = s.values.Intern(value)
+	}
+	return ids
+}
+
+/*
+Delete all elements in the given range, same as `Cut()` of the treap.
+Deleted values stay in the interner.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (s *Interned[T]) Cut(index_left int, index_right int) {
+	s.ids.Cut(index_left, index_right)
+}
+
+/*
+Delete 1 element by provided index, same as `Delete()` of the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (s *Interned[T]) Delete(index int) {
+	s.ids.Delete(index)
+}
+
+/*
+Returns size of the sequence.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *Interned[T]) Size() int {
+	return s.ids.Size()
+}
+
+/*
+Return the element on the given index.
+
+	if index out of range: return zero value
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (s *Interned[T]) Find(index int) (value T) {
+	if index < 0 || index >= s.ids.Size() {
+		return
+	}
+	return s.values.Value(s.ids.Find(index))
+}
+
+/*
+Returns all values of the sequence as a slice.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the sequence;
+*/
+func (s *Interned[T]) Export() []T {
+	ids := s.ids.Export()
+	values := make([]T, len(ids))
+	for i, id := range ids {
+		values[i] = s.values.Value(id)
+	}
+	return values
+}
@@ -0,0 +1,52 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInterner(t *testing.T) {
+	in := NewInterner[string]()
+	a, b := in.Intern("a"), in.Intern("b")
+	if a == b || in.Intern("a") != a {
+		t.Errorf("Intern() ids are %d, %d and %d", a, b, in.Intern("a"))
+	}
+	if in.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", in.Len())
+	}
+	if got := in.Value(b); got != "b" {
+		t.Errorf("Value(%d) = %q, want %q", b, got, "b")
+	}
+	if got := in.Value(-1) + in.Value(2); got != "" {
+		t.Errorf("Value() of unknown ids = %q", got)
+	}
+}
+
+func TestInterned(t *testing.T) {
+	in := NewInterner[string]()
+	s := NewInterned(in, "x", "y", "x")
+	s.PushBack("z")
+	s.PushFront("y")
+	s.Insert(2, "x")
+	if got, want := s.Export(), []string{"y", "x", "x", "y", "x", "z"}; !slices.Equal(got, want) {
+		t.Fatalf("Export() = %v, want %v", got, want)
+	}
+	if in.Len() != 3 {
+		t.Errorf("Len() of the interner = %d, want 3", in.Len())
+	}
+	other := NewInterned(in, "z", "w")
+	if got := other.Ids().Find(0); got != in.Intern("z") {
+		t.Errorf("shared interner gives id %d to %q, want %d", got, "z", in.Intern("z"))
+	}
+	s.Cut(1, 2)
+	s.Delete(0)
+	if got, want := s.Export(), []string{"y", "x", "z"}; !slices.Equal(got, want) {
+		t.Errorf("Export() after Cut() and Delete() = %v, want %v", got, want)
+	}
+	if s.Size() != 3 || s.Find(2) != "z" || s.Find(3) != "" {
+		t.Errorf("Size() = %d, Find(2) = %q, Find(3) = %q", s.Size(), s.Find(2), s.Find(3))
+	}
+	if s.Interner() != in {
+		t.Error("Interner() returns another interner")
+	}
+}
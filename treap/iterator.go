@@ -0,0 +1,274 @@
+package treap
+
+import (
+	"fmt"
+	"iter"
+)
+
+/*
+Bidirectional cursor over the elements of the treap.
+Keeps the path from the root to the current node on its stack,
+so stepping in both directions is constant in amortized time and no export is needed.
+Besides the elements, iterator can be before the first element (index -1) or after the last one (index equal to size).
+
+Treap must not be changed while the iterator is used,
+iterator panics with `ErrConcurrentModification` if the treap was changed or consumed after its creation.
+*/
+type Iterator struct {
+	t       *Treap
+	version uint64
+	stack   []*node
+	index   int
+}
+
+/*
+Creates new iterator on the given index.
+
+	if index < 0: iterator is placed before the 1st element
+	if index >= size of the treap: iterator is placed after the last element
+	if t == nil: return nil
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Iterator(index int) *Iterator {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	v := t.enter()
+	it := &Iterator{t: t, version: t.version}
+	it.seek(index)
+	t.exit(v)
+	return it
+}
+
+/*
+Reports whether iterator is on an element.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (it *Iterator) Valid() bool {
+	return len(it.stack) > 0
+}
+
+/*
+Returns index of the current element.
+
+	if iterator is before the 1st element: return -1
+	if iterator is after the last element: return size of the treap
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (it *Iterator) Index() int {
+	return it.index
+}
+
+/*
+Returns value of the current element.
+
+	if iterator is not on an element: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (it *Iterator) Value() int {
+	it.check()
+	if len(it.stack) == 0 {
+		return 0
+	}
+	return it.stack[len(it.stack)-1].value
+}
+
+/*
+Moves iterator to the following element.
+Reports whether iterator is on an element afterwards.
+
+	if iterator is before the 1st element: move to the 1st element
+	if iterator is after the last element: do nothing
+
+# Time complexity:
+  - Constant - amortized, each node is visited only twice by the full walk;
+*/
+func (it *Iterator) Next() bool {
+	it.check()
+	v := it.t.enter()
+	if len(it.stack) > 0 {
+		it.step(false)
+	} else if it.index < 0 {
+		it.seek(0)
+	}
+	it.t.exit(v)
+	return len(it.stack) > 0
+}
+
+/*
+Moves iterator to the previous element, same as `Next()`.
+
+	if iterator is after the last element: move to the last element
+	if iterator is before the 1st element: do nothing
+
+# Time complexity:
+  - Constant - amortized, each node is visited only twice by the full walk;
+*/
+func (it *Iterator) Prev() bool {
+	it.check()
+	v := it.t.enter()
+	if len(it.stack) > 0 {
+		it.step(true)
+	} else if it.index >= 0 {
+		it.seek(it.index - 1)
+	}
+	it.t.exit(v)
+	return len(it.stack) > 0
+}
+
+/*
+Moves iterator to the given index, same as new iterator.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (it *Iterator) Seek(index int) {
+	it.check()
+	v := it.t.enter()
+	it.seek(index)
+	it.t.exit(v)
+}
+
+/*
+Implementation of the `Seek()` method.
+Stack holds the whole path from the root to the node on the index, found by `descend()`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (it *Iterator) seek(index int) {
+	length := size(it.t.root)
+	if index < 0 || index >= length {
+		it.stack = it.stack[:0]
+		it.index = max(-1, min(index, length))
+		return
+	}
+	it.index = index
+	_, it.stack = descend(it.t.root, index, it.stack[:0], true)
+}
+
+/*
+Moves iterator to the neighbour node, to the previous one if backward is true.
+Iterator must be on an element.
+
+	if there is no neighbour: stack becomes empty
+
+# Time complexity:
+  - Constant - amortized, each node is visited only twice by the full walk;
+*/
+func (it *Iterator) step(backward bool) {
+	n := it.stack[len(it.stack)-1]
+	if backward {
+		it.index--
+	} else {
+		it.index++
+	}
+	son := n.rson
+	if backward {
+		son = n.lson
+	}
+	if son != nil {
+		// Neighbour is the closest node of the son's subtree.
+		for m := son; m != nil; {
+			push(m)
+			it.stack = append(it.stack, m)
+			if backward {
+				m = m.rson
+			} else {
+				m = m.lson
+			}
+		}
+		return
+	}
+	// Neighbour is the closest ancestor, that is reached from the other side.
+	for {
+		it.stack = it.stack[:len(it.stack)-1]
+		if len(it.stack) == 0 {
+			return
+		}
+		parent := it.stack[len(it.stack)-1]
+		if (!backward && parent.lson == n) || (backward && parent.rson == n) {
+			return
+		}
+		n = parent
+	}
+}
+
+/*
+Panics if the treap was changed or consumed after creation of the iterator.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (it *Iterator) check() {
+	if it.t.consumed || it.t.version != it.version {
+		panic(fmt.Errorf("%w: treap was changed while iterated", ErrConcurrentModification))
+	}
+}
+
+/*
+Returns iterator over all indexes and values of the treap in order,
+so the treap can be used in range-over-func loops.
+Treap must not be changed by the loop, same as with `Iterator`.
+
+	if t == nil: yield nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) All() iter.Seq2[int, int] {
+	return t.walk(false)
+}
+
+/*
+Returns iterator over all indexes and values of the treap from the last element to the first, same as `All()`.
+
+	if t == nil: yield nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) Backward() iter.Seq2[int, int] {
+	return t.walk(true)
+}
+
+/*
+Implementation of the `All()` and `Backward()` methods.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) walk(reverse bool) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		if t == nil {
+			nilReceiver()
+			return
+		}
+		it := Iterator{t: t, version: t.version}
+		var w walker
+		v := t.enter()
+		index, step := 0, 1
+		if reverse {
+			index, step = size(t.root)-1, -1
+		}
+		w.seek(t.root, index, reverse)
+		n := w.next()
+		t.exit(v)
+		for n != nil && yield(index, n.value) {
+			it.check()
+			v := t.enter()
+			n = w.next()
+			t.exit(v)
+			index += step
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	r := rand.New(rand.NewPCG(39, 40))
+	model := make([]int, 200)
+	for i := range model {
+		model[i] = r.IntN(1000)
+	}
+	tr := New(model...)
+	tr.ReverseRange(20, 150)
+	for i, j := 20, 150; i < j; i, j = i+1, j-1 {
+		model[i], model[j] = model[j], model[i]
+	}
+	it := tr.Iterator(-5)
+	if it.Valid() || it.Index() != -1 || it.Value() != 0 {
+		t.Fatalf("iterator before the 1st element is on %d", it.Index())
+	}
+	for i, want := range model {
+		if !it.Next() || it.Index() != i || it.Value() != want {
+			t.Fatalf("Next() is on %d = %d, want %d = %d", it.Index(), it.Value(), i, want)
+		}
+	}
+	if it.Next() || it.Index() != len(model) || it.Next() {
+		t.Fatalf("iterator after the last element is on %d", it.Index())
+	}
+	for i := len(model) - 1; i >= 0; i-- {
+		if !it.Prev() || it.Index() != i || it.Value() != model[i] {
+			t.Fatalf("Prev() is on %d = %d, want %d = %d", it.Index(), it.Value(), i, model[i])
+		}
+	}
+	if it.Prev() || it.Index() != -1 {
+		t.Fatalf("Prev() from the 1st element is on %d", it.Index())
+	}
+	for range 100 {
+		i := r.IntN(len(model)+4) - 2
+		it.Seek(i)
+		if i >= 0 && i < len(model) && it.Value() != model[i] {
+			t.Fatalf("Seek(%d) is on %d", i, it.Value())
+		}
+		if r.IntN(2) == 0 && it.Next() && it.Value() != model[i+1] {
+			t.Fatalf("Next() after Seek(%d) is on %d", i, it.Value())
+		}
+	}
+	if it := tr.Iterator(len(model) + 3); it.Valid() || it.Index() != len(model) {
+		t.Errorf("Iterator(%d) is on %d", len(model)+3, it.Index())
+	}
+}
+
+func TestIteratorConcurrentModification(t *testing.T) {
+	tr := New(1, 2, 3)
+	it := tr.Iterator(0)
+	tr.Insert(0, 0)
+	err, _ := recovered(func() { it.Next() }).(error)
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Errorf("Next() after Insert() = %v, want ErrConcurrentModification", err)
+	}
+	empty := New()
+	if it := empty.Iterator(0); it.Valid() || it.Next() || it.Prev() {
+		t.Errorf("iterator of an empty treap is on %d", it.Index())
+	}
+}
+
+func TestAllAndBackward(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.ReverseRange(1, 3)
+	values := tr.Export()
+	var forward, backward []int
+	for index, value := range tr.All() {
+		if value != values[index] {
+			t.Fatalf("All() yields %d on index %d", value, index)
+		}
+		forward = append(forward, value)
+	}
+	for index, value := range tr.Backward() {
+		if value != values[index] {
+			t.Fatalf("Backward() yields %d on index %d", value, index)
+		}
+		backward = append(backward, value)
+	}
+	if !slices.Equal(forward, []int{1, 4, 3, 2, 5}) || !slices.Equal(backward, []int{5, 2, 3, 4, 1}) {
+		t.Errorf("All() = %v, Backward() = %v", forward, backward)
+	}
+	visited := 0
+	for range tr.Backward() {
+		visited++
+		if visited == 2 {
+			break
+		}
+	}
+	if visited != 2 {
+		t.Errorf("Backward() visits %d elements after break, want 2", visited)
+	}
+	err, _ := recovered(func() {
+		for index := range tr.All() {
+			tr.Set(index, 0)
+		}
+	}).(error)
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Errorf("All() with a changing loop = %v, want ErrConcurrentModification", err)
+	}
+	var empty *Treap
+	for range empty.All() {
+		t.Error("All() of a nil treap yields an element")
+	}
+}
@@ -0,0 +1,288 @@
+package treap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+/*
+Returned when the journal record is corrupted or describes invalid operation.
+*/
+var ErrBadJournal = errors.New("treap: malformed journal")
+
+/*
+Write-ahead journal of the treap.
+Every record is written before the change is applied:
+
+	kind byte, uint64 index, uint64 count, uint32 CRC-32 of the header, [count]int64 inserted values, uint32 CRC-32 of the record
+
+All numbers are little-endian, records are written one after another without a header.
+Header of the record has its own checksum, so a corrupted count is detected before the values are read.
+*/
+type journal struct {
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+/*
+Size of the record without inserted values.
+*/
+const journalRecord = 1 + 8 + 8 + 4 + 4
+
+/*
+Maximum amount of inserted values read from the journal at once, see `readRecord()`.
+*/
+const journalChunk = 1 << 12
+
+/*
+Enables write-ahead journal, so every following change of the treap is written into provided writer
+before it is applied. Written journal can be replayed by `Recover()`.
+Each change is written by a single Write call and is not synced,
+writer is responsible for durability, e.g. by syncing the file.
+
+Journal starts with a snapshot of all current elements, so it must be written into a new file.
+After recovery, journal of the recovered treap should be started in a new file, which compacts the old one.
+
+If writing fails, the change is not applied and every following change is rejected,
+error is reported by `JournalErr()` and `Apply()`.
+Journal is not carried over by `Merge()` and `Split()`.
+
+	if w == nil: disable journal
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) SetJournal(w io.Writer) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if w == nil {
+		t.journal = nil
+		return
+	}
+	t.journal = &journal{w: w}
+	if t.root != nil {
+		v := t.enter()
+		values := make([]int, t.root.size)
+		export(values, 0, t.root)
+		t.log(InsertOp(0, values...))
+		t.exit(v)
+	}
+}
+
+/*
+Returns the error, that stopped the journal.
+
+	if journal is disabled or has no errors: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) JournalErr() error {
+	if t == nil || t.journal == nil {
+		return nil
+	}
+	return t.journal.err
+}
+
+/*
+Writes the operation into the journal and all active logs. Reports whether the operation can be applied.
+
+	if journal and logs are disabled: return true
+	if operation is empty: return true without writing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of inserted values multiplied by amount of active logs;
+*/
+func (t *Treap) log(op Op) bool {
+	if !t.logging() {
+		return true
+	} else if (op.Kind == OpInsert && len(op.Values) == 0) || (op.Kind == OpDelete && op.Count == 0) {
+		return true
+	}
+	if j := t.journal; j != nil {
+		if j.err != nil {
+			return false
+		}
+		j.buf = appendRecord(j.buf[:0], op)
+		if _, err := j.w.Write(j.buf); err != nil {
+			j.err = fmt.Errorf("treap: journal write: %w", err)
+			return false
+		}
+	}
+	for _, l := range t.logs {
+		// Operation is copied field by field, so provided values never escape when nothing is logged.
+		l.ops = append(l.ops, Op{Kind: op.Kind, Index: op.Index, Values: append([]int(nil), op.Values...), Count: op.Count})
+	}
+	return true
+}
+
+/*
+Writes replacement of the elements starting from the given index by provided values, same as `log()`.
+Replacement is written as deletion and insertion by a single Write call,
+so the journal never contains only half of it.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of values multiplied by amount of active logs;
+*/
+func (t *Treap) logReplace(index int, values ...int) bool {
+	if !t.logging() {
+		return true
+	} else if len(values) == 0 {
+		return true
+	}
+	ops := [2]Op{DeleteOp(index, len(values)), InsertOp(index, values...)}
+	if j := t.journal; j != nil {
+		if j.err != nil {
+			return false
+		}
+		j.buf = appendRecord(appendRecord(j.buf[:0], ops[0]), ops[1])
+		if _, err := j.w.Write(j.buf); err != nil {
+			j.err = fmt.Errorf("treap: journal write: %w", err)
+			return false
+		}
+	}
+	for _, l := range t.logs {
+		l.ops = append(l.ops, DeleteOp(index, len(values)), InsertOp(index, append([]int(nil), values...)...))
+	}
+	return true
+}
+
+/*
+Reports whether changes are written into the journal or any log.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) logging() bool {
+	return t.journal != nil || len(t.logs) > 0
+}
+
+/*
+Appends encoded record of the operation.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of inserted values;
+*/
+func appendRecord(buf []byte, op Op) []byte {
+	count := op.Count
+	if op.Kind == OpInsert {
+		count = len(op.Values)
+	}
+	start := len(buf)
+	buf = append(buf, byte(op.Kind))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(op.Index))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(count))
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf[start:]))
+	for _, value := range op.Values {
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(value))
+	}
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf[start:]))
+}
+
+/*
+Reads single encoded record of the operation.
+Count is trusted only after the checksum of the header,
+and inserted values are read in chunks, so the memory grows only as the values actually arrive.
+
+	if reader is empty: return io.EOF
+	if record is incomplete: return io.ErrUnexpectedEOF
+	if record is corrupted: return ErrBadJournal
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of inserted values;
+*/
+func readRecord(r io.Reader) (Op, error) {
+	head := make([]byte, journalRecord-4)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return Op{}, err
+	} else if binary.LittleEndian.Uint32(head[17:]) != crc32.ChecksumIEEE(head[:17]) {
+		return Op{}, fmt.Errorf("%w: checksum mismatch of the header", ErrBadJournal)
+	}
+	op := Op{Kind: OpKind(head[0]), Index: int(binary.LittleEndian.Uint64(head[1:]))}
+	count := int(binary.LittleEndian.Uint64(head[9:]))
+	if count <= 0 || (op.Kind == OpInsert && count > maxFrozenSize) {
+		return Op{}, fmt.Errorf("%w: record with %d elements", ErrBadJournal, count)
+	}
+	sum := crc32.NewIEEE()
+	sum.Write(head)
+	if op.Kind == OpInsert {
+		op.Values = make([]int, 0, min(count, journalChunk))
+		chunk := make([]byte, 8*min(count, journalChunk))
+		for len(op.Values) < count {
+			body := chunk[:8*min(count-len(op.Values), journalChunk)]
+			if _, err := io.ReadFull(r, body); err != nil {
+				return Op{}, unexpected(err)
+			}
+			sum.Write(body)
+			for i := 0; i < len(body); i += 8 {
+				op.Values = append(op.Values, int(binary.LittleEndian.Uint64(body[i:])))
+			}
+		}
+	} else {
+		op.Count = count
+	}
+	tail := make([]byte, 4)
+	if _, err := io.ReadFull(r, tail); err != nil {
+		return Op{}, unexpected(err)
+	} else if binary.LittleEndian.Uint32(tail) != sum.Sum32() {
+		return Op{}, fmt.Errorf("%w: checksum mismatch", ErrBadJournal)
+	}
+	return op, nil
+}
+
+/*
+Converts io.EOF in the middle of the record into io.ErrUnexpectedEOF.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+/*
+Rebuilds the treap by replaying the journal written by `SetJournal()`.
+Incomplete last record is ignored, since its change was never applied.
+Returned treap has default configuration and no journal.
+
+	if reading fails: return the treap replayed so far and the error
+	if any record is corrupted or invalid: return the treap replayed so far and ErrBadJournal
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of inserted values multiplied by height of the treap;
+*/
+func Recover(r io.Reader) (Treap, error) {
+	t := New()
+	for {
+		op, err := readRecord(r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return t, nil
+		} else if err != nil {
+			return t, err
+		} else if err := t.Apply(op); err != nil {
+			return t, fmt.Errorf("%w: %w", ErrBadJournal, err)
+		}
+	}
+}
+
+/*
+Returns copy of the values in reversed order.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of values;
+*/
+func reversed(values []int) []int {
+	result := make([]int, len(values))
+	for i, value := range values {
+		result[len(values)-1-i] = value
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package treap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestJournalRecover(t *testing.T) {
+	r := rand.New(rand.NewPCG(17, 18))
+	tr := New(1, 2, 3)
+	var buf bytes.Buffer
+	tr.SetJournal(&buf)
+	for step := range 500 {
+		n := tr.Size()
+		switch r.IntN(5) {
+		case 0:
+			tr.Insert(r.IntN(n+1), step)
+		case 1:
+			tr.PushFront(step, -step)
+		case 2:
+			if n > 0 {
+				l := r.IntN(n)
+				tr.Cut(l, l+r.IntN(min(3, n-l)))
+			}
+		case 3:
+			if n > 0 {
+				tr.Set(r.IntN(n), -step)
+			}
+		case 4:
+			if n > 0 {
+				l := r.IntN(n)
+				tr.ReverseRange(l, l+r.IntN(n-l))
+			}
+		}
+	}
+	recovered, err := Recover(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := recovered.Export(), tr.Export(); !slices.Equal(got, want) {
+		t.Fatalf("Recover() = %v, want %v", got, want)
+	}
+
+	// Torn last record was never applied, so it is ignored.
+	last := buf.Len()
+	tr.Insert(0, 100)
+	torn := buf.Bytes()[:buf.Len()-1]
+	if recovered, err := Recover(bytes.NewReader(torn)); err != nil || recovered.Size() != tr.Size()-1 {
+		t.Errorf("Recover() of a torn journal = size %d, %v", recovered.Size(), err)
+	}
+	damaged := slices.Clone(buf.Bytes())
+	damaged[last+1] ^= 1
+	if _, err := Recover(bytes.NewReader(damaged)); !errors.Is(err, ErrBadJournal) {
+		t.Errorf("Recover() of a damaged journal = %v, want ErrBadJournal", err)
+	}
+}
+
+// Writer that fails all writes.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk is full")
+}
+
+func TestJournalFailure(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.SetJournal(failingWriter{})
+	if err := tr.JournalErr(); err == nil {
+		t.Fatal("JournalErr() = nil after the failed snapshot")
+	}
+	tr.Insert(0, 0)
+	tr.Set(0, 9)
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Export() after failed journal = %v, want [1 2 3]", got)
+	}
+	if err := tr.Apply(DeleteOp(0, 1)); err == nil || err.Error() != tr.JournalErr().Error() {
+		t.Errorf("Apply() = %v, want %v", err, tr.JournalErr())
+	}
+	tr.SetJournal(nil)
+	tr.Insert(0, 0)
+	if tr.JournalErr() != nil || tr.Size() != 4 {
+		t.Errorf("disabled journal still rejects changes: %v", tr.JournalErr())
+	}
+}
+
+// Returns header of an insertion record, that claims provided amount of values, with a valid checksum.
+func insertHeader(count uint64) []byte {
+	head := []byte{byte(OpInsert)}
+	head = binary.LittleEndian.AppendUint64(head, 0)
+	head = binary.LittleEndian.AppendUint64(head, count)
+	return binary.LittleEndian.AppendUint32(head, crc32.ChecksumIEEE(head))
+}
+
+func TestRecoverCorruptedLength(t *testing.T) {
+	tr := New(1, 2, 3)
+	var buf bytes.Buffer
+	tr.SetJournal(&buf)
+	valid := buf.Len()
+	tr.Insert(0, 4)
+	tr.Insert(0, 5)
+
+	// Corrupted length is caught by the checksum of the header before the values are read.
+	journal := slices.Clone(buf.Bytes())
+	binary.LittleEndian.PutUint64(journal[valid+9:], 1<<45)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	recovered, err := Recover(bytes.NewReader(journal))
+	runtime.ReadMemStats(&after)
+	if !errors.Is(err, ErrBadJournal) || !slices.Equal(recovered.Export(), []int{1, 2, 3}) {
+		t.Errorf("Recover() of a journal with a corrupted length = %v, %v, want ErrBadJournal", recovered.Export(), err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("Recover() of a corrupted length allocated %d bytes", allocated)
+	}
+
+	// Torn record claiming a huge amount of values allocates only for the values it holds.
+	journal = append(slices.Clone(buf.Bytes()[:valid]), insertHeader(1<<45)...)
+	journal = append(journal, make([]byte, 100)...)
+	runtime.ReadMemStats(&before)
+	recovered, err = Recover(bytes.NewReader(journal))
+	runtime.ReadMemStats(&after)
+	if err != nil || !slices.Equal(recovered.Export(), []int{1, 2, 3}) {
+		t.Errorf("Recover() of a journal with a torn huge record = %v, %v", recovered.Export(), err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("Recover() of a torn huge record allocated %d bytes", allocated)
+	}
+}
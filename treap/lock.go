@@ -0,0 +1,174 @@
+package treap
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+Returned when the edit touches the range locked by another owner.
+Error message also contains the conflicting range.
+*/
+var ErrLocked = errors.New("treap: range is locked by another owner")
+
+/*
+Advisory range locks of the treap, for example to serialize conflicting writes
+of the collaborative document per region instead of globally.
+Locks are kept as annotations, so their ranges follow all edits of the treap.
+
+Locks are advisory: only edits made by `Apply()` of the locks are checked.
+Owners are compared by `==`, so they must be comparable.
+*/
+type Locks struct {
+	ranges *Annotations
+}
+
+/*
+Creates new empty set of range locks of the treap.
+
+	if t == nil: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Locks() *Locks {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	return &Locks{ranges: t.Annotate()}
+}
+
+/*
+Locks all elements from index_left to index_right for provided owner. Returns id of the lock.
+Owner can lock overlapping ranges several times.
+
+	if lock set is detached: return ErrNilTreap
+	if index_left > index_right or any index out of range: return ErrIndexOutOfRange
+	if any element is locked by another owner: return ErrLocked
+
+# Time complexity:
+  - Logarithmic - plus amount of overlapping locks multiplied by height of the lock treap;
+*/
+func (l *Locks) LockRange(index_left int, index_right int, owner any) (int, error) {
+	if err := l.conflict(l.ranges.Overlapping(index_left, index_right), owner, nil); err != nil {
+		return 0, err
+	}
+	return l.ranges.Add(index_left, index_right, owner)
+}
+
+/*
+Removes the lock with provided id.
+
+	if id is unknown: return false
+
+# Time complexity:
+  - Constant - amortized, same as `Remove()` of the annotations;
+*/
+func (l *Locks) Unlock(id int) bool {
+	return l.ranges.Remove(id)
+}
+
+/*
+Removes all locks of the owner. Returns amount of removed locks.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of locks;
+*/
+func (l *Locks) UnlockAll(owner any) int {
+	count := 0
+	for _, a := range l.ranges.All() {
+		if a.Tag == owner {
+			l.ranges.Remove(a.ID)
+			count++
+		}
+	}
+	return count
+}
+
+/*
+Returns all locks ordered by their left index. Owner of the lock is stored as its tag.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of locks;
+*/
+func (l *Locks) All() []Annotation {
+	return l.ranges.All()
+}
+
+/*
+Checks whether operation made by the owner conflicts with locks of other owners.
+Insertion conflicts with the lock, if it inserts elements between 2 locked elements,
+deletion conflicts with the lock, if it deletes any locked element.
+
+	if operation conflicts: return ErrLocked
+
+# Time complexity:
+  - Logarithmic - plus amount of overlapping locks multiplied by height of the lock treap;
+*/
+func (l *Locks) Check(op Op, owner any) error {
+	switch op.Kind {
+	case OpInsert:
+		if op.Index <= 0 {
+			return nil
+		}
+		// Insertion only extends locks containing both neighbours of the index.
+		return l.conflict(l.ranges.At(op.Index), owner, func(a Annotation) bool {
+			return a.Left < op.Index
+		})
+	case OpDelete:
+		if op.Count <= 0 {
+			return nil
+		}
+		return l.conflict(l.ranges.Overlapping(op.Index, op.Index+op.Count-1), owner, nil)
+	}
+	return nil
+}
+
+/*
+Applies the operation on behalf of the owner, same as `Apply()` of the treap.
+
+	if lock set is detached: return ErrNilTreap
+	if operation conflicts with locks of other owners: return ErrLocked
+	otherwise: return error of `Apply()` of the treap
+
+Nothing is changed if error is returned.
+
+# Time complexity:
+  - Logarithmic - plus time complexity of `Apply()` of the treap;
+*/
+func (l *Locks) Apply(op Op, owner any) error {
+	if l.ranges.t == nil {
+		return ErrNilTreap
+	} else if err := l.Check(op, owner); err != nil {
+		return err
+	}
+	return l.ranges.t.Apply(op)
+}
+
+/*
+Stops following edits of the treap, same as `Detach()` of the annotations.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of layers of the treap;
+*/
+func (l *Locks) Detach() {
+	l.ranges.Detach()
+}
+
+/*
+Returns ErrLocked for the 1st lock of another owner, that matches the filter.
+
+	if filter == nil: every lock matches
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided locks;
+*/
+func (l *Locks) conflict(locks []Annotation, owner any, filter func(Annotation) bool) error {
+	for _, a := range locks {
+		if a.Tag != owner && (filter == nil || filter(a)) {
+			return fmt.Errorf("%w: range [%d, %d]", ErrLocked, a.Left, a.Right)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package treap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLockRange(t *testing.T) {
+	tr := New(make([]int, 10)...)
+	l := tr.Locks()
+	if _, err := l.LockRange(2, 4, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.LockRange(3, 6, "alice"); err != nil {
+		t.Errorf("LockRange() overlapping own lock = %v", err)
+	}
+	if _, err := l.LockRange(6, 8, "bob"); !errors.Is(err, ErrLocked) {
+		t.Errorf("LockRange() overlapping another lock = %v, want ErrLocked", err)
+	}
+	bob, err := l.LockRange(7, 8, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.UnlockAll("alice"); got != 2 {
+		t.Errorf("UnlockAll() = %d, want 2", got)
+	}
+	if !l.Unlock(bob) || l.Unlock(bob) || len(l.All()) != 0 {
+		t.Errorf("Unlock() does not remove the lock once, %v left", l.All())
+	}
+}
+
+func TestLocksApply(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5)
+	l := tr.Locks()
+	l.LockRange(2, 3, "alice")
+	for _, tc := range []struct {
+		op     Op
+		owner  any
+		locked bool
+	}{
+		{InsertOp(3, 9), "bob", true},
+		{InsertOp(2, 9), "bob", false},
+		{InsertOp(5, 9), "bob", false},
+		{DeleteOp(3, 2), "bob", true},
+		{InsertOp(6, 9), "alice", false},
+		{DeleteOp(0, 2), "bob", false},
+		{DeleteOp(0, 0), "bob", false},
+	} {
+		before := tr.Export()
+		err := l.Apply(tc.op, tc.owner)
+		if tc.locked != errors.Is(err, ErrLocked) {
+			t.Fatalf("Apply(%v, %v) = %v, locked %v", tc.op, tc.owner, err, tc.locked)
+		} else if tc.locked && !slices.Equal(tr.Export(), before) {
+			t.Fatalf("Apply(%v, %v) changed the treap", tc.op, tc.owner)
+		}
+	}
+	if got, want := boundsOf(l.All()), [][2]int{{1, 2}}; !slices.Equal(got, want) {
+		t.Errorf("locks after edits = %v, want %v", got, want)
+	}
+	l.Detach()
+	if err := l.Apply(InsertOp(0, 1), "bob"); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("Apply() of detached locks = %v, want ErrNilTreap", err)
+	}
+}
@@ -0,0 +1,276 @@
+package treap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Returned when saved mark is restored into a treap with different version.
+See `Version()`.
+*/
+var ErrStaleMark = errors.New("treap: mark was saved for another version")
+
+/*
+Behaviour of the mark, when elements are inserted exactly at its index.
+*/
+type Bias int
+
+const (
+	// Mark keeps its index, so inserted elements are placed after it.
+	StickLeft Bias = iota
+	// Mark moves after inserted elements, like a cursor while typing.
+	StickRight
+)
+
+/*
+Position in the treap, that is maintained across edits,
+for example a cursor, bookmark or breakpoint.
+Mark points before the element with its index, so index is from 0 to size of the treap:
+  - elements inserted before the mark shift it, at its index - shift it depending on the bias;
+  - elements deleted before the mark shift it, around the mark - move it to the 1st deleted index.
+
+Mark is not carried over by `Merge()` and `Split()` and must not be used after the treap is consumed.
+*/
+type Mark struct {
+	t     *Treap
+	index int
+	bias  Bias
+}
+
+/*
+Creates new mark on the given index with `StickLeft` bias.
+
+	if index < 0: mark is placed before the 1st element
+	if index > size of the treap: mark is placed after the last element
+	if t == nil: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Mark(index int) *Mark {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	m := &Mark{t: t, index: max(0, min(index, t.Size()))}
+	t.observe(m)
+	return m
+}
+
+/*
+Returns current index of the mark.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) Index() int {
+	return m.index
+}
+
+/*
+Moves mark to the given index, clamped same as in `Mark()`.
+
+	if mark is released: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) Move(index int) {
+	if m.t == nil {
+		return
+	}
+	m.index = max(0, min(index, m.t.Size()))
+}
+
+/*
+Returns bias of the mark.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) Bias() Bias {
+	return m.bias
+}
+
+/*
+Changes behaviour of the mark on insertion at its index.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) SetBias(bias Bias) {
+	m.bias = bias
+}
+
+/*
+Stops maintaining the mark, its index is no longer changed by edits.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of marks and layers of the treap;
+*/
+func (m *Mark) Release() {
+	if m.t != nil {
+		m.t.unobserve(m)
+		m.t = nil
+	}
+}
+
+/*
+Adjusts index after insertion.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) inserted(index int, count int) {
+	if index < m.index || (index == m.index && m.bias == StickRight) {
+		m.index += count
+	}
+}
+
+/*
+Adjusts index after deletion.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) deleted(index int, count int) {
+	if index < m.index {
+		m.index = max(index, m.index-count)
+	}
+}
+
+/*
+Values do not move the elements, so index is not adjusted.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) updated(index int, count int) {}
+
+/*
+Moves the index to the new position of the same element after reversal.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) mirrored(index int, count int) {
+	if index <= m.index && m.index < index+count {
+		m.index = 2*index + count - 1 - m.index
+	}
+}
+
+/*
+Returns amount of changes made to the treap, every insertion, deletion and change of values increments it.
+Version identifies the state of the treap, e.g. for restoring saved marks, see `MarkState`.
+Result of `Merge()` and `Split()` starts from version 0.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Version() uint64 {
+	if t == nil {
+		nilReceiver()
+		return 0
+	}
+	return t.version
+}
+
+/*
+Sets version of the treap, for example when treap is loaded from the file
+together with the version it had when it was saved.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SetVersion(version uint64) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.version = version
+}
+
+/*
+Serializable state of the mark: its index and bias, and version of the treap it was saved for.
+Implements encoding.TextMarshaler and encoding.TextUnmarshaler,
+so it can be stored together with the document, e.g. in JSON as "index:bias@version".
+*/
+type MarkState struct {
+	Index   int
+	Bias    Bias
+	Version uint64
+}
+
+/*
+Returns current state of the mark.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (m *Mark) State() MarkState {
+	s := MarkState{Index: m.index, Bias: m.bias}
+	if m.t != nil {
+		s.Version = m.t.version
+	}
+	return s
+}
+
+/*
+Creates new mark from the saved state.
+
+	if version of the treap is not equal to the saved one: return ErrStaleMark
+	if saved index is not from 0 to size of the treap: return ErrIndexOutOfRange
+	if t == nil: return ErrNilTreap
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) RestoreMark(s MarkState) (*Mark, error) {
+	if t == nil {
+		if err := nilReceiver(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNilTreap
+	} else if s.Version != t.version {
+		return nil, fmt.Errorf("%w: saved %d, current %d", ErrStaleMark, s.Version, t.version)
+	} else if size := t.Size(); s.Index < 0 || s.Index > size {
+		return nil, fmt.Errorf("%w: mark index %d with size %d", ErrIndexOutOfRange, s.Index, size)
+	}
+	m := t.Mark(s.Index)
+	m.bias = s.Bias
+	return m, nil
+}
+
+/*
+Encodes state as "index:bias@version".
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s MarkState) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "%d:%d@%d", s.Index, s.Bias, s.Version), nil
+}
+
+/*
+Decodes state encoded by `MarshalText()`.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s *MarkState) UnmarshalText(text []byte) error {
+	index, rest, ok1 := strings.Cut(string(text), ":")
+	bias, version, ok2 := strings.Cut(rest, "@")
+	i, err1 := strconv.Atoi(index)
+	b, err2 := strconv.Atoi(bias)
+	v, err3 := strconv.ParseUint(version, 10, 64)
+	if !ok1 || !ok2 || err1 != nil || err2 != nil || err3 != nil {
+		return fmt.Errorf("treap: malformed mark state %q", text)
+	} else if Bias(b) != StickLeft && Bias(b) != StickRight {
+		return fmt.Errorf("treap: unknown mark bias %d", b)
+	}
+	*s = MarkState{Index: i, Bias: Bias(b), Version: v}
+	return nil
+}
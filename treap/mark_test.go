@@ -0,0 +1,111 @@
+package treap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMark(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5)
+	if got := tr.Mark(-3).Index(); got != 0 {
+		t.Errorf("Mark(-3).Index() = %d, want 0", got)
+	}
+	if got := tr.Mark(100).Index(); got != 6 {
+		t.Errorf("Mark(100).Index() = %d, want 6", got)
+	}
+	left, right := tr.Mark(2), tr.Mark(2)
+	right.SetBias(StickRight)
+	tr.Insert(2, 9)
+	if left.Index() != 2 || right.Index() != 3 {
+		t.Fatalf("marks after insertion at their index are %d and %d, want 2 and 3", left.Index(), right.Index())
+	}
+	tr.Insert(0, 9)
+	if left.Index() != 3 || right.Index() != 4 {
+		t.Fatalf("marks after insertion before them are %d and %d, want 3 and 4", left.Index(), right.Index())
+	}
+	tr.Cut(2, 5)
+	if left.Index() != 2 || right.Index() != 2 {
+		t.Fatalf("marks after deletion around them are %d and %d, want 2 and 2", left.Index(), right.Index())
+	}
+	tr.Cut(0, 0)
+	tr.Cut(3, 3)
+	if left.Index() != 1 {
+		t.Fatalf("mark after deletions is %d, want 1", left.Index())
+	}
+}
+
+func TestMarkFollowsReversal(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5)
+	m := tr.Mark(1)
+	tr.ReverseRange(0, 3)
+	if got := tr.Find(m.Index()); got != 1 {
+		t.Errorf("mark points to %d after reversal, want 1", got)
+	}
+	outside := tr.Mark(4)
+	tr.ReverseRange(0, 3)
+	if outside.Index() != 4 {
+		t.Errorf("mark outside of the reversed range moved to %d", outside.Index())
+	}
+}
+
+func TestMarkRelease(t *testing.T) {
+	tr := New(0, 1, 2)
+	m := tr.Mark(1)
+	m.Move(10)
+	if m.Index() != 3 {
+		t.Errorf("Move(10) gives index %d, want 3", m.Index())
+	}
+	m.Release()
+	tr.Insert(0, 9)
+	m.Move(0)
+	if m.Index() != 3 {
+		t.Errorf("released mark moved to %d", m.Index())
+	}
+}
+
+func TestMarkState(t *testing.T) {
+	tr := New(0, 1, 2)
+	tr.SetVersion(41)
+	tr.Insert(0, 9)
+	m := tr.Mark(2)
+	m.SetBias(StickRight)
+	data, err := json.Marshal(m.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"2:1@42"` {
+		t.Errorf("encoded state = %s, want %q", data, "2:1@42")
+	}
+	var s MarkState
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := tr.RestoreMark(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Index() != 2 || restored.Bias() != StickRight {
+		t.Errorf("restored mark is on %d with bias %d", restored.Index(), restored.Bias())
+	}
+	tr.Insert(2, 9)
+	if restored.Index() != 3 {
+		t.Errorf("restored mark is not maintained, index %d", restored.Index())
+	}
+	if _, err := tr.RestoreMark(s); !errors.Is(err, ErrStaleMark) {
+		t.Errorf("RestoreMark() of an old state = %v, want ErrStaleMark", err)
+	}
+	tr.SetVersion(s.Version)
+	if _, err := tr.RestoreMark(MarkState{Index: 6, Version: s.Version}); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("RestoreMark() out of range = %v, want ErrIndexOutOfRange", err)
+	}
+}
+
+func TestMarkStateUnmarshalMalformed(t *testing.T) {
+	for _, text := range []string{"", "1:0", "1@0", "a:0@0", "1:0@-1", "1:2@0"} {
+		var s MarkState
+		if err := s.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("UnmarshalText(%q) = %+v, want error", text, s)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !unix
+
+package treap
+
+import "errors"
+
+/*
+Memory-mapping is not available on this platform,
+frozen treap files are always read into the heap memory.
+*/
+const mmapSupported = false
+
+/*
+Never called, since memory-mapping is not supported.
+*/
+func mmapFile(path string) ([]byte, error) {
+	return nil, errors.New("treap: memory-mapping is not supported")
+}
+
+/*
+Never called, since memory-mapping is not supported.
+*/
+func munmap(data []byte) error {
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build unix
+
+package treap
+
+import (
+	"os"
+	"syscall"
+)
+
+/*
+Memory-mapping is available on this platform.
+*/
+const mmapSupported = true
+
+/*
+Maps the whole file into memory for reading.
+
+	if file is empty: return empty slice
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func mmapFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return []byte{}, nil
+	}
+	return syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+/*
+Unmaps memory returned by `mmapFile()`.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func munmap(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,43 @@
+package treap
+
+/*
+Behavior of the methods called on a nil treap.
+*/
+type NilPolicy int
+
+const (
+	// Methods do nothing and return zero values, error-returning methods return their usual errors.
+	// This is the default behavior.
+	NilIgnore NilPolicy = iota
+	// Same as NilIgnore, but error-returning methods return `ErrNilTreap`.
+	NilError
+	// Every method panics with `ErrNilTreap`.
+	NilPanic
+)
+
+/*
+Behavior of every method called on a nil treap.
+Default value silently tolerates nil treaps for backward compatibility,
+set to NilError or NilPanic to surface initialization bugs.
+*/
+var NilReceiver = NilIgnore
+
+/*
+Called by every method that got a nil treap.
+
+	if NilReceiver == NilPanic: panic with ErrNilTreap
+	if NilReceiver == NilError: return ErrNilTreap
+	else: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func nilReceiver() error {
+	switch NilReceiver {
+	case NilPanic:
+		panic(ErrNilTreap)
+	case NilError:
+		return ErrNilTreap
+	}
+	return nil
+}
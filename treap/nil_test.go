@@ -0,0 +1,90 @@
+package treap
+
+import (
+	"errors"
+	"testing"
+)
+
+// Sets the policy for the test and restores the default one afterwards.
+func withNilPolicy(t *testing.T, policy NilPolicy) {
+	t.Cleanup(func() { NilReceiver = NilIgnore })
+	NilReceiver = policy
+}
+
+func TestNilIgnore(t *testing.T) {
+	var tr *Treap
+	if err := panicked(t, func() {
+		tr.Insert(0, 1)
+		tr.Delete(0)
+		tr.Cut(0, 1)
+	}); err != nil {
+		t.Fatalf("methods of nil treap panicked with %v", err)
+	}
+	if tr.Size() != 0 || tr.Find(0) != 0 {
+		t.Error("nil treap is not empty")
+	}
+	if _, err := tr.TryFind(0); !errors.Is(err, ErrEmptyTreap) {
+		t.Errorf("TryFind() on nil treap = %v, want ErrEmptyTreap", err)
+	}
+}
+
+func TestNilError(t *testing.T) {
+	withNilPolicy(t, NilError)
+	var tr *Treap
+	if _, err := tr.TryFind(0); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("TryFind() on nil treap = %v, want ErrNilTreap", err)
+	}
+	if err := tr.TryCut(0, 0); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("TryCut() on nil treap = %v, want ErrNilTreap", err)
+	}
+	if err := panicked(t, func() { tr.Find(0) }); err != nil {
+		t.Errorf("Find() on nil treap panicked with %v", err)
+	}
+}
+
+func TestNilPanic(t *testing.T) {
+	withNilPolicy(t, NilPanic)
+	var tr *Treap
+	for name, fn := range map[string]func(){
+		"Find":    func() { tr.Find(0) },
+		"Size":    func() { tr.Size() },
+		"Insert":  func() { tr.Insert(0, 1) },
+		"TryFind": func() { tr.TryFind(0) },
+	} {
+		if err := panicked(t, fn); !errors.Is(err, ErrNilTreap) {
+			t.Errorf("%s() on nil treap panicked with %v, want ErrNilTreap", name, err)
+		}
+	}
+}
+
+func TestApplyOnNilTreap(t *testing.T) {
+	var tr *Treap
+	for _, policy := range []NilPolicy{NilIgnore, NilError} {
+		withNilPolicy(t, policy)
+		if err := tr.Apply(Op{Kind: OpInsert, Values: []int{1}}); !errors.Is(err, ErrNilTreap) {
+			t.Errorf("Apply() on nil treap with policy %v = %v, want ErrNilTreap", policy, err)
+		}
+	}
+	withNilPolicy(t, NilPanic)
+	if err := panicked(t, func() { tr.Apply(Op{Kind: OpDelete, Count: 1}) }); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("Apply() on nil treap panicked with %v, want ErrNilTreap", err)
+	}
+}
+
+func TestApplyBatchOnNilTreap(t *testing.T) {
+	var tr *Treap
+	ops := []Op{{Kind: OpInsert, Values: []int{1}}}
+	for _, policy := range []NilPolicy{NilIgnore, NilError} {
+		withNilPolicy(t, policy)
+		if err := tr.ApplyBatch(ops); !errors.Is(err, ErrNilTreap) {
+			t.Errorf("ApplyBatch() on nil treap with policy %v = %v, want ErrNilTreap", policy, err)
+		}
+		if err := tr.ApplyBatch(nil); !errors.Is(err, ErrNilTreap) {
+			t.Errorf("ApplyBatch(nil) on nil treap with policy %v = %v, want ErrNilTreap", policy, err)
+		}
+	}
+	withNilPolicy(t, NilPanic)
+	if err := panicked(t, func() { tr.ApplyBatch(ops) }); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("ApplyBatch() on nil treap panicked with %v, want ErrNilTreap", err)
+	}
+}
@@ -0,0 +1,126 @@
+package treap
+
+/*
+Collects edits of the treap into dirty ranges and delivers them on a channel,
+for example to repaint only changed rows of a virtualized list view.
+Edits between deliveries are coalesced into the minimal sorted list of ranges, that do not touch each other.
+Every delivered list has the same meaning as result of `ChangedRanges()`
+between the treap at the previous delivery and at this one.
+
+Notifier follows the treap by hooks, see `AddHooks()`, so it has the same restrictions.
+Channel can be read by another goroutine.
+*/
+type Notifier struct {
+	// Channel of delivered ranges, holds at most 1 undelivered list.
+	C       <-chan []Range
+	c       chan []Range
+	t       *Treap
+	hooks   Hooks
+	pending []Range
+}
+
+/*
+Creates new notifier, that follows all edits of the treap.
+
+	if t == nil: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Notifier() *Notifier {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	c := make(chan []Range, 1)
+	n := &Notifier{C: c, c: c, t: t}
+	n.hooks = Hooks{
+		OnInsert: func(index int, count int) {
+			n.replace(index, 0, count)
+		},
+		OnDelete: func(index int, count int) {
+			n.replace(index, count, 0)
+		},
+		OnRangeUpdate: func(index_left int, index_right int) {
+			n.replace(index_left, index_right-index_left+1, index_right-index_left+1)
+		},
+	}
+	t.AddHooks(&n.hooks)
+	return n
+}
+
+/*
+Sends all ranges changed since the previous delivery, if the channel is empty.
+Should be called after every batch of edits.
+If the previous list is still not received, ranges are kept and coalesced with the following edits,
+so the receiver never gets lists out of order.
+Reports whether there are no ranges left to deliver.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (n *Notifier) Flush() bool {
+	if len(n.pending) == 0 {
+		return true
+	}
+	select {
+	case n.c <- n.pending:
+		n.pending = nil
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+Stops following edits of the treap, ranges not delivered yet are dropped.
+Channel is not closed, since it may still hold the last list.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of hooks, marks and annotations of the treap;
+*/
+func (n *Notifier) Stop() {
+	if n.t != nil {
+		n.t.RemoveHooks(&n.hooks)
+		n.t, n.pending = nil, nil
+	}
+}
+
+/*
+Records replacement of count elements starting from the index by inserted new elements.
+Ranges touching the replaced elements are joined with them, following ranges are shifted.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of pending ranges;
+*/
+func (n *Notifier) replace(index int, count int, inserted int) {
+	last, clean := index+count, count
+	joined := Range{Left: index, Right: last - 1}
+	pending := make([]Range, 0, len(n.pending)+1)
+	for _, r := range n.pending {
+		if r.Right+1 < index {
+			pending = append(pending, r)
+			continue
+		} else if r.Left > last {
+			r.Left += inserted - count
+			r.Right += inserted - count
+			pending = append(pending, r)
+			continue
+		}
+		// Dirty elements of the range are replaced, so only clean ones are counted as removed.
+		clean -= max(0, min(r.Right, last-1)-max(r.Left, index)+1)
+		joined.Removed += r.Removed
+		joined.Left = min(joined.Left, r.Left)
+		joined.Right = max(joined.Right, r.Right)
+	}
+	joined.Removed += clean
+	joined.Right += inserted - count
+	if joined.Right >= joined.Left || joined.Removed > 0 {
+		i := 0
+		for i < len(pending) && pending[i].Left < joined.Left {
+			i++
+		}
+		pending = append(pending[:i], append([]Range{joined}, pending[i:]...)...)
+	}
+	n.pending = pending
+}
@@ -0,0 +1,93 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// Reports whether the ranges are sorted, do not touch each other,
+// and cover all differences between old and new values.
+func explains(old []int, new []int, ranges []Range) bool {
+	i, j := 0, 0
+	for k, r := range ranges {
+		if k > 0 && r.Left <= ranges[k-1].Right+1 {
+			return false
+		}
+		clean := r.Left - j
+		if clean < 0 || i+clean+r.Removed > len(old) || !slices.Equal(old[i:i+clean], new[j:r.Left]) {
+			return false
+		}
+		i += clean + r.Removed
+		j = r.Right + 1
+	}
+	return slices.Equal(old[i:], new[min(j, len(new)):])
+}
+
+func TestNotifier(t *testing.T) {
+	r := rand.New(rand.NewPCG(27, 28))
+	tr := New(make([]int, 30)...)
+	n := tr.Notifier()
+	old := tr.Export()
+	for step := range 3000 {
+		size := tr.Size()
+		switch r.IntN(5) {
+		case 0:
+			tr.InsertSlice(r.IntN(size+1), step, step+1)
+		case 1:
+			if size > 0 {
+				l := r.IntN(size)
+				tr.Cut(l, l+r.IntN(min(4, size-l)))
+			}
+		case 2:
+			if size > 0 {
+				tr.Set(r.IntN(size), step)
+			}
+		case 3:
+			if size > 0 {
+				l := r.IntN(size)
+				tr.ReverseRange(l, l+r.IntN(size-l))
+			}
+		case 4:
+			if !n.Flush() {
+				t.Fatalf("step %d: Flush() of an empty channel failed", step)
+			}
+			select {
+			case ranges := <-n.C:
+				if new := tr.Export(); !explains(old, new, ranges) {
+					t.Fatalf("step %d: ranges %v do not explain %v -> %v", step, ranges, old, new)
+				}
+			default:
+			}
+			old = tr.Export()
+		}
+	}
+}
+
+func TestNotifierCoalesces(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	n := tr.Notifier()
+	tr.Set(2, 20)
+	n.Flush()
+	tr.Insert(3, 30)
+	tr.Delete(8)
+	if n.Flush() {
+		t.Fatal("Flush() succeeded while the previous list is not received")
+	}
+	if got := <-n.C; !slices.Equal(got, []Range{{Left: 2, Right: 2, Removed: 1}}) {
+		t.Errorf("1st delivery = %v", got)
+	}
+	tr.Set(4, 40)
+	if !n.Flush() {
+		t.Fatal("Flush() failed after the previous list was received")
+	}
+	want := []Range{{Left: 3, Right: 4, Removed: 1}, {Left: 8, Right: 7, Removed: 1}}
+	if got := <-n.C; !slices.Equal(got, want) {
+		t.Errorf("2nd delivery = %v, want %v", got, want)
+	}
+	n.Stop()
+	tr.Insert(0, 0)
+	if !n.Flush() || len(n.C) != 0 {
+		t.Error("stopped notifier delivers ranges")
+	}
+}
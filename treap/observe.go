@@ -0,0 +1,116 @@
+package treap
+
+/*
+Layer that follows edits of the treap, like annotations.
+Observer is called inside of the mutating method,
+so it must not call methods of the treap itself.
+*/
+type observer interface {
+	// Count elements were inserted, so the 1st of them got the given index.
+	inserted(index int, count int)
+	// Count elements starting from the given index were deleted.
+	deleted(index int, count int)
+	// Count elements starting from the given index got new values, but kept their positions.
+	updated(index int, count int)
+	// Order of count elements starting from the given index was reversed.
+	mirrored(index int, count int)
+}
+
+/*
+Registers observer, so it is notified about all following edits.
+
+# Time complexity:
+  - Constant - amortized, requires single append;
+*/
+func (t *Treap) observe(o observer) {
+	t.observers = append(t.observers, o)
+}
+
+/*
+Unregisters observer, so it is no longer notified.
+
+	if observer is not registered: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of observers;
+*/
+func (t *Treap) unobserve(o observer) {
+	for i, other := range t.observers {
+		if other == o {
+			t.observers = append(t.observers[:i:i], t.observers[i+1:]...)
+			return
+		}
+	}
+}
+
+/*
+Notifies all observers about inserted elements and counts the change.
+
+	if count == 0: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of observers;
+*/
+func (t *Treap) inserted(index int, count int) {
+	if count == 0 {
+		return
+	}
+	t.version++
+	for _, o := range t.observers {
+		o.inserted(index, count)
+	}
+}
+
+/*
+Notifies all observers about deleted elements and counts the change.
+
+	if count == 0: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of observers;
+*/
+func (t *Treap) deleted(index int, count int) {
+	if count == 0 {
+		return
+	}
+	t.version++
+	for _, o := range t.observers {
+		o.deleted(index, count)
+	}
+}
+
+/*
+Notifies all observers about changed values and counts the change.
+
+	if count == 0: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of observers;
+*/
+func (t *Treap) updated(index int, count int) {
+	if count == 0 {
+		return
+	}
+	t.version++
+	for _, o := range t.observers {
+		o.updated(index, count)
+	}
+}
+
+/*
+Notifies all observers about reversed elements and counts the change.
+
+	if count == 0: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of observers;
+*/
+func (t *Treap) mirrored(index int, count int) {
+	if count == 0 {
+		return
+	}
+	t.version++
+	for _, o := range t.observers {
+		o.mirrored(index, count)
+	}
+}
@@ -0,0 +1,196 @@
+package treap
+
+import "fmt"
+
+/*
+Kind of the mutating operation described by `Op`.
+*/
+type OpKind int
+
+const (
+	// Insert all values of the operation, so the 1st of them is on the operation's index.
+	OpInsert OpKind = iota + 1
+	// Delete Count elements starting from the operation's index.
+	OpDelete
+)
+
+/*
+Single mutating operation on the sequence.
+Unlike `Insert()` and `Cut()` methods, indexes of the operation have exact meaning,
+so operations can be exchanged, transformed and replayed.
+
+	OpInsert: Values are inserted before Index, 0 <= Index <= size
+	OpDelete: Count elements from Index are deleted, 0 <= Index and Index+Count <= size
+*/
+type Op struct {
+	Kind   OpKind
+	Index  int
+	Values []int
+	Count  int
+}
+
+/*
+Returns operation inserting values before the index.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func InsertOp(index int, values ...int) Op {
+	return Op{Kind: OpInsert, Index: index, Values: values}
+}
+
+/*
+Returns operation deleting count elements from the index.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func DeleteOp(index int, count int) Op {
+	return Op{Kind: OpDelete, Index: index, Count: count}
+}
+
+/*
+Returns difference in size of the sequence after the operation is applied.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (op Op) delta() int {
+	switch op.Kind {
+	case OpInsert:
+		return len(op.Values)
+	case OpDelete:
+		return -op.Count
+	}
+	return 0
+}
+
+/*
+Checks that the operation can be applied to the sequence of provided size.
+
+	if operation is unknown or negative: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (op Op) valid(size int) error {
+	switch op.Kind {
+	case OpInsert:
+		if op.Index < 0 || op.Index > size {
+			return fmt.Errorf("%w: insert index %d with size %d", ErrIndexOutOfRange, op.Index, size)
+		}
+	case OpDelete:
+		if op.Count < 0 || op.Index < 0 || op.Index > size-op.Count {
+			return fmt.Errorf("%w: delete %d from index %d with size %d", ErrIndexOutOfRange, op.Count, op.Index, size)
+		}
+	default:
+		return fmt.Errorf("%w: unknown operation kind %d", ErrIndexOutOfRange, op.Kind)
+	}
+	return nil
+}
+
+/*
+Applies already validated operation to the treap.
+
+# Time complexity:
+  - Logarithmic - for deletion, time complexity is equal to height of the treap;
+  - Linear - for insertion, time complexity is equal to amount of values plus height of the treap;
+*/
+func (t *Treap) apply(op Op) {
+	switch op.Kind {
+	case OpInsert:
+		if !t.log(op) {
+			return
+		}
+		l, r := split(t.root, op.Index-1)
+		t.root = merge(merge(l, build(op.Values, false, t.opts.pool)), r)
+		t.inserted(op.Index, len(op.Values))
+		t.evict()
+	case OpDelete:
+		if op.Count > 0 {
+			t.cut(op.Index, op.Index+op.Count-1)
+		}
+	}
+}
+
+/*
+Applies single operation with exact indexes.
+
+	if t == nil: return ErrNilTreap, or panic if NilReceiver == NilPanic
+	if treap was consumed: return ErrConsumedTreap
+	if journal failed: return error of `JournalErr()`
+	if operation is out of range: return ErrIndexOutOfRange
+	if inserted values do not fit into the treap: return ErrSizeLimit
+
+Nothing is changed if error is returned.
+
+# Time complexity:
+  - Logarithmic - for deletion, time complexity is equal to height of the treap;
+  - Linear - for insertion, time complexity is equal to amount of values plus height of the treap;
+*/
+func (t *Treap) Apply(op Op) error {
+	if t == nil {
+		nilReceiver()
+		return ErrNilTreap
+	} else if t.consumed {
+		return ErrConsumedTreap
+	} else if err := t.JournalErr(); err != nil {
+		return err
+	} else if err := op.valid(t.Size()); err != nil {
+		return err
+	} else if op.Kind == OpInsert {
+		if err := t.validInsert(len(op.Values)); err != nil {
+			return err
+		}
+	}
+	v := t.enter()
+	t.apply(op)
+	t.leave(v)
+	return t.JournalErr()
+}
+
+/*
+Applies all operations in order, same as `Apply()` for each of them, but fails atomically.
+All operations are validated first against the size the treap will have right before each of them,
+so either all operations are applied, or none of them and the error of the 1st invalid one is returned.
+Only failure of the journal in the middle of the batch leaves previous operations applied.
+
+	if t == nil: return ErrNilTreap, or panic if NilReceiver == NilPanic
+	if treap was consumed: return ErrConsumedTreap
+	if journal failed: return error of `JournalErr()`
+	if any operation is out of range: return ErrIndexOutOfRange
+	if inserted values do not fit into the treap: return ErrSizeLimit
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of operations multiplied by height of the treap, plus amount of inserted values;
+*/
+func (t *Treap) ApplyBatch(ops []Op) error {
+	if t == nil {
+		nilReceiver()
+		return ErrNilTreap
+	} else if t.consumed {
+		return ErrConsumedTreap
+	} else if err := t.JournalErr(); err != nil {
+		return err
+	}
+	size, limit := t.Size(), t.opts.limit
+	for i, op := range ops {
+		if err := op.valid(size); err != nil {
+			return fmt.Errorf("treap: batch operation %d: %w", i, err)
+		}
+		size += op.delta()
+		if limit > 0 && size > limit {
+			if t.opts.evict == EvictNone {
+				return fmt.Errorf("treap: batch operation %d: %w: inserting %d with limit %d", i, ErrSizeLimit, len(op.Values), limit)
+			}
+			// Overflow is evicted right after the insertion.
+			size = limit
+		}
+	}
+	v := t.enter()
+	for _, op := range ops {
+		t.apply(op)
+	}
+	t.leave(v)
+	return t.JournalErr()
+}
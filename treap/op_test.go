@@ -0,0 +1,137 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tr := New(1, 2, 3)
+	if err := tr.Apply(InsertOp(3, 4, 5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Apply(DeleteOp(0, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{3, 4, 5}) {
+		t.Fatalf("Export() = %v, want [3 4 5]", got)
+	}
+	for _, op := range []Op{InsertOp(-1, 0), InsertOp(4, 0), DeleteOp(1, 3), DeleteOp(-1, 1), DeleteOp(0, -1), {Kind: 7}} {
+		if err := tr.Apply(op); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("Apply(%v) = %v, want ErrIndexOutOfRange", op, err)
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{3, 4, 5}) {
+		t.Errorf("Export() after invalid operations = %v", got)
+	}
+}
+
+// Returns random valid operation on the sequence of provided size.
+func randomOp(r *rand.Rand, size int) Op {
+	if size == 0 || r.IntN(2) == 0 {
+		values := make([]int, 1+r.IntN(3))
+		for i := range values {
+			values[i] = r.IntN(100)
+		}
+		return InsertOp(r.IntN(size+1), values...)
+	}
+	index := r.IntN(size)
+	return DeleteOp(index, r.IntN(size-index+1))
+}
+
+// Applies all operations to the copy of the values.
+func applied(t *testing.T, values []int, ops ...Op) []int {
+	t.Helper()
+	tr := New(values...)
+	for _, op := range ops {
+		if err := tr.ApplyRemote(op); err != nil {
+			t.Fatalf("ApplyRemote(%v) to %v = %v", op, values, err)
+		}
+	}
+	return tr.Export()
+}
+
+func TestTransformConverges(t *testing.T) {
+	r := rand.New(rand.NewPCG(15, 16))
+	for range 5000 {
+		values := make([]int, r.IntN(8))
+		for i := range values {
+			values[i] = -i - 1
+		}
+		a, b := randomOp(r, len(values)), randomOp(r, len(values))
+		atA := applied(t, values, append([]Op{a}, Transform(b, a, true)...)...)
+		atB := applied(t, values, append([]Op{b}, Transform(a, b, false)...)...)
+		if !slices.Equal(atA, atB) {
+			t.Fatalf("%v and %v applied to %v diverge: %v and %v", a, b, values, atA, atB)
+		}
+	}
+}
+
+func TestTransformKeepsInsertedValues(t *testing.T) {
+	values := []int{0, 1, 2, 3, 4}
+	insert, del := InsertOp(2, 9), DeleteOp(1, 3)
+	want := []int{0, 9, 4}
+	if got := applied(t, values, append([]Op{insert}, Transform(del, insert, true)...)...); !slices.Equal(got, want) {
+		t.Errorf("deletion after insertion gives %v, want %v", got, want)
+	}
+	if got := applied(t, values, append([]Op{del}, Transform(insert, del, false)...)...); !slices.Equal(got, want) {
+		t.Errorf("insertion after deletion gives %v, want %v", got, want)
+	}
+}
+
+func TestApplyRemoteAcceptsEmptyOperations(t *testing.T) {
+	tr := New(1, 2)
+	if err := tr.ApplyRemote(DeleteOp(5, 0)); err != nil {
+		t.Errorf("ApplyRemote() of empty deletion = %v", err)
+	}
+	if err := tr.ApplyRemote(InsertOp(5)); err != nil {
+		t.Errorf("ApplyRemote() of empty insertion = %v", err)
+	}
+	if err := tr.ApplyRemote(DeleteOp(5, 1)); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("ApplyRemote() of invalid deletion = %v, want ErrIndexOutOfRange", err)
+	}
+}
+
+func TestApplyBatch(t *testing.T) {
+	tr := New(1, 2, 3)
+	ops := []Op{InsertOp(3, 4, 5), DeleteOp(0, 2), InsertOp(3, 6)}
+	if err := tr.ApplyBatch(ops); err != nil || !slices.Equal(tr.Export(), []int{3, 4, 5, 6}) {
+		t.Fatalf("ApplyBatch(%v) = %v, %v", ops, tr.Export(), err)
+	}
+	// The last operation is valid only before the deletion, so nothing is applied.
+	ops = []Op{InsertOp(0, 0), DeleteOp(0, 3), DeleteOp(1, 2)}
+	if err := tr.ApplyBatch(ops); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("ApplyBatch(%v) = %v, want ErrIndexOutOfRange", ops, err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{3, 4, 5, 6}) {
+		t.Fatalf("failed ApplyBatch() changed the treap to %v", got)
+	}
+	tr.SetLimit(5)
+	ops = []Op{DeleteOp(0, 1), InsertOp(0, 0, 1, 2)}
+	if err := tr.ApplyBatch(ops); !errors.Is(err, ErrSizeLimit) || tr.Size() != 4 {
+		t.Errorf("ApplyBatch() over the limit = %v, size %d", err, tr.Size())
+	}
+	ops = []Op{DeleteOp(0, 2), InsertOp(0, 1, 2, 3)}
+	if err := tr.ApplyBatch(ops); err != nil || !slices.Equal(tr.Export(), []int{1, 2, 3, 5, 6}) {
+		t.Errorf("ApplyBatch() up to the limit = %v, %v", tr.Export(), err)
+	}
+	bounded := NewBounded(3, EvictFront)
+	bounded.PushBack(1, 2, 3)
+	ops = []Op{InsertOp(3, 4, 5), DeleteOp(2, 1)}
+	if err := bounded.ApplyBatch(ops); err != nil || !slices.Equal(bounded.Export(), []int{3, 4}) {
+		t.Errorf("ApplyBatch() with eviction = %v, %v", bounded.Export(), err)
+	}
+}
+
+func TestApplyBatchWithFailingJournal(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.SetJournal(failingWriter{})
+	if err := tr.ApplyBatch([]Op{DeleteOp(0, 1), DeleteOp(0, 1)}); err == nil {
+		t.Error("ApplyBatch() with a failing journal succeeded")
+	}
+	if err := tr.ApplyBatch(nil); err == nil {
+		t.Error("ApplyBatch() after a failure of the journal succeeded")
+	}
+}
@@ -0,0 +1,75 @@
+package treap
+
+/*
+Transforms operation against concurrent operation that was already applied,
+so the result has the same intention on the changed sequence (operational transformation).
+
+Index shifting rules:
+  - insert after inserted values is shifted by their amount;
+  - insert on the same index is placed before other inserted values only if `first` is true;
+  - insert inside deleted range is moved to the start of that range;
+  - delete range is shrunk by already deleted elements and shifted;
+  - delete range with values inserted inside is split into 2 deletions around them.
+
+Returns 0, 1 or 2 operations that must be applied in order.
+Sites must pass opposite `first` values for the same pair of operations,
+e.g. the site with the lower id always passes true.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func Transform(op Op, against Op, first bool) []Op {
+	switch {
+	case op.Kind == OpInsert && against.Kind == OpInsert:
+		if against.Index < op.Index || (against.Index == op.Index && !first) {
+			op.Index += len(against.Values)
+		}
+	case op.Kind == OpInsert && against.Kind == OpDelete:
+		if op.Index >= against.Index+against.Count {
+			op.Index -= against.Count
+		} else if op.Index > against.Index {
+			op.Index = against.Index
+		}
+	case op.Kind == OpDelete && against.Kind == OpInsert:
+		inserted := len(against.Values)
+		if against.Index <= op.Index {
+			op.Index += inserted
+		} else if against.Index < op.Index+op.Count {
+			// Inserted values must survive, so the range is deleted around them.
+			before := DeleteOp(op.Index, against.Index-op.Index)
+			after := DeleteOp(op.Index+inserted, op.Count-before.Count)
+			return []Op{before, after}
+		}
+	case op.Kind == OpDelete && against.Kind == OpDelete:
+		end, aend := op.Index+op.Count, against.Index+against.Count
+		overlap := max(0, min(end, aend)-max(op.Index, against.Index))
+		// Amount of already deleted elements that were before the range.
+		before := max(0, min(op.Index, aend)-against.Index)
+		op.Index -= min(before, against.Count)
+		op.Count -= overlap
+		if op.Count == 0 {
+			return nil
+		}
+	}
+	return []Op{op}
+}
+
+/*
+Applies operation received from another site.
+Operation must be already transformed by `Transform()` against all local operations
+that were not known to the other site.
+
+Same as `Apply()`, but operations that became empty are accepted.
+
+# Time complexity:
+  - Logarithmic - for deletion, time complexity is equal to height of the treap;
+  - Linear - for insertion, time complexity is equal to amount of values plus height of the treap;
+*/
+func (t *Treap) ApplyRemote(op Op) error {
+	if op.Kind == OpDelete && op.Count == 0 {
+		return nil
+	} else if op.Kind == OpInsert && len(op.Values) == 0 {
+		return nil
+	}
+	return t.Apply(op)
+}
@@ -0,0 +1,438 @@
+package treap
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+/*
+Editable sequence backed by a frozen treap file.
+Sequence is stored as a treap of pieces, every piece is either:
+  - cold - range of the frozen treap, read directly from the (memory-mapped) file;
+  - hot - treap with the elements inserted by the user.
+
+Only pieces touched by operations are visited, and only the file pages of the touched subtrees
+are loaded by the operating system, so huge sequence can be edited with bounded resident memory.
+Inserting next to a hot piece reuses it instead of creating a new piece.
+
+Resident memory grows with amount of edits: inserted elements are held in the heap until they are spilled,
+see `SetSpill()`, and every edit may cut a piece in 2. Use `WriteTo()` and `OpenPaged()` to compact all pieces into 1 file.
+
+# Paged sequence is unsafe to be used in parallel goroutines.
+*/
+type Paged struct {
+	sources []source
+	root    *piece
+	spill   spill
+}
+
+/*
+Continuous part of the paged sequence and a node of the treap of pieces.
+Cold piece reads its elements from the base, which is either the frozen treap of the sequence or a spill file.
+*/
+type piece struct {
+	base     *Frozen
+	from     int
+	length   int
+	hot      *Treap
+	size     int
+	priority int
+	tiebreak uint64
+	lson     *piece
+	rson     *piece
+}
+
+/*
+Creates single piece with random priority.
+
+	if hot == nil: piece is cold and contains `length` elements of the base starting from `from`
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func newPiece(base *Frozen, from int, length int, hot *Treap) *piece {
+	return &piece{base: base, from: from, length: length, hot: hot, size: length,
+		priority: randomPriority(), tiebreak: mix(created.Add(1))}
+}
+
+/*
+Returns amount of elements in the subtree of pieces.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func psize(p *piece) int {
+	if p == nil {
+		return 0
+	}
+	return p.size
+}
+
+/*
+Recalculate piece's subtree size.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func psync(p *piece) {
+	p.size = p.length + psize(p.lson) + psize(p.rson)
+}
+
+/*
+Merges 2 subtrees of pieces, same as `merge()`.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap of pieces;
+*/
+func pmerge(p1 *piece, p2 *piece) *piece {
+	if p1 == nil {
+		return p2
+	} else if p2 == nil {
+		return p1
+	}
+	if p1.priority > p2.priority || (p1.priority == p2.priority && p1.tiebreak > p2.tiebreak) {
+		p1.rson = pmerge(p1.rson, p2)
+		psync(p1)
+		return p1
+	}
+	p2.lson = pmerge(p1, p2.lson)
+	psync(p2)
+	return p2
+}
+
+/*
+Splits subtree of pieces into first `count` elements and the rest.
+Piece that contains the border is cut into 2 pieces.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap of pieces;
+*/
+func psplit(p *piece, count int) (l *piece, r *piece) {
+	if p == nil {
+		return nil, nil
+	} else if count <= 0 {
+		return nil, p
+	} else if count >= p.size {
+		return p, nil
+	}
+	lsize := psize(p.lson)
+	if count <= lsize {
+		l, p.lson = psplit(p.lson, count)
+		psync(p)
+		return l, p
+	} else if count >= lsize+p.length {
+		p.rson, r = psplit(p.rson, count-lsize-p.length)
+		psync(p)
+		return p, r
+	}
+	lson, rson := p.lson, p.rson
+	first, second := p.cut(count - lsize)
+	return pmerge(lson, first), pmerge(second, rson)
+}
+
+/*
+Cuts single piece into 2 pieces, 1st one contains `count` elements.
+Count must be inside the piece.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the hot treap;
+*/
+func (p *piece) cut(count int) (*piece, *piece) {
+	if p.hot == nil {
+		return newPiece(p.base, p.from, count, nil), newPiece(p.base, p.from+count, p.length-count, nil)
+	}
+	tl, tr := Split(p.hot, count-1)
+	return newPiece(nil, 0, count, &tl), newPiece(nil, 0, p.length-count, &tr)
+}
+
+/*
+Creates paged sequence with the elements of the frozen treap.
+Frozen treap is never changed and must not be closed while paged sequence is used,
+it is closed by `Close()` of the sequence, or earlier by spilling once no element is read from it, see `SetSpill()`.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func NewPaged(base *Frozen) *Paged {
+	p := &Paged{sources: []source{{frozen: base}}}
+	if base.Size() > 0 {
+		p.root = newPiece(base, 0, base.Size(), nil)
+	}
+	return p
+}
+
+/*
+Opens frozen treap file by `OpenFrozen()` and creates paged sequence editing it.
+File is never changed, use `WriteTo()` to save the edited sequence into another file.
+Paged sequence must be closed by `Close()` when it is no longer needed.
+
+# Time complexity:
+  - Linear - time complexity is same as of `OpenFrozen()`;
+*/
+func OpenPaged(path string) (*Paged, error) {
+	base, err := OpenFrozen(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPaged(base), nil
+}
+
+/*
+Closes all frozen treaps of the paged sequence and removes its spill files.
+Paged sequence must not be used afterwards.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of spill files in use;
+*/
+func (p *Paged) Close() error {
+	p.root = nil
+	var err error
+	for _, s := range p.sources {
+		err = errors.Join(err, s.close())
+	}
+	p.sources = nil
+	return err
+}
+
+/*
+Returns size of the paged sequence.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (p *Paged) Size() int {
+	return psize(p.root)
+}
+
+/*
+Returns amount of pieces the sequence is made of.
+Useful to decide when the file must be compacted.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of pieces;
+*/
+func (p *Paged) Pieces() int {
+	count := 0
+	for range p.pieces() {
+		count++
+	}
+	return count
+}
+
+/*
+Return the element on the given index.
+
+	if index out of range: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap of pieces plus height of the piece;
+*/
+func (p *Paged) Find(index int) int {
+	if index < 0 || index >= p.Size() {
+		return 0
+	}
+	for pc := p.root; pc != nil; {
+		lsize := psize(pc.lson)
+		if index < lsize {
+			pc = pc.lson
+		} else if index >= lsize+pc.length {
+			index -= lsize + pc.length
+			pc = pc.rson
+		} else if pc.hot != nil {
+			return pc.hot.Find(index - lsize)
+		} else {
+			return pc.base.Find(pc.from + index - lsize)
+		}
+	}
+	return 0
+}
+
+/*
+Insert value into provided index.
+Behaves the same as `Insert()` of the treap:
+
+	if index <= 0: insert to the front
+	if index >= size: insert to the back
+
+No element is read from the file.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap of pieces;
+*/
+func (p *Paged) Insert(index int, value int) {
+	size := p.Size()
+	position := index
+	if index <= 0 {
+		position = 0
+	} else if index >= size {
+		position = size
+	}
+	l, r := psplit(p.root, position)
+	last := l
+	for last != nil && last.rson != nil {
+		last = last.rson
+	}
+	if last != nil && last.hot != nil {
+		last.hot.PushBack(value)
+		last.length++
+		// Hot piece is the rightmost one, so it is inside of every subtree of the right spine.
+		for pc := l; pc != nil; pc = pc.rson {
+			pc.size++
+		}
+	} else {
+		hot := New(value)
+		l = pmerge(l, newPiece(nil, 0, 1, &hot))
+	}
+	p.root = pmerge(l, r)
+	p.spill.inserted++
+	p.spillOver()
+}
+
+/*
+Delete all elements in the given range.
+Behaves the same as `Cut()` of the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap of pieces;
+*/
+func (p *Paged) Cut(index_left int, index_right int) {
+	size := p.Size()
+	if index_left > index_right || index_right < 0 || index_left >= size {
+		return
+	}
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size-1)
+	l, k := psplit(p.root, index_left)
+	_, r := psplit(k, index_right-index_left+1)
+	p.root = pmerge(l, r)
+}
+
+/*
+Delete 1 element from the sequence by provided index.
+
+	if index < 0 || index >= size: do nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap of pieces;
+*/
+func (p *Paged) Delete(index int) {
+	if index < 0 || index >= p.Size() {
+		return
+	}
+	p.Cut(index, index)
+}
+
+/*
+Returns iterator over all pieces in order.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of pieces;
+*/
+func (p *Paged) pieces() iter.Seq[*piece] {
+	return func(yield func(*piece) bool) {
+		var stack []*piece
+		for pc := p.root; pc != nil || len(stack) > 0; {
+			for ; pc != nil; pc = pc.lson {
+				stack = append(stack, pc)
+			}
+			pc = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(pc) {
+				return
+			}
+			pc = pc.rson
+		}
+	}
+}
+
+/*
+Returns iterator over all indexes and values of the sequence in order.
+Sequence must not be changed during the iteration.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the sequence;
+*/
+func (p *Paged) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		offset := 0
+		for pc := range p.pieces() {
+			shifted := func(index int, value int) bool {
+				return yield(offset+index-pc.from, value)
+			}
+			if pc.hot != nil {
+				for i, value := range pc.hot.Export() {
+					if !yield(offset+i, value) {
+						return
+					}
+				}
+			} else if !pc.base.walkRange(0, pc.base.Size(), 0, pc.from, pc.from+pc.length, shifted) {
+				return
+			}
+			offset += pc.length
+		}
+	}
+}
+
+/*
+Returns all values of the sequence as slice of the integers.
+Intended for small sequences, since whole sequence is loaded into the heap.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the sequence;
+*/
+func (p *Paged) Export() []int {
+	values := make([]int, 0, p.Size())
+	for _, value := range p.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+/*
+Writes the sequence as a frozen treap file with perfectly balanced layout.
+Written file can be opened by `OpenFrozen()` or `OpenPaged()`.
+Implements io.WriterTo interface.
+
+Values are read one by one, so the sequence is never loaded into the heap.
+
+# Time complexity:
+  - Loglinear - every value is found in a logarithmic time;
+*/
+func (p *Paged) WriteTo(w io.Writer) (int64, error) {
+	return writeBalanced(w, p.Size(), p.Find)
+}
+
+/*
+Writes frozen treap file with perfectly balanced layout of provided amount of values,
+every value is requested by its index.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of values multiplied by time of a request;
+*/
+func writeBalanced(w io.Writer, size int, value func(index int) int) (int64, error) {
+	fw := newFrozenWriter(w, size)
+	balanced(0, size, func(index int, lsize int) {
+		fw.put(value(index))
+	})
+	balanced(0, size, func(index int, lsize int) {
+		fw.put(lsize)
+	})
+	return fw.finish()
+}
+
+/*
+Visits in pre-order all nodes of the perfectly balanced tree over indexes [lo, hi).
+For every node its index and size of its left subtree are provided.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of indexes;
+*/
+func balanced(lo int, hi int, visit func(index int, lsize int)) {
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		visit(mid, mid-lo)
+		balanced(lo, mid, visit)
+		lo = mid + 1
+	}
+}
@@ -0,0 +1,88 @@
+package treap
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestPagedModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	base := make([]int, 200)
+	for i := range base {
+		base[i] = i
+	}
+	p := openPaged(t, base)
+	defer p.Close()
+	model := slices.Clone(base)
+	for step := range 2000 {
+		n := len(model)
+		switch r.IntN(4) {
+		case 0, 1:
+			index, value := r.IntN(n+3)-1, -step
+			p.Insert(index, value)
+			index = min(max(index, 0), n)
+			model = slices.Insert(model, index, value)
+		case 2:
+			if n > 0 {
+				l := r.IntN(n)
+				h := l + r.IntN(min(5, n-l))
+				p.Cut(l, h)
+				model = slices.Delete(model, l, h+1)
+			}
+		case 3:
+			index := r.IntN(n+2) - 1
+			p.Delete(index)
+			if index >= 0 && index < n {
+				model = slices.Delete(model, index, index+1)
+			}
+		}
+		if p.Size() != len(model) {
+			t.Fatalf("step %d: Size() = %d, want %d", step, p.Size(), len(model))
+		}
+		if len(model) > 0 {
+			index := r.IntN(len(model))
+			if got := p.Find(index); got != model[index] {
+				t.Fatalf("step %d: Find(%d) = %d, want %d", step, index, got, model[index])
+			}
+		}
+	}
+	if got := p.Export(); !slices.Equal(got, model) {
+		t.Fatalf("Export() = %v, want %v", got, model)
+	}
+	for i, value := range p.All() {
+		if value != model[i] {
+			t.Fatalf("All() yields %d at %d, want %d", value, i, model[i])
+		}
+	}
+	if got := p.Find(-1) + p.Find(len(model)); got != 0 {
+		t.Errorf("Find() out of range = %d, want 0", got)
+	}
+}
+
+func TestPagedWriteTo(t *testing.T) {
+	p := openPaged(t, []int{1, 2, 3, 4, 5, 6})
+	defer p.Close()
+	p.Insert(2, 9)
+	p.Insert(3, 8)
+	p.Cut(5, 6)
+	want := []int{1, 2, 9, 8, 3, 6}
+	if got := p.Pieces(); got != 4 {
+		t.Errorf("Pieces() = %d, want 4", got)
+	}
+	var buf bytes.Buffer
+	if _, err := p.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	f, err := ReadFrozen(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Export(); !slices.Equal(got, want) {
+		t.Errorf("written sequence = %v, want %v", got, want)
+	}
+	if got := p.sources[0].frozen.Export(); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("base frozen treap = %v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package treap
+
+import "iter"
+
+/*
+Returns iterator over consecutive pages of the treap with provided length, last page may be shorter.
+Every page is exported only when it is requested, so stopping early costs only the visited pages,
+and every page is a new slice, which can be kept or sent by the caller.
+Page k holds indexes from k*length to (k+1)*length-1 of the treap at the moment the page is exported,
+so changes made between pages are visible in the following pages.
+
+	if length <= 0: yield nothing
+	if t == nil: yield nothing
+
+# Time complexity:
+  - Linear - for every page, time complexity is equal to page length plus height of the treap;
+*/
+func (t *Treap) Pages(length int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		if t == nil {
+			nilReceiver()
+			return
+		} else if length <= 0 {
+			return
+		}
+		for lo := 0; ; lo += length {
+			v := t.enter()
+			page := collect(t.root, lo, min(lo+length, size(t.root)))
+			t.exit(v)
+			if page == nil || !yield(page) {
+				return
+			}
+		}
+	}
+}
+
+/*
+Returns iterator over every k-th element of the treap with its index, starting from the 1st element,
+for example to downsample a very large sequence.
+Every element is found by its own descent from the root, so skipped elements are never visited.
+Element with index i*k is found at the moment it is requested, same as pages of `Pages()`.
+
+	if k <= 0: yield nothing
+	if t == nil: yield nothing
+
+# Time complexity:
+  - Logarithmic - for every yielded element, time complexity is equal to height of the treap;
+*/
+func (t *Treap) Every(k int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		if t == nil {
+			nilReceiver()
+			return
+		} else if k <= 0 {
+			return
+		}
+		for index := 0; ; index += k {
+			v := t.enter()
+			n := find(t.root, index)
+			t.exit(v)
+			if n == nil || !yield(index, n.value) {
+				return
+			}
+		}
+	}
+}
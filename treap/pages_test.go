@@ -0,0 +1,65 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPages(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5, 6)
+	var pages [][]int
+	for page := range tr.Pages(3) {
+		pages = append(pages, page)
+	}
+	if want := [][]int{{0, 1, 2}, {3, 4, 5}, {6}}; !slices.EqualFunc(pages, want, slices.Equal) {
+		t.Fatalf("Pages(3) = %v, want %v", pages, want)
+	}
+	pages[0][0] = 9
+	if tr.Find(0) != 0 {
+		t.Error("page shares memory with the treap")
+	}
+	pages = nil
+	for page := range tr.Pages(2) {
+		pages = append(pages, page)
+		tr.Delete(0)
+	}
+	if want := [][]int{{0, 1}, {3, 4}, {6}}; !slices.EqualFunc(pages, want, slices.Equal) {
+		t.Errorf("Pages(2) with deletions = %v, want %v", pages, want)
+	}
+	for range tr.Pages(0) {
+		t.Error("Pages(0) yields a page")
+	}
+	count, big := 0, New(make([]int, 100)...)
+	for range big.Pages(10) {
+		if count++; count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("stopped Pages() yields %d pages", count)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5, 6)
+	var got [][2]int
+	for index, value := range tr.Every(3) {
+		got = append(got, [2]int{index, value})
+	}
+	if want := [][2]int{{0, 0}, {3, 3}, {6, 6}}; !slices.Equal(got, want) {
+		t.Errorf("Every(3) = %v, want %v", got, want)
+	}
+	for range tr.Every(-1) {
+		t.Error("Every(-1) yields an element")
+	}
+	visited := 0
+	for index := range tr.Every(1) {
+		visited++
+		if index == 2 {
+			break
+		}
+	}
+	if visited != 3 {
+		t.Errorf("Every(1) visits %d elements after break, want 3", visited)
+	}
+}
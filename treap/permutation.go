@@ -0,0 +1,56 @@
+package treap
+
+import "iter"
+
+/*
+Returns uniformly random permutation of numbers from 0 to n-1.
+Every number i is inserted into the random position from 0 to i of the implicit treap,
+which gives every permutation the same probability.
+
+	if n <= 0: return nil
+
+# Time complexity:
+  - Loglinear - time complexity is equal to n multiplied by height of the treap;
+*/
+func RandomPermutation(n int) []int {
+	if n <= 0 {
+		return nil
+	}
+	var root *node
+	for i := 0; i < n; i++ {
+		l, r := split(root, randomN(i+1)-1)
+		root = merge(merge(l, newNode(i)), r)
+	}
+	values := make([]int, n)
+	export(values, 0, root)
+	return values
+}
+
+/*
+Yields uniformly random permutation of numbers from 0 to n-1 lazily.
+Numbers are drawn without replacement from the treap of all numbers not yielded yet,
+so stopping early costs only the drawn numbers.
+
+	if n <= 0: yield nothing
+
+# Time complexity:
+  - Linear - for the start, treap of all numbers is built in a linear time;
+  - Logarithmic - for every drawn number, time complexity is equal to height of the treap;
+*/
+func RandomPermutationSeq(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		b := builder{spine: make([]*node, 0, 64)}
+		for i := 0; i < n; i++ {
+			b.push(i)
+		}
+		root := b.finish()
+		for root != nil {
+			l, r := split(root, randomN(root.size)-1)
+			m, r := split(r, 0)
+			root = merge(l, r)
+			if !yield(m.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+// Reports whether values are a permutation of numbers from 0 to n-1.
+func isPermutation(values []int, n int) bool {
+	sorted := slices.Sorted(slices.Values(values))
+	for i, value := range sorted {
+		if value != i {
+			return false
+		}
+	}
+	return len(sorted) == n
+}
+
+func TestRandomPermutation(t *testing.T) {
+	if RandomPermutation(0) != nil || RandomPermutation(-1) != nil {
+		t.Error("RandomPermutation() of non-positive size is not nil")
+	}
+	// Every one of 6 permutations of 3 numbers must be drawn about equally often.
+	counts := make(map[[3]int]int)
+	for range 6000 {
+		p := RandomPermutation(3)
+		if !isPermutation(p, 3) {
+			t.Fatalf("RandomPermutation(3) = %v", p)
+		}
+		counts[[3]int(p)]++
+	}
+	if len(counts) != 6 {
+		t.Fatalf("only %d permutations were drawn", len(counts))
+	}
+	for p, count := range counts {
+		if count < 800 || count > 1200 {
+			t.Errorf("permutation %v was drawn %d times of 6000", p, count)
+		}
+	}
+	if p := RandomPermutation(1000); !isPermutation(p, 1000) {
+		t.Errorf("RandomPermutation(1000) is not a permutation")
+	}
+}
+
+func TestRandomPermutationSeq(t *testing.T) {
+	counts := make(map[[3]int]int)
+	for range 6000 {
+		p := slices.Collect(RandomPermutationSeq(3))
+		if !isPermutation(p, 3) {
+			t.Fatalf("RandomPermutationSeq(3) yields %v", p)
+		}
+		counts[[3]int(p)]++
+	}
+	for p, count := range counts {
+		if len(counts) != 6 || count < 800 || count > 1200 {
+			t.Errorf("permutation %v was drawn %d times of 6000, %d permutations drawn", p, count, len(counts))
+		}
+	}
+	var prefix []int
+	for value := range RandomPermutationSeq(100) {
+		prefix = append(prefix, value)
+		if len(prefix) == 5 {
+			break
+		}
+	}
+	if len(prefix) != 5 || len(slices.Compact(slices.Sorted(slices.Values(prefix)))) != 5 {
+		t.Errorf("stopped permutation yields %v", prefix)
+	}
+	for range RandomPermutationSeq(0) {
+		t.Error("RandomPermutationSeq(0) yields a number")
+	}
+}
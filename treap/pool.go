@@ -0,0 +1,262 @@
+package treap
+
+/*
+Allocator of the treap nodes.
+Keeps free list of nodes that were deleted from the treap if recycling is enabled,
+nodes are linked via right son, so no additional memory is used.
+Counts allocated and freed nodes if accounting is enabled.
+Nodes reserved in advance are kept in a single slice and taken from it before new nodes are allocated.
+*/
+type pool struct {
+	free    *node
+	size    int
+	recycle bool
+	stats   *stats
+	arena   []node
+	// Pool is owned by the workspace and shared by all its treaps.
+	shared bool
+}
+
+/*
+Returns node with provided value and new priority.
+Reuses free node if there is one.
+
+	if p == nil: allocate new node
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (p *pool) get(value int) *node {
+	if p == nil {
+		return newNode(value)
+	}
+	n := p.take()
+	initNode(n, value, randomPriority(), mix(created.Add(1)))
+	return n
+}
+
+/*
+Returns free node with undefined fields, or allocates new one.
+
+	if p == nil: allocate new node
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (p *pool) take() *node {
+	if p == nil {
+		return new(node)
+	} else if p.free == nil && len(p.arena) > 0 {
+		n := &p.arena[0]
+		p.arena = p.arena[1:]
+		return n
+	} else if p.free == nil {
+		p.stats.allocated(1)
+		return new(node)
+	}
+	n := p.free
+	p.free = n.rson
+	p.size--
+	p.stats.reused(1)
+	return n
+}
+
+/*
+Puts single node into the free list.
+Node must be already unlinked from its sons.
+
+	if p == nil or recycling is disabled: only count the node as freed
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (p *pool) put(n *node) {
+	if p == nil {
+		return
+	}
+	p.stats.freed(1)
+	if !p.recycle {
+		return
+	}
+	*n = node{rson: p.free}
+	p.free = n
+	p.size++
+}
+
+/*
+Handles the subtree deleted from the treap.
+Subtree is released into the free list if recycling is enabled,
+otherwise it is left for the garbage collector and only counted.
+
+	if p == nil: do nothing
+
+# Time complexity:
+  - Linear - if recycling is enabled, time complexity is equal to size of the subtree;
+  - Constant - otherwise;
+*/
+func (p *pool) discard(n *node) {
+	if p == nil || n == nil {
+		return
+	} else if p.recycle {
+		p.release(n)
+		return
+	}
+	p.stats.freed(n.size)
+}
+
+/*
+Returns new allocator with the same settings, but with empty free list.
+
+	if p == nil: return nil
+	if pool is shared: return the same pool
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (p *pool) fork() *pool {
+	if p == nil {
+		return nil
+	} else if p.shared {
+		return p
+	}
+	return &pool{recycle: p.recycle, stats: p.stats}
+}
+
+/*
+Returns allocator of the treap, creating it if needed.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) allocator() *pool {
+	if t.opts.pool == nil {
+		t.opts.pool = &pool{}
+	}
+	return t.opts.pool
+}
+
+/*
+Drops allocator of the treap if it has nothing to do.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) trimAllocator() {
+	if p := t.opts.pool; p != nil && !p.recycle && p.stats == nil && !p.shared && len(p.arena) == 0 {
+		t.opts.pool = nil
+	}
+}
+
+/*
+Unlinks all nodes of the subtree, so no node points to another one.
+Nodes are put into the pool if it is provided.
+Tree is flattened by rotations, so no recursion or additional memory is used.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the subtree;
+*/
+func (p *pool) release(n *node) {
+	for n != nil {
+		if l := n.lson; l != nil {
+			n.lson = l.rson
+			l.rson = n
+			n = l
+			continue
+		}
+		next := n.rson
+		n.rson = nil
+		p.put(n)
+		n = next
+	}
+}
+
+/*
+Enables or disables recycling of the deleted nodes.
+While enabled, nodes released by `Destroy()`, `Cut()` and `Delete()` are kept in the free list of the treap
+and reused by the following insertions instead of allocating new memory.
+Deleting huge range this way leaves no garbage for the garbage collector,
+but makes `Cut()` linear in amount of deleted elements.
+
+Disabling recycling drops the free list.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SetRecycling(on bool) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	p := t.allocator()
+	p.recycle = on
+	if !on {
+		p.free, p.size = nil, 0
+	}
+	t.trimAllocator()
+}
+
+/*
+Deletes all elements of the treap and unlinks all its nodes.
+Dropping huge treap this way lets garbage collector reclaim memory faster,
+since it no longer needs to trace pointers between dead nodes.
+If recycling is enabled, nodes are returned into the free list.
+
+Treap stays empty and can be used afterwards.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) Destroy() {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	v := t.enter()
+	if !t.log(DeleteOp(0, size(t.root))) {
+		t.leave(v)
+		return
+	}
+	root := t.root
+	t.root = nil
+	t.deleted(0, size(root))
+	t.opts.pool.release(root)
+	t.leave(v)
+}
+
+/*
+Correctly initialize an empty treap with nodes reserved for n elements, same as `New()` followed by `Reserve()`.
+
+# Time complexity:
+  - Linear - time complexity is equal to n;
+*/
+func CreateWithCapacity(n int) Treap {
+	t := New()
+	t.Reserve(n)
+	return t
+}
+
+/*
+Allocates nodes in advance, so n elements can be inserted without allocating memory,
+for example before the bulk load of a huge treap.
+Nodes are allocated as a single slice, which is freed by the garbage collector only after all its nodes are.
+Free nodes kept by recycling are counted as reserved.
+Reserved nodes stay with the 1st result of `Split()`, the 2nd one starts without them.
+
+	if n is less or equal to the amount of reserved nodes: do nothing
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of newly reserved nodes;
+*/
+func (t *Treap) Reserve(n int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	p := t.allocator()
+	need := n - p.size - len(p.arena)
+	if need > 0 {
+		p.arena = append(p.arena, make([]node, need)...)
+		p.stats.allocated(need)
+	}
+	t.trimAllocator()
+}
@@ -0,0 +1,113 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+// Returns all nodes of the subtree in order.
+func nodesOf(n *node) []*node {
+	var nodes []*node
+	var w walker
+	w.seek(n, 0, false)
+	for n := w.next(); n != nil; n = w.next() {
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func TestDestroy(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5, 6, 7, 8)
+	nodes := nodesOf(tr.root)
+	tr.Destroy()
+	if tr.Size() != 0 {
+		t.Fatalf("Size() = %d after Destroy()", tr.Size())
+	}
+	for _, n := range nodes {
+		if n.lson != nil || n.rson != nil {
+			t.Fatal("node is still linked after Destroy()")
+		}
+	}
+	tr.PushBack(9)
+	if got := tr.Export(); !slices.Equal(got, []int{9}) {
+		t.Errorf("treap after Destroy() = %v, want [9]", got)
+	}
+}
+
+func TestDestroyRecycles(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.SetRecycling(true)
+	nodes := nodesOf(tr.root)
+	tr.Destroy()
+	if tr.opts.pool.size != len(nodes) {
+		t.Fatalf("free list has %d nodes, want %d", tr.opts.pool.size, len(nodes))
+	}
+	tr.Insert(0, 10)
+	if !slices.Contains(nodes, tr.root) {
+		t.Error("inserted element does not reuse a destroyed node")
+	}
+	if tr.opts.pool.size != len(nodes)-1 {
+		t.Errorf("free list has %d nodes after insertion, want %d", tr.opts.pool.size, len(nodes)-1)
+	}
+	tr.SetRecycling(false)
+	if tr.opts.pool != nil {
+		t.Error("allocator is kept after recycling was disabled")
+	}
+}
+
+func TestCutRecycles(t *testing.T) {
+	tr := New(make([]int, 100)...)
+	tr.SetRecycling(true)
+	tr.Cut(10, 59)
+	tr.Delete(0)
+	if tr.opts.pool.size != 51 {
+		t.Fatalf("free list has %d nodes after deleting 51 elements", tr.opts.pool.size)
+	}
+	allocs := testing.AllocsPerRun(50, func() {
+		tr.Insert(tr.Size()/2, 1)
+	})
+	if allocs != 0 {
+		t.Errorf("insertion with recycled nodes allocates %v times", allocs)
+	}
+	if tr.Size() != 100 {
+		t.Errorf("Size() = %d, want 100", tr.Size())
+	}
+}
+
+func TestCutWithoutRecyclingKeepsNodes(t *testing.T) {
+	tr := New(make([]int, 100)...)
+	tr.Cut(10, 59)
+	if tr.opts.pool != nil {
+		t.Error("allocator is created without recycling")
+	}
+}
+
+func TestReserve(t *testing.T) {
+	tr := CreateWithCapacity(100)
+	if len(tr.opts.pool.arena) != 100 {
+		t.Fatalf("CreateWithCapacity(100) reserved %d nodes", len(tr.opts.pool.arena))
+	}
+	allocs := testing.AllocsPerRun(50, func() {
+		tr.Insert(tr.Size()/2, 1)
+	})
+	if allocs != 0 {
+		t.Errorf("insertion with reserved nodes allocates %v times", allocs)
+	}
+	tr.Reserve(30)
+	if len(tr.opts.pool.arena) != 49 {
+		t.Errorf("Reserve(30) with 49 reserved nodes changed them to %d", len(tr.opts.pool.arena))
+	}
+	tr.Reserve(200)
+	if len(tr.opts.pool.arena) != 200 {
+		t.Errorf("Reserve(200) reserved %d nodes", len(tr.opts.pool.arena))
+	}
+	l, r := Split(&tr, 10)
+	if len(l.opts.pool.arena) != 200 || (r.opts.pool != nil && len(r.opts.pool.arena) != 0) {
+		t.Error("reserved nodes do not stay with the 1st result of Split()")
+	}
+	plain := New()
+	plain.Reserve(0)
+	if plain.opts.pool != nil {
+		t.Error("Reserve(0) created an allocator")
+	}
+}
@@ -0,0 +1,53 @@
+package treap
+
+/*
+Correctly initialize a treap from values with explicit priorities, the node with the biggest priority is the root.
+Shape of the treap is exactly the Cartesian tree of the priorities,
+so a shape saved by `Priorities()` is restored, or a Cartesian tree of any sequence is built.
+Equal priorities are resolved by tie-breaking words, so their order is not defined.
+Following insertions get random priorities as usual.
+
+	if lengths differ: only as many values as there are priorities are used
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func NewWithPriorities(values []int, priorities []int) Treap {
+	t := Treap{}
+	count := min(len(values), len(priorities))
+	b := builder{spine: make([]*node, 0, 64)}
+	for i := range count {
+		n := newNode(values[i])
+		n.priority = priorities[i]
+		b.attach(n)
+	}
+	v := t.enter()
+	t.root = b.finish()
+	t.leave(v)
+	return t
+}
+
+/*
+Returns priorities of all elements of the treap in order of the elements,
+for example to study the shape of the treap or to restore it by `NewWithPriorities()`.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) Priorities() []int {
+	if t == nil {
+		nilReceiver()
+		return nil
+	} else if t.root == nil {
+		return nil
+	}
+	v := t.enter()
+	priorities := make([]int, 0, t.root.size)
+	var w walker
+	w.seek(t.root, 0, false)
+	for n := w.next(); n != nil; n = w.next() {
+		priorities = append(priorities, n.priority)
+	}
+	t.exit(v)
+	return priorities
+}
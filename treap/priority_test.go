@@ -0,0 +1,52 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+// Reports whether both subtrees have the same shape and values.
+func sameShape(n1 *node, n2 *node) bool {
+	if n1 == nil || n2 == nil {
+		return n1 == n2
+	}
+	push(n1)
+	push(n2)
+	return n1.value == n2.value && sameShape(n1.lson, n2.lson) && sameShape(n1.rson, n2.rson)
+}
+
+func TestNewWithPriorities(t *testing.T) {
+	tr := NewWithPriorities([]int{10, 20, 30, 40, 50}, []int{3, 1, 9, 4, 2})
+	root := tr.root
+	if root.value != 30 || root.lson.value != 10 || root.rson.value != 40 {
+		t.Fatalf("Cartesian tree has root %d with sons %d and %d", root.value, root.lson.value, root.rson.value)
+	}
+	if root.lson.rson.value != 20 || root.rson.rson.value != 50 {
+		t.Errorf("Cartesian tree has grandsons %d and %d", root.lson.rson.value, root.rson.rson.value)
+	}
+	if got := tr.Priorities(); !slices.Equal(got, []int{3, 1, 9, 4, 2}) {
+		t.Errorf("Priorities() = %v", got)
+	}
+	if short := NewWithPriorities([]int{1, 2, 3}, []int{5, 6}); !slices.Equal(short.Export(), []int{1, 2}) {
+		t.Errorf("NewWithPriorities() with fewer priorities = %v", short.Export())
+	}
+}
+
+func TestPrioritiesRestoreShape(t *testing.T) {
+	values := make([]int, 500)
+	for i := range values {
+		values[i] = i
+	}
+	tr := New(values...)
+	tr.ReverseRange(100, 400)
+	restored := NewWithPriorities(tr.Export(), tr.Priorities())
+	if !sameShape(tr.root, restored.root) {
+		t.Error("NewWithPriorities() does not restore the shape saved by Priorities()")
+	}
+	restored.Insert(0, -1)
+	checkShape(t, restored.root)
+	var empty Treap
+	if empty.Priorities() != nil {
+		t.Error("Priorities() of an empty treap is not nil")
+	}
+}
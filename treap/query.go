@@ -0,0 +1,184 @@
+package treap
+
+import (
+	"math"
+	"slices"
+)
+
+/*
+Returns sum of the elements from index_left to index_right.
+Sums of all subtrees are kept by augmented treaps, so the range is not cut out,
+only 2 paths from the root are walked, like prefix sums of a Fenwick tree.
+
+	if index_left > index_right: return 0
+	if any index out of range: return sum of only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) RangeSum(index_left int, index_right int) int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	}
+	t.checkRange(index_left, index_right)
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size(t.root)-1)
+	if index_left > index_right {
+		return 0
+	}
+	v := t.enter()
+	t.augment()
+	result := prefixSum(t.root, index_right+1) - prefixSum(t.root, index_left)
+	t.exit(v)
+	return result
+}
+
+/*
+Returns sum of the first count elements of the subtree.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func prefixSum(n *node, count int) int {
+	result := 0
+	for n != nil && count > 0 {
+		if count >= n.size {
+			return result + n.aug.sum
+		}
+		push(n)
+		lsize := size(n.lson)
+		if count <= lsize {
+			n = n.lson
+			continue
+		}
+		result += sum(n.lson) + n.value
+		count -= lsize + 1
+		n = n.rson
+	}
+	return result
+}
+
+/*
+Returns the smallest element from index_left to index_right.
+Minimums of all subtrees are kept by augmented treaps, so only 2 paths from the root are walked.
+
+	if index_left > index_right: return 0
+	if any index out of range: return minimum of only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) RangeMin(index_left int, index_right int) int {
+	low, _ := t.bounds(index_left, index_right)
+	return low
+}
+
+/*
+Returns the biggest element from index_left to index_right, same as `RangeMin()`.
+
+	if index_left > index_right: return 0
+	if any index out of range: return maximum of only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) RangeMax(index_left int, index_right int) int {
+	_, high := t.bounds(index_left, index_right)
+	return high
+}
+
+/*
+Implementation of the `RangeMin()` and `RangeMax()` methods.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) bounds(index_left int, index_right int) (low int, high int) {
+	if t == nil {
+		nilReceiver()
+		return 0, 0
+	}
+	t.checkRange(index_left, index_right)
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size(t.root)-1)
+	if index_left > index_right {
+		return 0, 0
+	}
+	v := t.enter()
+	t.augment()
+	low, high = bounds(t.root, index_left, index_right+1)
+	t.exit(v)
+	return low, high
+}
+
+/*
+Returns minimum and maximum of the elements of the subtree with indexes from lo to hi-1.
+Range must intersect the subtree.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func bounds(n *node, lo int, hi int) (low int, high int) {
+	if lo <= 0 && hi >= n.size {
+		return n.aug.min, n.aug.max
+	}
+	push(n)
+	low, high = math.MaxInt, math.MinInt
+	lsize := size(n.lson)
+	if lo < lsize {
+		low, high = bounds(n.lson, lo, min(hi, lsize))
+	}
+	if lo <= lsize && lsize < hi {
+		low, high = min(low, n.value), max(high, n.value)
+	}
+	if hi > lsize+1 {
+		l, h := bounds(n.rson, max(lo-lsize-1, 0), hi-lsize-1)
+		low, high = min(low, l), max(high, h)
+	}
+	return low, high
+}
+
+/*
+Returns the q-quantile of the elements from index_left to index_right by the nearest rank,
+for example q = 0.99 gives the 99th percentile of latencies kept in the treap.
+Implicit treap does not order its values, so the range is exported and sorted,
+result is exact, but only the minimum (q <= 0) and the maximum (q >= 1) are found without the export.
+
+	if index_left > index_right: return 0
+	if any index out of range: return quantile of only existing elements of the range
+	if q is NaN: return 0
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Loglinear - time complexity is equal to length of the range multiplied by its logarithm, plus height of the treap;
+*/
+func (t *Treap) QuantileInRange(index_left int, index_right int, q float64) int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	} else if math.IsNaN(q) {
+		return 0
+	} else if q <= 0 {
+		return t.RangeMin(index_left, index_right)
+	} else if q >= 1 {
+		return t.RangeMax(index_left, index_right)
+	}
+	values := t.ExportRange(index_left, index_right)
+	if len(values) == 0 {
+		return 0
+	}
+	slices.Sort(values)
+	rank := int(math.Ceil(q*float64(len(values)))) - 1
+	return values[max(0, min(rank, len(values)-1))]
+}
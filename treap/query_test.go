@@ -0,0 +1,110 @@
+package treap
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// Returns the part of the values from index_left to index_right clamped to the existing indexes.
+func clamped(values []int, index_left int, index_right int) []int {
+	index_left, index_right = max(index_left, 0), min(index_right, len(values)-1)
+	if index_left > index_right {
+		return nil
+	}
+	return values[index_left : index_right+1]
+}
+
+func TestRangeSum(t *testing.T) {
+	r := rand.New(rand.NewPCG(29, 30))
+	model := make([]int, 100)
+	for i := range model {
+		model[i] = r.IntN(21) - 10
+	}
+	tr := New(model...)
+	for step := range 2000 {
+		if step%4 == 0 {
+			index, value := r.IntN(len(model)+1), r.IntN(21)-10
+			tr.Insert(index, value)
+			model = slices.Insert(model, index, value)
+		}
+		l, h := r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2
+		want := 0
+		for _, value := range clamped(model, l, h) {
+			want += value
+		}
+		if got := tr.RangeSum(l, h); got != want {
+			t.Fatalf("step %d: RangeSum(%d, %d) = %d, want %d", step, l, h, got, want)
+		}
+	}
+	var nilTreap *Treap
+	if nilTreap.RangeSum(0, 1) != 0 {
+		t.Error("RangeSum() of nil treap is not 0")
+	}
+}
+
+func TestRangeMinAndMax(t *testing.T) {
+	r := rand.New(rand.NewPCG(31, 32))
+	model := make([]int, 100)
+	for i := range model {
+		model[i] = r.IntN(1000) - 500
+	}
+	tr := New(model...)
+	for step := range 2000 {
+		switch step % 4 {
+		case 0:
+			index := r.IntN(len(model))
+			tr.Set(index, -step)
+			model[index] = -step
+		case 1:
+			l := r.IntN(len(model))
+			h := l + r.IntN(len(model)-l)
+			tr.ReverseRange(l, h)
+			slices.Reverse(model[l : h+1])
+		}
+		l, h := r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2
+		part := clamped(model, l, h)
+		low, high := 0, 0
+		if len(part) > 0 {
+			low, high = slices.Min(part), slices.Max(part)
+		}
+		if got := tr.RangeMin(l, h); got != low {
+			t.Fatalf("step %d: RangeMin(%d, %d) = %d, want %d", step, l, h, got, low)
+		}
+		if got := tr.RangeMax(l, h); got != high {
+			t.Fatalf("step %d: RangeMax(%d, %d) = %d, want %d", step, l, h, got, high)
+		}
+	}
+}
+
+func TestQuantileInRange(t *testing.T) {
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = 100 - i
+	}
+	tr := New(values...)
+	tests := []struct {
+		l, h int
+		q    float64
+		want int
+	}{
+		{0, 99, 0.5, 50},
+		{0, 99, 0.99, 99},
+		{0, 99, 0.001, 1},
+		{0, 99, -1, 1},
+		{0, 99, 2, 100},
+		{-5, 9, 0.5, 95},
+		{90, 200, 0.1, 1},
+		{5, 4, 0.5, 0},
+		{0, 99, math.NaN(), 0},
+	}
+	for _, tt := range tests {
+		if got := tr.QuantileInRange(tt.l, tt.h, tt.q); got != tt.want {
+			t.Errorf("QuantileInRange(%d, %d, %v) = %d, want %d", tt.l, tt.h, tt.q, got, tt.want)
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, values) {
+		t.Error("QuantileInRange() changed the treap")
+	}
+}
@@ -0,0 +1,127 @@
+package treap
+
+/*
+Window of the last pushed values with random access and aggregates,
+for example the last N samples of a monitoring agent.
+Values are kept in a treap, that evicts the oldest values from the front, see `NewBounded()`,
+so aggregates of the whole window are read from its root.
+
+# Window is unsafe to be used in parallel goroutines.
+*/
+type Recent struct {
+	t Treap
+}
+
+/*
+Correctly initialize an empty window, that keeps at most n last values.
+
+	if n <= 0: window is unlimited
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func NewRecent(n int) *Recent {
+	return &Recent{t: NewBounded(n, EvictFront)}
+}
+
+/*
+Appends values to the window, the oldest values beyond the capacity are evicted.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus amount of provided values;
+*/
+func (r *Recent) Push(values ...int) {
+	r.t.PushBack(values...)
+}
+
+/*
+Returns amount of values in the window.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (r *Recent) Len() int {
+	return r.t.Size()
+}
+
+/*
+Returns maximum amount of values in the window.
+
+	if window is unlimited: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (r *Recent) Cap() int {
+	return r.t.Limit()
+}
+
+/*
+Returns value on the given index and whether it exists, the oldest value has index 0.
+
+	if index out of range: return 0, false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (r *Recent) Get(index int) (int, bool) {
+	return r.t.Get(index)
+}
+
+/*
+Returns sum of all values in the window.
+
+	if window is empty: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (r *Recent) Sum() int {
+	r.t.augment()
+	return sum(r.t.root)
+}
+
+/*
+Returns the smallest value in the window.
+
+	if window is empty: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (r *Recent) Min() int {
+	if r.t.root == nil {
+		return 0
+	}
+	r.t.augment()
+	return r.t.root.aug.min
+}
+
+/*
+Returns the biggest value in the window.
+
+	if window is empty: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (r *Recent) Max() int {
+	if r.t.root == nil {
+		return 0
+	}
+	r.t.augment()
+	return r.t.root.aug.max
+}
+
+/*
+Returns all values of the window from the oldest to the newest.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of values in the window;
+*/
+func (r *Recent) Export() []int {
+	return r.t.Export()
+}
@@ -0,0 +1,58 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestRecent(t *testing.T) {
+	r := rand.New(rand.NewPCG(41, 42))
+	const n = 50
+	w := NewRecent(n)
+	var model []int
+	for step := range 1000 {
+		values := make([]int, r.IntN(8))
+		for i := range values {
+			values[i] = r.IntN(1000) - 500
+		}
+		w.Push(values...)
+		model = append(model, values...)
+		model = model[max(len(model)-n, 0):]
+		if w.Len() != len(model) || w.Cap() != n {
+			t.Fatalf("step %d: window has %d of %d values, want %d", step, w.Len(), w.Cap(), len(model))
+		}
+		if len(model) == 0 {
+			continue
+		}
+		want := 0
+		for _, value := range model {
+			want += value
+		}
+		if w.Sum() != want || w.Min() != slices.Min(model) || w.Max() != slices.Max(model) {
+			t.Fatalf("step %d: window has sum %d, min %d and max %d", step, w.Sum(), w.Min(), w.Max())
+		}
+		if i := r.IntN(len(model)); step%10 == 0 {
+			if got, ok := w.Get(i); !ok || got != model[i] {
+				t.Fatalf("step %d: Get(%d) = %d, %v, want %d", step, i, got, ok, model[i])
+			}
+		}
+	}
+	if got := w.Export(); !slices.Equal(got, model) {
+		t.Errorf("Export() = %v, want %v", got, model)
+	}
+}
+
+func TestRecentEmptyAndUnlimited(t *testing.T) {
+	w := NewRecent(0)
+	if w.Sum() != 0 || w.Min() != 0 || w.Max() != 0 || w.Cap() != 0 {
+		t.Error("empty window has non-zero aggregates")
+	}
+	if _, ok := w.Get(0); ok {
+		t.Error("Get(0) of an empty window exists")
+	}
+	w.Push(make([]int, 1000)...)
+	if w.Len() != 1000 {
+		t.Errorf("unlimited window keeps %d values, want 1000", w.Len())
+	}
+}
@@ -0,0 +1,140 @@
+package treap
+
+import (
+	"fmt"
+	"io"
+)
+
+/*
+Log of all operations applied to the treap since `Record()` was called.
+Together with the state of the treap at that moment it reproduces all following changes,
+for example to reproduce a bug report or to transfer the state between processes.
+*/
+type Log struct {
+	t   *Treap
+	ops []Op
+}
+
+/*
+Starts recording of all following changes of the treap.
+Every change is recorded as an operation with exact indexes, see `Op`.
+Log is not carried over by `Merge()` and `Split()`.
+
+	if t == nil: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Record() *Log {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	l := &Log{t: t}
+	t.logs = append(t.logs, l)
+	return l
+}
+
+/*
+Stops recording, already recorded operations are kept.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of active logs of the treap;
+*/
+func (l *Log) Stop() {
+	if l.t == nil {
+		return
+	}
+	for i, other := range l.t.logs {
+		if other == l {
+			l.t.logs = append(l.t.logs[:i:i], l.t.logs[i+1:]...)
+			break
+		}
+	}
+	l.t = nil
+}
+
+/*
+Returns all recorded operations in order.
+Returned slice must not be changed.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (l *Log) Ops() []Op {
+	return l.ops
+}
+
+/*
+Returns amount of recorded operations.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (l *Log) Len() int {
+	return len(l.ops)
+}
+
+/*
+Writes all recorded operations in the format of the journal, see `SetJournal()`.
+Implements io.WriterTo interface.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the log;
+*/
+func (l *Log) WriteTo(w io.Writer) (int64, error) {
+	written := int64(0)
+	var buf []byte
+	for _, op := range l.ops {
+		buf = appendRecord(buf[:0], op)
+		n, err := w.Write(buf)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+/*
+Reads the log written by `WriteTo()`.
+Returned log is not recording.
+
+	if reading fails: return the error
+	if any record is incomplete or corrupted: return ErrBadJournal
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the log;
+*/
+func ReadLog(r io.Reader) (*Log, error) {
+	l := &Log{}
+	for {
+		op, err := readRecord(r)
+		if err == io.EOF {
+			return l, nil
+		} else if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("%w: incomplete record", ErrBadJournal)
+		} else if err != nil {
+			return nil, err
+		}
+		l.ops = append(l.ops, op)
+	}
+}
+
+/*
+Applies all recorded operations onto the treap in order by `Apply()`.
+Treap must have the same elements, as the recorded treap had when recording started.
+
+	if any operation fails: return its error, all previous operations stay applied
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the log multiplied by height of the treap;
+*/
+func Replay(log *Log, onto *Treap) error {
+	for i, op := range log.ops {
+		if err := onto.Apply(op); err != nil {
+			return fmt.Errorf("treap: replay of operation %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package treap
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	start := tr.Export()
+	l := tr.Record()
+	tr.Insert(2, 9)
+	tr.Set(0, 7)
+	tr.Cut(3, 4)
+	tr.ReverseRange(0, 2)
+	tr.InsertSlice(0)
+	l.Stop()
+	tr.Insert(0, 5)
+	if l.Len() != 6 {
+		t.Errorf("Len() = %d, want 6", l.Len())
+	}
+	var buf bytes.Buffer
+	if _, err := l.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadLog(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	onto := New(start...)
+	if err := Replay(read, &onto); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := onto.Export(), tr.Export()[1:]; !slices.Equal(got, want) {
+		t.Errorf("replayed treap = %v, want %v", got, want)
+	}
+	short := New(1)
+	if err := Replay(read, &short); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Replay() onto another treap = %v, want ErrIndexOutOfRange", err)
+	}
+}
+
+func TestRecordedValuesAreCopied(t *testing.T) {
+	tr := New()
+	l := tr.Record()
+	values := []int{1, 2}
+	tr.PushBack(values...)
+	values[0] = 9
+	if got := l.Ops()[0].Values; !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("recorded values = %v, want [1 2]", got)
+	}
+}
+
+func TestReadLogIncomplete(t *testing.T) {
+	tr := New(1, 2)
+	l := tr.Record()
+	tr.Insert(0, 3)
+	var buf bytes.Buffer
+	l.WriteTo(&buf)
+	if _, err := ReadLog(bytes.NewReader(buf.Bytes()[:buf.Len()-2])); !errors.Is(err, ErrBadJournal) {
+		t.Errorf("ReadLog() of an incomplete log = %v, want ErrBadJournal", err)
+	}
+}
+
+func TestReadLogCorruptedLength(t *testing.T) {
+	log := insertHeader(1 << 45)
+	log[9] ^= 1
+	if _, err := ReadLog(bytes.NewReader(log)); !errors.Is(err, ErrBadJournal) {
+		t.Errorf("ReadLog() of a corrupted length = %v, want ErrBadJournal", err)
+	}
+	log = append(insertHeader(1<<45), make([]byte, 100)...)
+	if _, err := ReadLog(bytes.NewReader(log)); !errors.Is(err, ErrBadJournal) {
+		t.Errorf("ReadLog() of a huge incomplete record = %v, want ErrBadJournal", err)
+	}
+}
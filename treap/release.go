@@ -0,0 +1,50 @@
+//go:build !treapdebug
+
+package treap
+
+/*
+Generation embedded into every treap and node, empty in release builds.
+See `debug.go` for the debug version.
+*/
+type stamp struct{}
+
+/*
+State of the treap captured before an operation.
+See `debug.go` for the debug version.
+*/
+type visit struct {
+	guard *guard
+}
+
+/*
+Called before every operation on the treap.
+Only acquires ownership checks in release builds.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) enter() visit {
+	t.opts.guard.acquire()
+	return visit{t.opts.guard}
+}
+
+/*
+Called after every mutating operation on the treap.
+Only releases ownership checks in release builds.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) leave(v visit) {
+	v.guard.release()
+}
+
+/*
+Called after every read-only operation on the treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) exit(v visit) {
+	v.guard.release()
+}
@@ -0,0 +1,120 @@
+package treap
+
+import "slices"
+
+/*
+Reverses order of all elements of the treap in place, same as `ReverseRange()` for the whole treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations, plus the size of the treap if the journal or any log is active;
+*/
+func (t *Treap) Reverse() {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if t.root == nil {
+		return
+	}
+	v := t.enter()
+	n := t.root.size
+	if t.logReverse(0, n-1) {
+		reverse(t.root)
+		t.mirrored(0, n)
+	}
+	t.leave(v)
+}
+
+/*
+Reverses order of the elements from index_left to index_right.
+Range is cut out and only its root is reversed, sons get pending reversal,
+that is pushed down when they are reached by a following operation.
+Marks follow their elements, annotations inside of the range are mirrored.
+
+	if index_left > index_right: do nothing
+	if any index out of range: reverse only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus the length of the range if the journal or any log is active;
+*/
+func (t *Treap) ReverseRange(index_left int, index_right int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.checkRange(index_left, index_right)
+	if t.root == nil {
+		return
+	}
+	index_left = max(index_left, 0)
+	index_right = min(index_right, t.root.size-1)
+	if index_left >= index_right {
+		return
+	}
+	v := t.enter()
+	if t.logReverse(index_left, index_right) {
+		l, k := split(t.root, index_left-1)
+		m, r := split(k, index_right-index_left)
+		reverse(m)
+		t.root = merge(merge(l, m), r)
+		t.mirrored(index_left, index_right-index_left+1)
+	}
+	t.leave(v)
+}
+
+/*
+Same as `ReverseRange()`, but range must be fully inside the treap.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index_left > index_right or any index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) TryReverseRange(index_left int, index_right int) error {
+	if err := t.validRange(index_left, index_right); err != nil {
+		return err
+	}
+	t.ReverseRange(index_left, index_right)
+	return nil
+}
+
+/*
+Writes reversal of the elements from index_left to index_right as their replacement, same as `log()`.
+Range must be inside the treap.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the range, if the journal or any log is active;
+  - Constant - otherwise;
+*/
+func (t *Treap) logReverse(index_left int, index_right int) bool {
+	if !t.logging() {
+		return true
+	}
+	values := collect(t.root, index_left, index_right+1)
+	slices.Reverse(values)
+	return t.logReplace(index_left, values...)
+}
+
+/*
+Reverses the whole subtree: sons of the node and its hashes are swapped at once,
+reversal of the sons is left pending, see `push()`.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func reverse(n *node) {
+	if n == nil {
+		return
+	}
+	n.lson, n.rson = n.rson, n.lson
+	n.flip = !n.flip
+	if a := n.aug; a != nil {
+		a.hash, a.rhash = a.rhash, a.hash
+		a.folded = false
+	}
+}
@@ -0,0 +1,85 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	m := tr.Mark(1)
+	tr.Reverse()
+	if got := tr.Export(); !slices.Equal(got, []int{5, 4, 3, 2, 1}) {
+		t.Fatalf("Reverse() = %v", got)
+	}
+	if m.Index() != 3 {
+		t.Errorf("mark moved to %d after Reverse(), want 3", m.Index())
+	}
+	if reversed := New(5, 4, 3, 2, 1); tr.Hash() != reversed.Hash() {
+		t.Error("hash is not kept by Reverse()")
+	}
+	tr.Reverse()
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("double Reverse() = %v", got)
+	}
+	checkShape(t, tr.root)
+	empty := New()
+	empty.Reverse()
+
+	logged := New(1, 2, 3)
+	l := logged.Record()
+	logged.Reverse()
+	replayed := New(1, 2, 3)
+	if err := Replay(l, &replayed); err != nil || !slices.Equal(replayed.Export(), []int{3, 2, 1}) {
+		t.Errorf("replayed Reverse() = %v, %v", replayed.Export(), err)
+	}
+}
+
+func TestReverseRangeModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(25, 26))
+	model := make([]int, 200)
+	for i := range model {
+		model[i] = i
+	}
+	tr := New(model...)
+	for step := range 2000 {
+		l, h := r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2
+		tr.ReverseRange(l, h)
+		if l, h = max(l, 0), min(h, len(model)-1); l < h {
+			slices.Reverse(model[l : h+1])
+		}
+		if step%3 == 0 {
+			index := r.IntN(len(model) + 1)
+			tr.Insert(index, -step)
+			model = slices.Insert(model, index, -step)
+		}
+		if index := r.IntN(len(model)); tr.Find(index) != model[index] {
+			t.Fatalf("step %d: Find(%d) = %d, want %d", step, index, tr.Find(index), model[index])
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("Export() = %v, want %v", got, model)
+	}
+	if fresh := New(model...); tr.Hash() != fresh.Hash() || tr.RangeSum(10, 100) != fresh.RangeSum(10, 100) {
+		t.Error("augmentations are not kept by ReverseRange()")
+	}
+	checkShape(t, tr.root)
+}
+
+func TestTryReverseRange(t *testing.T) {
+	tr := New(1, 2, 3)
+	if err := tr.TryReverseRange(0, 1); err != nil || !slices.Equal(tr.Export(), []int{2, 1, 3}) {
+		t.Errorf("TryReverseRange(0, 1) = %v, %v", tr.Export(), err)
+	}
+	for _, r := range [][2]int{{-1, 1}, {1, 3}, {2, 1}} {
+		if err := tr.TryReverseRange(r[0], r[1]); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("TryReverseRange(%d, %d) = %v, want ErrIndexOutOfRange", r[0], r[1], err)
+		}
+	}
+	empty := New()
+	if err := empty.TryReverseRange(0, 0); !errors.Is(err, ErrEmptyTreap) {
+		t.Errorf("TryReverseRange() of empty treap = %v, want ErrEmptyTreap", err)
+	}
+}
@@ -0,0 +1,280 @@
+package treap
+
+/*
+Returns index of the first value satisfying the predicate, same as slices.IndexFunc.
+Values are visited in order and the walk stops on the first match.
+Predicate must not change the treap.
+
+	if no value satisfies the predicate: return -1
+	if t == nil: return -1
+
+# Time complexity:
+  - Linear - time complexity is equal to index of the match (or size of the treap) plus height of the treap;
+*/
+func (t *Treap) IndexFunc(pred func(int) bool) int {
+	if t == nil {
+		nilReceiver()
+		return -1
+	}
+	v := t.enter()
+	defer t.exit(v)
+	return indexFunc(t.root, pred, false)
+}
+
+/*
+Returns index of the last value satisfying the predicate, same as `IndexFunc()`.
+Values are visited from the last to the first.
+
+	if no value satisfies the predicate: return -1
+	if t == nil: return -1
+
+# Time complexity:
+  - Linear - time complexity is equal to distance of the match from the back (or size of the treap) plus height of the treap;
+*/
+func (t *Treap) LastIndexFunc(pred func(int) bool) int {
+	if t == nil {
+		nilReceiver()
+		return -1
+	}
+	v := t.enter()
+	defer t.exit(v)
+	return indexFunc(t.root, pred, true)
+}
+
+/*
+Returns the first value satisfying the predicate and whether it was found, same as `IndexFunc()`.
+
+	if no value satisfies the predicate: return 0, false
+	if t == nil: return 0, false
+
+# Time complexity:
+  - Linear - time complexity is equal to index of the match (or size of the treap) plus height of the treap;
+*/
+func (t *Treap) FindFunc(pred func(int) bool) (int, bool) {
+	if t == nil {
+		nilReceiver()
+		return 0, false
+	}
+	v := t.enter()
+	defer t.exit(v)
+	index := indexFunc(t.root, pred, false)
+	if index < 0 {
+		return 0, false
+	}
+	return find(t.root, index).value, true
+}
+
+/*
+Returns index of the first visited node satisfying the predicate.
+Nodes are visited from the last to the first if reverse is true.
+
+	if no node satisfies the predicate: return -1
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of visited nodes plus height of the treap;
+*/
+func indexFunc(root *node, pred func(int) bool, reverse bool) int {
+	var w walker
+	index, step := 0, 1
+	if reverse {
+		index, step = size(root)-1, -1
+	}
+	w.seek(root, index, reverse)
+	for n := w.next(); n != nil; n = w.next() {
+		if pred(n.value) {
+			return index
+		}
+		index += step
+	}
+	return -1
+}
+
+/*
+Splits treap before the first element violating the predicate, same as `Split()`.
+1st treap holds the longest prefix, all elements of which satisfy the predicate,
+2nd treap starts from the first violating element.
+Values are visited in order and the walk stops on the first violation.
+Old treap is left empty and marked as consumed, it must not be used afterwards.
+Predicate must not change the treap.
+
+	if all elements satisfy the predicate: 2nd treap is empty
+	if t == nil: return 2 empty treaps
+
+# Time complexity:
+  - Linear - time complexity is equal to index of the violation plus height of the treap;
+*/
+func SplitWhen(t *Treap, pred func(index int, value int) bool) (tl Treap, tr Treap) {
+	if t == nil {
+		return
+	}
+	v := t.enter()
+	var w walker
+	w.seek(t.root, 0, false)
+	index := 0
+	for n := w.next(); n != nil && pred(index, n.value); n = w.next() {
+		index++
+	}
+	t.exit(v)
+	return Split(t, index-1)
+}
+
+/*
+Returns the smallest index, on which the predicate is true, same as sort.Search.
+Predicate must be monotone: once it is true for an index, it is true for all following indexes,
+so the treap is descended once and the predicate is called only on the path from the root.
+Predicate must not change the treap.
+
+	if predicate is false for all elements: return size of the treap
+	if t == nil: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) SearchIndex(pred func(index int, value int) bool) int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	}
+	v := t.enter()
+	defer t.exit(v)
+	result, offset := size(t.root), 0
+	for n := t.root; n != nil; {
+		push(n)
+		index := offset + size(n.lson)
+		if pred(index, n.value) {
+			result = index
+			n = n.lson
+		} else {
+			offset = index + 1
+			n = n.rson
+		}
+	}
+	return result
+}
+
+/*
+Returns index of the first element equal to the value.
+Subtrees, whose minimum and maximum do not surround the value, are skipped without visiting,
+if the treap is augmented, see `SetAugmented()`, otherwise all elements before the match are visited.
+
+	if treap does not contain the value: return -1
+	if t == nil: return -1
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of visited nodes, that is the size of the treap in the worst case;
+*/
+func (t *Treap) IndexOf(value int) int {
+	if t == nil {
+		nilReceiver()
+		return -1
+	}
+	v := t.enter()
+	defer t.exit(v)
+	return indexOf(t.root, value, 0)
+}
+
+/*
+Reports whether the treap contains the value, same as `IndexOf()`.
+
+	if t == nil: return false
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of visited nodes, that is the size of the treap in the worst case;
+*/
+func (t *Treap) Contains(value int) bool {
+	return t.IndexOf(value) >= 0
+}
+
+/*
+Returns index of the first element of the subtree equal to the value, offset is index of its 1st element.
+
+	if subtree does not contain the value: return -1
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of visited nodes;
+*/
+func indexOf(n *node, value int, offset int) int {
+	for n != nil && (n.aug == nil || n.aug.min <= value && value <= n.aug.max) {
+		push(n)
+		if index := indexOf(n.lson, value, offset); index >= 0 {
+			return index
+		}
+		offset += size(n.lson)
+		if n.value == value {
+			return offset
+		}
+		offset++
+		n = n.rson
+	}
+	return -1
+}
+
+/*
+Returns amount of elements equal to the value.
+Subtrees, whose minimum and maximum do not surround the value, are skipped,
+and subtrees of only this value are counted by their sizes without visiting,
+if the treap is augmented, see `SetAugmented()`.
+
+	if t == nil: return 0
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of visited nodes, that is the size of the treap in the worst case;
+*/
+func (t *Treap) Count(value int) int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	}
+	v := t.enter()
+	defer t.exit(v)
+	return count(t.root, value)
+}
+
+/*
+Returns amount of elements satisfying the predicate.
+Values are visited in order, predicate must not change the treap.
+
+	if t == nil: return 0
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) CountFunc(pred func(int) bool) int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	}
+	v := t.enter()
+	defer t.exit(v)
+	result := 0
+	var w walker
+	w.seek(t.root, 0, false)
+	for n := w.next(); n != nil; n = w.next() {
+		if pred(n.value) {
+			result++
+		}
+	}
+	return result
+}
+
+/*
+Returns amount of elements of the subtree equal to the value.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of visited nodes;
+*/
+func count(n *node, value int) int {
+	result := 0
+	for n != nil && (n.aug == nil || n.aug.min <= value && value <= n.aug.max) {
+		if n.aug != nil && n.aug.min == n.aug.max {
+			return result + n.size
+		}
+		push(n)
+		result += count(n.lson, value)
+		if n.value == value {
+			result++
+		}
+		n = n.rson
+	}
+	return result
+}
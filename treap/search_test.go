@@ -0,0 +1,198 @@
+package treap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIndexFunc(t *testing.T) {
+	tr := New(5, 2, 8, 2, 9)
+	tr.ReverseRange(0, 4)
+	values := tr.Export()
+	for _, pred := range []func(int) bool{
+		func(x int) bool { return x == 2 },
+		func(x int) bool { return x > 100 },
+		func(x int) bool { return x%2 == 1 },
+	} {
+		if got, want := tr.IndexFunc(pred), slices.IndexFunc(values, pred); got != want {
+			t.Errorf("IndexFunc() = %d, want %d", got, want)
+		}
+		want := -1
+		for i, value := range values {
+			if pred(value) {
+				want = i
+			}
+		}
+		if got := tr.LastIndexFunc(pred); got != want {
+			t.Errorf("LastIndexFunc() = %d, want %d", got, want)
+		}
+	}
+	visited := 0
+	tr.IndexFunc(func(x int) bool {
+		visited++
+		return x == 8
+	})
+	if visited != 3 {
+		t.Errorf("IndexFunc() visited %d values, want 3", visited)
+	}
+	var empty *Treap
+	if empty.IndexFunc(func(int) bool { return true }) != -1 || empty.LastIndexFunc(func(int) bool { return true }) != -1 {
+		t.Error("nil treap has a matching index")
+	}
+}
+
+func TestSplitWhen(t *testing.T) {
+	tr := New(1, 3, 5, 4, 7)
+	visited := 0
+	l, r := SplitWhen(&tr, func(index int, value int) bool {
+		visited++
+		return index == 0 || value%2 == 1
+	})
+	if !slices.Equal(l.Export(), []int{1, 3, 5}) || !slices.Equal(r.Export(), []int{4, 7}) {
+		t.Errorf("SplitWhen() = %v, %v, want [1 3 5], [4 7]", l.Export(), r.Export())
+	}
+	if visited != 4 {
+		t.Errorf("SplitWhen() called the predicate %d times, want 4", visited)
+	}
+	if tr.Size() != 0 {
+		t.Error("old treap is not left empty")
+	}
+	all, none := SplitWhen(&l, func(int, int) bool { return true })
+	if all.Size() != 3 || none.Size() != 0 {
+		t.Errorf("SplitWhen() of a satisfying treap has sizes %d and %d", all.Size(), none.Size())
+	}
+	if l, r := SplitWhen(nil, nil); l.Size() != 0 || r.Size() != 0 {
+		t.Error("SplitWhen(nil) returns non-empty treaps")
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = 2 * i
+	}
+	tr := New(values...)
+	for target := -1; target <= 200; target++ {
+		calls := 0
+		got := tr.SearchIndex(func(index int, value int) bool {
+			calls++
+			if value != values[index] {
+				t.Fatalf("predicate is called with %d on index %d", value, index)
+			}
+			return value >= target
+		})
+		if want, _ := slices.BinarySearch(values, target); got != want {
+			t.Fatalf("SearchIndex() of %d = %d, want %d", target, got, want)
+		}
+		if calls > height(tr.root) {
+			t.Fatalf("SearchIndex() called the predicate %d times, height is %d", calls, height(tr.root))
+		}
+	}
+	var empty *Treap
+	if got := empty.SearchIndex(nil); got != 0 {
+		t.Errorf("SearchIndex() of a nil treap = %d, want 0", got)
+	}
+}
+
+func TestIndexOf(t *testing.T) {
+	r := rand.New(rand.NewPCG(51, 52))
+	for _, augmented := range []bool{false, true} {
+		model := make([]int, 200)
+		for i := range model {
+			model[i] = r.IntN(100)
+		}
+		tr := New(model...)
+		tr.SetAugmented(augmented)
+		for step := range 300 {
+			l := r.IntN(len(model))
+			h := l + r.IntN(len(model)-l)
+			if step%2 == 0 {
+				tr.ReverseRange(l, h)
+				slices.Reverse(model[l : h+1])
+			} else if augmented {
+				delta := r.IntN(11) - 5
+				tr.RangeAdd(l, h, delta)
+				for i := l; i <= h; i++ {
+					model[i] += delta
+				}
+			}
+			value := r.IntN(120) - 10
+			if got, want := tr.IndexOf(value), slices.Index(model, value); got != want {
+				t.Fatalf("augmented %v, step %d: IndexOf(%d) = %d, want %d", augmented, step, value, got, want)
+			}
+			if got, want := tr.Contains(value), slices.Contains(model, value); got != want {
+				t.Fatalf("augmented %v, step %d: Contains(%d) = %v, want %v", augmented, step, value, got, want)
+			}
+		}
+	}
+	var empty *Treap
+	if empty.IndexOf(0) != -1 || empty.Contains(0) {
+		t.Error("nil treap contains a value")
+	}
+}
+
+func TestCount(t *testing.T) {
+	r := rand.New(rand.NewPCG(53, 54))
+	for _, augmented := range []bool{false, true} {
+		model := make([]int, 200)
+		tr := New(model...)
+		tr.SetAugmented(augmented)
+		for step := range 300 {
+			l := r.IntN(len(model))
+			h := l + r.IntN(len(model)-l)
+			value := r.IntN(5)
+			if augmented && step%2 == 0 {
+				tr.RangeAssign(l, h, value)
+			} else {
+				for i := l; i <= h; i++ {
+					tr.Set(i, value)
+				}
+			}
+			for i := l; i <= h; i++ {
+				model[i] = value
+			}
+			value = r.IntN(6) - 1
+			want := 0
+			for _, x := range model {
+				if x == value {
+					want++
+				}
+			}
+			if got := tr.Count(value); got != want {
+				t.Fatalf("augmented %v, step %d: Count(%d) = %d, want %d", augmented, step, value, got, want)
+			}
+			if got := tr.CountFunc(func(x int) bool { return x == value }); got != want {
+				t.Fatalf("augmented %v, step %d: CountFunc() = %d, want %d", augmented, step, got, want)
+			}
+		}
+	}
+	tr := New(3, 1, 4, 1, 5)
+	var visited []int
+	tr.CountFunc(func(x int) bool {
+		visited = append(visited, x)
+		return false
+	})
+	if !slices.Equal(visited, []int{3, 1, 4, 1, 5}) {
+		t.Errorf("CountFunc() visited %v", visited)
+	}
+	var empty *Treap
+	if empty.Count(0) != 0 || empty.CountFunc(nil) != 0 {
+		t.Error("nil treap counts elements")
+	}
+}
+
+func TestFindFunc(t *testing.T) {
+	tr := New(4, 7, 10, 13, 16)
+	tr.ReverseRange(0, 4)
+	if got, ok := tr.FindFunc(func(x int) bool { return x%2 == 1 }); !ok || got != 13 {
+		t.Errorf("FindFunc() of an odd value = %d, %v, want 13", got, ok)
+	}
+	if got, ok := tr.FindFunc(func(x int) bool { return x < 0 }); ok || got != 0 {
+		t.Errorf("FindFunc() of a negative value = %d, %v, want 0, false", got, ok)
+	}
+	var empty *Treap
+	if _, ok := empty.FindFunc(func(int) bool { return true }); ok {
+		t.Error("nil treap has a matching value")
+	}
+}
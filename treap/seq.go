@@ -0,0 +1,56 @@
+package treap
+
+import "iter"
+
+/*
+Correctly initialize a treap from all values of the sequence, same as `New()`,
+for example from slices.Values, maps.Keys or a custom generator.
+Values are built into a treap while they are yielded, so no intermediate slice is allocated.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of yielded values;
+*/
+func FromSeq(seq iter.Seq[int]) Treap {
+	t := Treap{}
+	t.AppendSeq(seq)
+	return t
+}
+
+/*
+Insert all values of the sequence to the back of the treap, same as `PushBack()`.
+Sequence is read before the treap is changed, so it may read the treap itself.
+
+If not all values fit into the treap: do nothing.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of yielded values plus height of the treap;
+*/
+func (t *Treap) AppendSeq(seq iter.Seq[int]) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	b := builder{spine: make([]*node, 0, 64), pool: t.opts.pool}
+	for value := range seq {
+		b.push(value)
+	}
+	root := b.finish()
+	if root == nil {
+		return
+	} else if !t.fits(root.size) {
+		t.opts.pool.discard(root)
+		return
+	}
+	v := t.enter()
+	index, count := size(t.root), root.size
+	// Journal and logs need the values themselves.
+	if t.logging() && !t.log(InsertOp(index, collect(root, 0, count)...)) {
+		t.opts.pool.discard(root)
+		t.exit(v)
+		return
+	}
+	t.root = merge(t.root, root)
+	t.inserted(index, count)
+	t.evict()
+	t.leave(v)
+}
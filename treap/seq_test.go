@@ -0,0 +1,66 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFromSeq(t *testing.T) {
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = i * i
+	}
+	tr := FromSeq(slices.Values(values))
+	if got := tr.Export(); !slices.Equal(got, values) {
+		t.Fatalf("FromSeq() = %v", got)
+	}
+	checkShape(t, tr.root)
+	empty := FromSeq(slices.Values([]int(nil)))
+	if empty.Size() != 0 {
+		t.Errorf("FromSeq() of an empty sequence has size %d", empty.Size())
+	}
+}
+
+func TestAppendSeq(t *testing.T) {
+	tr := New(1, 2, 3)
+	// Sequence reads the treap itself, before it is changed.
+	tr.AppendSeq(func(yield func(int) bool) {
+		for _, value := range tr.All() {
+			if !yield(10 * value) {
+				return
+			}
+		}
+	})
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3, 10, 20, 30}) {
+		t.Fatalf("AppendSeq() = %v", got)
+	}
+	tr.SetLimit(7)
+	tr.AppendSeq(slices.Values([]int{4, 5}))
+	if tr.Size() != 6 {
+		t.Errorf("AppendSeq() over the limit changed the size to %d", tr.Size())
+	}
+	m := tr.Mark(5)
+	tr.AppendSeq(slices.Values([]int{4}))
+	if m.Index() != 5 || tr.Size() != 7 {
+		t.Errorf("AppendSeq() moved the mark to %d, size is %d", m.Index(), tr.Size())
+	}
+}
+
+func TestAppendSeqNotifies(t *testing.T) {
+	// Appended values often become the root, so every append is seen with its own count.
+	for trial := range 200 {
+		tr := New(1, 2, 3)
+		var calls []string
+		tr.AddHooks(recordingHooks(&calls))
+		m := tr.Mark(3)
+		m.SetBias(StickRight)
+		tr.AppendSeq(slices.Values([]int{4}))
+		tr.AppendSeq(slices.Values([]int{5, 6}))
+		if want := []string{"insert 3 1", "insert 4 2"}; !slices.Equal(calls, want) {
+			t.Fatalf("trial %d: hooks of AppendSeq() = %v, want %v", trial, calls, want)
+		}
+		if m.Index() != 6 {
+			t.Fatalf("trial %d: mark after AppendSeq() is %d, want 6", trial, m.Index())
+		}
+	}
+}
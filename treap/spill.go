@@ -0,0 +1,169 @@
+package treap
+
+import (
+	"errors"
+	"os"
+)
+
+/*
+Configuration and state of spilling of the paged sequence, see `SetSpill()`.
+*/
+type spill struct {
+	limit    int
+	dir      string
+	inserted int
+	err      error
+}
+
+/*
+Frozen treap, that cold pieces of the paged sequence read from.
+Path is the spill file of the frozen treap, it is empty for the frozen treap provided to `NewPaged()`.
+*/
+type source struct {
+	frozen *Frozen
+	path   string
+}
+
+/*
+Closes the frozen treap and removes its spill file.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (s source) close() error {
+	err := s.frozen.Close()
+	if s.path != "" {
+		err = errors.Join(err, os.Remove(s.path))
+	}
+	return err
+}
+
+/*
+Enables automatic spilling of the inserted elements to the disk.
+Once more than limit elements were inserted since the previous spill,
+all hot pieces are written by `Spill()` into a new file of the directory, which is opened by `OpenFrozen()`,
+so inserted elements become cold and are faulted back in by the operating system only when they are read.
+This way the sequence holds at most limit elements in the heap memory, however big it is.
+Pieces are not joined by spilling, so the sequence still has to be compacted after many edits, see `Paged`.
+
+Frozen treaps and spill files, from which no element is read anymore, are closed and removed on the next spill,
+all others are closed and removed by `Close()`.
+If automatic spilling fails, it is stopped and the error is reported by `SpillErr()`.
+
+	if limit <= 0: spilling is disabled
+	if dir == "": default directory for temporary files is used
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (p *Paged) SetSpill(limit int, dir string) {
+	p.spill.limit, p.spill.dir = limit, dir
+}
+
+/*
+Writes all hot pieces into a new spill file and makes them cold pieces of it,
+same as automatic spilling of `SetSpill()`.
+Cold pieces are not read, so only the elements held in the heap memory are written.
+Successful spill resumes automatic spilling, if it was stopped by an error.
+
+	if there are no hot pieces: only unused frozen treaps are closed
+	if spilling failed: return the error, sequence is not changed
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of pieces plus amount of hot elements;
+*/
+func (p *Paged) Spill() error {
+	var hot []*piece
+	used := make(map[*Frozen]bool, len(p.sources))
+	for pc := range p.pieces() {
+		if pc.hot != nil {
+			hot = append(hot, pc)
+		} else {
+			used[pc.base] = true
+		}
+	}
+	var sources []source
+	if len(hot) > 0 {
+		var values []int
+		for _, pc := range hot {
+			values = pc.hot.ExportAppend(values)
+		}
+		s, err := p.write(values)
+		if err != nil {
+			return err
+		}
+		from := 0
+		for _, pc := range hot {
+			pc.base, pc.from, pc.hot = s.frozen, from, nil
+			from += pc.length
+		}
+		sources = append(sources, s)
+	}
+	// Sources without cold pieces are never read again.
+	var closeErr error
+	for _, old := range p.sources {
+		if used[old.frozen] {
+			sources = append(sources, old)
+		} else {
+			closeErr = errors.Join(closeErr, old.close())
+		}
+	}
+	p.sources, p.spill.inserted, p.spill.err = sources, 0, nil
+	return closeErr
+}
+
+/*
+Writes values into a new spill file and opens it.
+
+	if writing or opening failed: spill file is removed
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of values;
+*/
+func (p *Paged) write(values []int) (source, error) {
+	file, err := os.CreateTemp(p.spill.dir, "treap-spill-*.frozen")
+	if err != nil {
+		return source{}, err
+	}
+	_, err = writeBalanced(file, len(values), func(index int) int {
+		return values[index]
+	})
+	if err = errors.Join(err, file.Close()); err != nil {
+		os.Remove(file.Name())
+		return source{}, err
+	}
+	frozen, err := OpenFrozen(file.Name())
+	if err != nil {
+		os.Remove(file.Name())
+		return source{}, err
+	}
+	return source{frozen: frozen, path: file.Name()}, nil
+}
+
+/*
+Returns the error of automatic spilling, after which it was stopped.
+
+	if automatic spilling did not fail: return nil
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (p *Paged) SpillErr() error {
+	return p.spill.err
+}
+
+/*
+Spills the sequence if more elements were inserted than the limit allows.
+Error is remembered, since the calling edit cannot return it, and stops automatic spilling.
+
+# Time complexity:
+  - Constant - if the limit is not exceeded;
+  - Linear - otherwise, same as `Spill()`;
+*/
+func (p *Paged) spillOver() {
+	if p.spill.limit > 0 && p.spill.err == nil && p.spill.inserted > p.spill.limit {
+		if err := p.Spill(); err != nil {
+			p.spill.err = err
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Returns paged sequence opened from a new file with provided values.
+func openPaged(t *testing.T, values []int) *Paged {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "base.fz")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := New(values...)
+	if _, err := tr.Freeze().WriteTo(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	p, err := OpenPaged(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Returns sizes of all files of the directory.
+func fileSizes(t *testing.T, dir string) []int64 {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sizes []int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, info.Size())
+	}
+	return sizes
+}
+
+func TestSpillWritesOnlyHotPieces(t *testing.T) {
+	p := openPaged(t, make([]int, 1000))
+	defer p.Close()
+	dir := t.TempDir()
+	p.SetSpill(0, dir)
+	p.Insert(10, 1)
+	p.Insert(500, 2)
+	p.Insert(501, 3)
+	if err := p.Spill(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fileSizes(t, dir); !slices.Equal(got, []int64{frozenHeader + 16*3}) {
+		t.Errorf("spill files have sizes %v, want only 3 hot elements", got)
+	}
+	for pc := range p.pieces() {
+		if pc.hot != nil {
+			t.Error("hot piece is left after Spill()")
+		}
+	}
+	if got := []int{p.Find(10), p.Find(500), p.Find(501), p.Find(502)}; !slices.Equal(got, []int{1, 2, 3, 0}) {
+		t.Errorf("values after Spill() = %v", got)
+	}
+}
+
+func TestSpillClosesUnusedSources(t *testing.T) {
+	p := openPaged(t, make([]int, 100))
+	original := p.sources[0].frozen
+	dir := t.TempDir()
+	p.SetSpill(0, dir)
+	p.Insert(0, 1)
+	if err := p.Spill(); err != nil {
+		t.Fatal(err)
+	}
+	p.Cut(1, 100)
+	p.Insert(1, 2)
+	if err := p.Spill(); err != nil {
+		t.Fatal(err)
+	}
+	if slices.ContainsFunc(p.sources, func(s source) bool { return s.frozen == original }) {
+		t.Error("opened file is not closed after all its elements were cut")
+	}
+	p.Cut(0, 0)
+	if err := p.Spill(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fileSizes(t, dir); len(got) != 1 {
+		t.Errorf("%d spill files are left, want 1", len(got))
+	}
+	if got := p.Export(); !slices.Equal(got, []int{2}) {
+		t.Errorf("Export() = %v, want [2]", got)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fileSizes(t, dir); len(got) != 0 {
+		t.Errorf("%d spill files are left after Close()", len(got))
+	}
+}
+
+func TestSpillModel(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	model := []int{1, 2, 3}
+	base := New(model...)
+	p := NewPaged(base.Freeze())
+	dir := t.TempDir()
+	p.SetSpill(10, dir)
+	for i := range 500 {
+		index, value := r.IntN(len(model)+1), r.IntN(100)
+		p.Insert(index, value)
+		model = slices.Insert(model, index, value)
+		if i%5 == 0 {
+			l := r.IntN(len(model))
+			h := l + r.IntN(min(3, len(model)-l))
+			p.Cut(l, h)
+			model = slices.Delete(model, l, h+1)
+		}
+	}
+	if err := p.SpillErr(); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Export(); !slices.Equal(got, model) {
+		t.Fatalf("Export() = %v, want %v", got, model)
+	}
+	hot := 0
+	for pc := range p.pieces() {
+		if pc.hot != nil {
+			hot += pc.length
+		}
+	}
+	if hot > 10 {
+		t.Errorf("%d elements are held in the heap, want at most 10", hot)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := fileSizes(t, dir); len(got) != 0 {
+		t.Errorf("%d spill files are left after Close()", len(got))
+	}
+}
+
+func TestSpillError(t *testing.T) {
+	base := New(1, 2, 3)
+	p := NewPaged(base.Freeze())
+	defer p.Close()
+	p.SetSpill(2, filepath.Join(t.TempDir(), "missing"))
+	for i := range 5 {
+		p.Insert(0, i)
+	}
+	if p.SpillErr() == nil {
+		t.Fatal("SpillErr() = nil, want error of missing directory")
+	}
+	if got := p.Export(); !slices.Equal(got, []int{4, 3, 2, 1, 0, 1, 2, 3}) {
+		t.Fatalf("Export() after failed spill = %v", got)
+	}
+	if err := p.Spill(); err == nil || errors.Is(err, ErrBadFrozen) {
+		t.Errorf("Spill() = %v, want error of missing directory", err)
+	}
+	p.SetSpill(2, t.TempDir())
+	if err := p.Spill(); err != nil || p.SpillErr() != nil {
+		t.Errorf("Spill() into existing directory = %v, SpillErr() = %v", err, p.SpillErr())
+	}
+}
@@ -0,0 +1,196 @@
+package treap
+
+/*
+Inserts all elements of the other treap, so the 1st of them gets the given index.
+Treap is split on the index and the other treap is merged into the middle, no values are copied.
+Other treap is left empty and marked as consumed, it must not be used afterwards.
+
+	if index < 0: insert to the front
+	if index > size: insert to the back
+	if other == nil or other == t: do nothing
+
+If not all elements fit into the treap: do nothing, other treap is not consumed.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap, plus size of the other treap if the journal or any log is active;
+*/
+func (t *Treap) InsertTreap(index int, other *Treap) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if other == nil || other == t {
+		return
+	} else if !t.fits(size(other.root)) {
+		return
+	}
+	index = max(0, min(index, size(t.root)))
+	v := t.enter()
+	vo := other.enter()
+	count := size(other.root)
+	// Journal and logs need the values themselves.
+	if count > 0 && t.logging() && !t.log(InsertOp(index, collect(other.root, 0, count)...)) {
+		other.exit(vo)
+		t.exit(v)
+		return
+	}
+	if count > 0 {
+		l, r := split(t.root, index-1)
+		t.root = merge(merge(l, other.root), r)
+		t.inserted(index, count)
+		t.evict()
+	}
+	t.leave(vo)
+	t.leave(v)
+	other.consume()
+}
+
+/*
+Inserts all provided values, so the 1st of them gets the given index.
+Values are built into a treap in a linear time and spliced in by a single split and 2 merges,
+instead of a split and merge for every value.
+
+	if index < 0: insert to the front
+	if index > size: insert to the back
+
+If not all values fit into the treap: do nothing.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
+*/
+func (t *Treap) InsertSlice(index int, values ...int) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if len(values) == 0 || !t.fits(len(values)) {
+		return
+	}
+	index = max(0, min(index, size(t.root)))
+	v := t.enter()
+	if !t.log(InsertOp(index, values...)) {
+		t.exit(v)
+		return
+	}
+	l, r := split(t.root, index-1)
+	t.root = merge(merge(l, build(values, false, t.opts.pool)), r)
+	t.inserted(index, len(values))
+	t.evict()
+	t.leave(v)
+}
+
+/*
+Same as `InsertSlice()`, but reports why the values were not inserted.
+
+	if t == nil: return ErrNilTreap
+	if treap was consumed: return ErrConsumedTreap
+	if values do not fit: return ErrSizeLimit
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
+*/
+func (t *Treap) TryInsertSlice(index int, values ...int) error {
+	if err := t.validInsert(len(values)); err != nil {
+		return err
+	}
+	t.InsertSlice(index, values...)
+	return nil
+}
+
+/*
+Moves the elements from index_left to index_right, so they are placed right before the element on the index dest,
+for example to reorder a playlist or to move a paragraph.
+Dest is an index of the treap before the move, range is cut out and merged back by 3 splits and merges.
+Move is seen as deletion followed by insertion, so marks inside of the range stay at the place of the range.
+
+	if index_left > index_right: do nothing
+	if any index out of range: move only existing elements of the range
+	if dest < 0: move to the front
+	if dest > size: move to the back
+	if dest is inside of the range or right after it: do nothing
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus the length of the range if the journal or any log is active;
+*/
+func (t *Treap) MoveRange(index_left int, index_right int, dest int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.checkRange(index_left, index_right)
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size(t.root)-1)
+	dest = max(0, min(dest, size(t.root)))
+	if index_left > index_right || (index_left <= dest && dest <= index_right+1) {
+		return
+	}
+	v := t.enter()
+	count := index_right - index_left + 1
+	if dest > index_right {
+		dest -= count
+	}
+	var values []int
+	if t.logging() {
+		values = collect(t.root, index_left, index_right+1)
+	}
+	if t.log(DeleteOp(index_left, count)) {
+		l, k := split(t.root, index_left-1)
+		m, r := split(k, count-1)
+		t.root = merge(l, r)
+		t.deleted(index_left, count)
+		if t.log(InsertOp(dest, values...)) {
+			l, r = split(t.root, dest-1)
+			t.root = merge(merge(l, m), r)
+			t.inserted(dest, count)
+		} else {
+			t.opts.pool.discard(m)
+		}
+	}
+	t.leave(v)
+}
+
+/*
+Cyclically shifts all elements k positions to the left, so the element on the index k becomes the first.
+Same as `MoveRange()` of the first k elements to the back, which is a single split and merge.
+
+	if k < 0: shift to the right, same as `RotateRight()`
+	if k >= size: shift by k modulo size
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus k if the journal or any log is active;
+*/
+func (t *Treap) RotateLeft(k int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	n := size(t.root)
+	if n == 0 {
+		return
+	}
+	k = (k%n + n) % n
+	if k != 0 {
+		t.MoveRange(0, k-1, n)
+	}
+}
+
+/*
+Cyclically shifts all elements k positions to the right, so the last k elements become the first, same as `RotateLeft()`.
+
+	if k < 0: shift to the left
+	if k >= size: shift by k modulo size
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus k if the journal or any log is active;
+*/
+func (t *Treap) RotateRight(k int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	n := size(t.root)
+	if n == 0 {
+		return
+	}
+	t.RotateLeft(n - k%n)
+}
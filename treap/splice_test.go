@@ -0,0 +1,176 @@
+package treap
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestInsertTreap(t *testing.T) {
+	tests := []struct {
+		index int
+		want  []int
+	}{
+		{-3, []int{7, 8, 1, 2, 3}},
+		{1, []int{1, 7, 8, 2, 3}},
+		{3, []int{1, 2, 3, 7, 8}},
+		{10, []int{1, 2, 3, 7, 8}},
+	}
+	for _, tt := range tests {
+		tr, other := New(1, 2, 3), New(7, 8)
+		m := tr.Mark(2)
+		tr.InsertTreap(tt.index, &other)
+		if got := tr.Export(); !slices.Equal(got, tt.want) {
+			t.Errorf("InsertTreap(%d) = %v, want %v", tt.index, got, tt.want)
+		}
+		if other.root != nil || !other.consumed {
+			t.Errorf("InsertTreap(%d) did not consume the other treap", tt.index)
+		}
+		if want := slices.Index(tt.want, 3); m.Index() != want {
+			t.Errorf("InsertTreap(%d) moved the mark to %d, want %d", tt.index, m.Index(), want)
+		}
+		checkShape(t, tr.root)
+	}
+}
+
+func TestInsertTreapDoesNothing(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.InsertTreap(0, &tr)
+	tr.InsertTreap(0, nil)
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("InsertTreap() of itself or nil = %v", got)
+	}
+	tr.SetLimit(4)
+	other := New(4, 5)
+	tr.InsertTreap(3, &other)
+	if tr.Size() != 3 || other.consumed || !slices.Equal(other.Export(), []int{4, 5}) {
+		t.Errorf("InsertTreap() over the limit changed the treaps to %v and %v", tr.Export(), other.Export())
+	}
+	empty := New()
+	tr.InsertTreap(1, &empty)
+	if tr.Size() != 3 || !empty.consumed {
+		t.Errorf("InsertTreap() of an empty treap = %v", tr.Export())
+	}
+}
+
+// Returns values with the range from index_left to index_right moved before the dest, same as `MoveRange()`.
+func moved(values []int, index_left int, index_right int, dest int) []int {
+	index_left, index_right = max(index_left, 0), min(index_right, len(values)-1)
+	dest = max(0, min(dest, len(values)))
+	if index_left > index_right || (index_left <= dest && dest <= index_right+1) {
+		return values
+	}
+	part := slices.Clone(values[index_left : index_right+1])
+	values = slices.Delete(values, index_left, index_right+1)
+	if dest > index_right {
+		dest -= len(part)
+	}
+	return slices.Insert(values, dest, part...)
+}
+
+func TestMoveRange(t *testing.T) {
+	r := rand.New(rand.NewPCG(49, 50))
+	model := make([]int, 50)
+	for i := range model {
+		model[i] = i
+	}
+	tr := New(model...)
+	var buf bytes.Buffer
+	tr.SetJournal(&buf)
+	for range 500 {
+		l, h, dest := r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2
+		tr.MoveRange(l, h, dest)
+		model = moved(model, l, h, dest)
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("treap after moves = %v, want %v", got, model)
+	}
+	checkShape(t, tr.root)
+	recovered, err := Recover(bytes.NewReader(buf.Bytes()))
+	if err != nil || !slices.Equal(recovered.Export(), model) {
+		t.Errorf("Recover() of the moves = %v, %v", recovered.Export(), err)
+	}
+}
+
+func TestMoveRangeMarks(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5)
+	before, after, end := tr.Mark(0), tr.Mark(4), tr.Mark(6)
+	tr.MoveRange(1, 2, 5)
+	if got := tr.Export(); !slices.Equal(got, []int{0, 3, 4, 1, 2, 5}) {
+		t.Fatalf("MoveRange(1, 2, 5) = %v", got)
+	}
+	if before.Index() != 0 || after.Index() != 2 || end.Index() != 6 {
+		t.Errorf("marks outside of the range moved to %d, %d and %d", before.Index(), after.Index(), end.Index())
+	}
+	tr.MoveRange(3, 4, 1)
+	if got := tr.Export(); !slices.Equal(got, []int{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("MoveRange(3, 4, 1) = %v", got)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	values := []int{0, 1, 2, 3, 4}
+	for k := -12; k <= 12; k++ {
+		n := len(values)
+		shift := (k%n + n) % n
+		want := append(slices.Clone(values[shift:]), values[:shift]...)
+		left, right := New(values...), New(values...)
+		left.RotateLeft(k)
+		right.RotateRight(-k)
+		if got := left.Export(); !slices.Equal(got, want) {
+			t.Errorf("RotateLeft(%d) = %v, want %v", k, got, want)
+		}
+		if got := right.Export(); !slices.Equal(got, want) {
+			t.Errorf("RotateRight(%d) = %v, want %v", -k, got, want)
+		}
+	}
+	empty := New()
+	empty.RotateLeft(3)
+	empty.RotateRight(3)
+	if empty.Size() != 0 {
+		t.Error("rotation of an empty treap inserted elements")
+	}
+}
+
+func TestInsertSlice(t *testing.T) {
+	r := rand.New(rand.NewPCG(61, 62))
+	var model []int
+	tr := New()
+	for step := range 300 {
+		values := make([]int, r.IntN(6))
+		for i := range values {
+			values[i] = step
+		}
+		index := r.IntN(len(model)+4) - 2
+		tr.InsertSlice(index, values...)
+		model = slices.Insert(model, max(0, min(index, len(model))), values...)
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("treap after insertions = %v, want %v", got, model)
+	}
+	checkShape(t, tr.root)
+	values := []int{1, 2, 3}
+	small := New(0)
+	small.InsertSlice(1, values...)
+	values[0] = 9
+	if got := small.Export(); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("InsertSlice() keeps the provided slice: %v", got)
+	}
+}
+
+func TestTryInsertSlice(t *testing.T) {
+	tr := New(1, 2)
+	tr.SetLimit(3)
+	if err := tr.TryInsertSlice(1, 5, 6); !errors.Is(err, ErrSizeLimit) || tr.Size() != 2 {
+		t.Errorf("TryInsertSlice() over the limit = %v, size %d", err, tr.Size())
+	}
+	if err := tr.TryInsertSlice(1, 5); err != nil || !slices.Equal(tr.Export(), []int{1, 5, 2}) {
+		t.Errorf("TryInsertSlice(1, 5) = %v, %v", tr.Export(), err)
+	}
+	var nilTreap *Treap
+	if err := nilTreap.TryInsertSlice(0, 1); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("TryInsertSlice() of a nil treap = %v, want ErrNilTreap", err)
+	}
+}
@@ -0,0 +1,73 @@
+package treap
+
+/*
+Enables strict mode for every treap in the package.
+Strict mode makes operations on invalid indexes panic instead of silently doing nothing.
+Intended to be turned on during development and testing.
+
+See `SetStrict()` to enable strict mode for a single treap.
+*/
+var Strict bool
+
+/*
+Enables or disables strict mode for the treap.
+In strict mode `Find()`, `Delete()` and `Cut()` panic on indexes out of range,
+panic value is an error wrapping `ErrIndexOutOfRange` (or `ErrEmptyTreap`, `ErrConsumedTreap`),
+its message contains the index and the size of the treap.
+
+Package-level `Strict` variable overrides disabled mode.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SetStrict(on bool) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.opts.strict = on
+}
+
+/*
+Reports whether the treap is in strict mode.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) IsStrict() bool {
+	if t == nil {
+		nilReceiver()
+		return Strict
+	}
+	return Strict || t.opts.strict
+}
+
+/*
+Panics in strict mode if index is out of range.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) checkIndex(index int) {
+	if !t.IsStrict() {
+		return
+	}
+	if err := t.validIndex(index); err != nil {
+		panic(err)
+	}
+}
+
+/*
+Panics in strict mode if range is empty or not fully inside the treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) checkRange(index_left int, index_right int) {
+	if !t.IsStrict() {
+		return
+	}
+	if err := t.validRange(index_left, index_right); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,88 @@
+package treap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Returns the error the function panicked with, or nil if it did not panic.
+func panicked(t *testing.T, fn func()) error {
+	t.Helper()
+	r := recovered(fn)
+	if r == nil {
+		return nil
+	}
+	err, ok := r.(error)
+	if !ok {
+		t.Fatalf("panic value %v is not an error", r)
+	}
+	return err
+}
+
+func TestStrictPanics(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.SetStrict(true)
+	if !tr.IsStrict() {
+		t.Fatal("IsStrict() = false after SetStrict(true)")
+	}
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"Find(3)", func() { tr.Find(3) }},
+		{"Find(-1)", func() { tr.Find(-1) }},
+		{"Delete(5)", func() { tr.Delete(5) }},
+		{"Cut(2, 1)", func() { tr.Cut(2, 1) }},
+		{"Cut(1, 3)", func() { tr.Cut(1, 3) }},
+	}
+	for _, tt := range tests {
+		err := panicked(t, tt.fn)
+		if !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("%s panicked with %v, want ErrIndexOutOfRange", tt.name, err)
+		} else if !strings.Contains(err.Error(), "size 3") {
+			t.Errorf("%s panic message %q does not contain the size", tt.name, err)
+		}
+	}
+	if err := panicked(t, func() { tr.Find(2) }); err != nil {
+		t.Errorf("Find(2) panicked with %v", err)
+	}
+	if tr.Size() != 3 {
+		t.Errorf("failed operations changed the treap, size is %d", tr.Size())
+	}
+}
+
+func TestStrictEmpty(t *testing.T) {
+	tr := New()
+	tr.SetStrict(true)
+	if err := panicked(t, func() { tr.Find(0) }); !errors.Is(err, ErrEmptyTreap) {
+		t.Errorf("Find(0) on empty treap panicked with %v, want ErrEmptyTreap", err)
+	}
+}
+
+func TestNotStrictDoesNothing(t *testing.T) {
+	tr := New(1, 2, 3)
+	if err := panicked(t, func() {
+		tr.Find(10)
+		tr.Delete(-1)
+		tr.Cut(5, 7)
+	}); err != nil {
+		t.Fatalf("operations out of range panicked with %v", err)
+	}
+	if got := tr.Find(10); got != 0 {
+		t.Errorf("Find(10) = %d, want 0", got)
+	}
+}
+
+func TestPackageStrict(t *testing.T) {
+	Strict = true
+	defer func() { Strict = false }()
+	tr := New(1)
+	tr.SetStrict(false)
+	if !tr.IsStrict() {
+		t.Error("package Strict does not override disabled mode")
+	}
+	if err := panicked(t, func() { tr.Delete(1) }); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Delete(1) panicked with %v, want ErrIndexOutOfRange", err)
+	}
+}
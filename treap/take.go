@@ -0,0 +1,205 @@
+package treap
+
+/*
+Deletes the first count elements and returns them as a new treap, so the treap itself stays usable.
+Returned treap uses configuration of the treap, same as the 2nd result of `Split()`.
+
+	if count <= 0: return empty treap
+	if count >= size: take all elements
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) TakeFront(count int) Treap {
+	if t == nil {
+		nilReceiver()
+		return Treap{}
+	}
+	count = min(count, size(t.root))
+	if count <= 0 {
+		return t.derive(nil)
+	}
+	return t.take(0, count-1)
+}
+
+/*
+Deletes the last count elements and returns them as a new treap, same as `TakeFront()`.
+
+	if count <= 0: return empty treap
+	if count >= size: take all elements
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) TakeBack(count int) Treap {
+	if t == nil {
+		nilReceiver()
+		return Treap{}
+	}
+	n := size(t.root)
+	count = min(count, n)
+	if count <= 0 {
+		return t.derive(nil)
+	}
+	return t.take(n-count, n-1)
+}
+
+/*
+Deletes the first count elements, same as `Cut()` of the prefix.
+
+	if count <= 0: do nothing
+	if count >= size: delete all elements
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) DropFront(count int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	count = min(count, size(t.root))
+	if count <= 0 {
+		return
+	}
+	v := t.enter()
+	t.cut(0, count-1)
+	t.leave(v)
+}
+
+/*
+Deletes the last count elements, same as `Cut()` of the suffix.
+
+	if count <= 0: do nothing
+	if count >= size: delete all elements
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) DropBack(count int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	n := size(t.root)
+	count = min(count, n)
+	if count <= 0 {
+		return
+	}
+	v := t.enter()
+	t.cut(n-count, n-1)
+	t.leave(v)
+}
+
+/*
+Keeps only the first n elements, the tail is deleted by a single split, same as `DropBack()`.
+
+	if n <= 0: delete all elements
+	if n >= size: do nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Truncate(n int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.DropBack(size(t.root) - max(n, 0))
+}
+
+/*
+Changes size of the treap to n, like resizing of a slice:
+the tail is deleted by `Truncate()`, or copies of the fill value are pushed to the back.
+
+	if n <= 0: delete all elements
+	if n == size: do nothing
+
+If not all fill values fit into the treap: do nothing.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, if the treap is truncated;
+  - Linear - time complexity is equal to amount of pushed values plus height of the treap, otherwise;
+*/
+func (t *Treap) Resize(n int, fill int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	count := n - size(t.root)
+	if count <= 0 {
+		t.Truncate(n)
+		return
+	}
+	values := make([]int, count)
+	for i := range values {
+		values[i] = fill
+	}
+	t.PushBack(values...)
+}
+
+/*
+Deletes the elements from index_left to index_right and returns them as a new treap, same as `TakeFront()`,
+for example to cut the range and paste it elsewhere by `Split()` and `Merge()`.
+
+	if index_left > index_right: return empty treap
+	if any index out of range: take only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Extract(index_left int, index_right int) Treap {
+	if t == nil {
+		nilReceiver()
+		return Treap{}
+	}
+	t.checkRange(index_left, index_right)
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size(t.root)-1)
+	if index_left > index_right {
+		return t.derive(nil)
+	}
+	return t.take(index_left, index_right)
+}
+
+/*
+Implementation of the `TakeFront()`, `TakeBack()` and `Extract()` methods.
+Range must be inside the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) take(index_left int, index_right int) Treap {
+	v := t.enter()
+	if !t.log(DeleteOp(index_left, index_right-index_left+1)) {
+		t.exit(v)
+		return t.derive(nil)
+	}
+	l, k := split(t.root, index_left-1)
+	m, r := split(k, index_right-index_left)
+	t.root = merge(l, r)
+	t.deleted(index_left, size(m))
+	taken := t.derive(m)
+	t.leave(v)
+	taken.leave(v)
+	return taken
+}
+
+/*
+Returns treap with provided root and configuration of the treap,
+that can be used independently from it, same as the 2nd result of `Split()`.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) derive(root *node) Treap {
+	d := Treap{root: root, opts: t.opts}
+	if t.opts.guard != nil {
+		d.opts.guard = &guard{}
+	}
+	d.opts.pool = t.opts.pool.fork()
+	d.opts.balance = t.opts.balance.fork()
+	return d
+}
@@ -0,0 +1,132 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTakeAndDrop(t *testing.T) {
+	tr := New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	front := tr.TakeFront(3)
+	back := tr.TakeBack(2)
+	if !slices.Equal(front.Export(), []int{0, 1, 2}) || !slices.Equal(back.Export(), []int{8, 9}) {
+		t.Fatalf("TakeFront(3) = %v, TakeBack(2) = %v", front.Export(), back.Export())
+	}
+	tr.DropFront(1)
+	tr.DropBack(1)
+	if got := tr.Export(); !slices.Equal(got, []int{4, 5, 6}) {
+		t.Fatalf("treap after takes and drops = %v, want [4 5 6]", got)
+	}
+	if none := tr.TakeFront(-1); none.Size() != 0 || tr.Size() != 3 {
+		t.Error("TakeFront(-1) takes elements")
+	}
+	tr.DropFront(0)
+	tr.DropBack(-2)
+	if all := tr.TakeBack(5); all.Size() != 3 || tr.Size() != 0 {
+		t.Errorf("TakeBack(5) takes %d elements, %d left", all.Size(), tr.Size())
+	}
+	tr.PushBack(1, 2)
+	front.PushBack(3)
+	if !slices.Equal(tr.Export(), []int{1, 2}) || !slices.Equal(front.Export(), []int{0, 1, 2, 3}) {
+		t.Error("treaps are not usable after takes")
+	}
+	checkShape(t, front.root)
+}
+
+func TestTakeKeepsConfiguration(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	tr.SetLimit(4)
+	tr.SetRecycling(true)
+	taken := tr.TakeFront(2)
+	if taken.Limit() != 4 {
+		t.Errorf("taken treap has limit %d, want 4", taken.Limit())
+	}
+	if taken.allocator() == tr.allocator() {
+		t.Error("taken treap shares the free list of the treap")
+	}
+	m := tr.Mark(2)
+	tr.TakeBack(1)
+	if m.Index() != 1 {
+		t.Errorf("mark after TakeBack() is %d, want 1", m.Index())
+	}
+}
+
+func TestTakeWithFailingJournal(t *testing.T) {
+	tr := New(1, 2, 3)
+	tr.SetJournal(failingWriter{})
+	if taken := tr.TakeFront(2); taken.Size() != 0 || tr.Size() != 3 {
+		t.Errorf("TakeFront() with a failing journal takes %d elements, %d left", taken.Size(), tr.Size())
+	}
+	tr.DropBack(1)
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("DropBack() with a failing journal leaves %v", got)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	r := rand.New(rand.NewPCG(45, 46))
+	model := make([]int, 60)
+	for i := range model {
+		model[i] = i
+	}
+	tr := New(model...)
+	for range 200 {
+		l, h := r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2
+		want := slices.Clone(clamped(model, l, h))
+		got := tr.Extract(l, h)
+		if !slices.Equal(got.Export(), want) {
+			t.Fatalf("Extract(%d, %d) = %v, want %v", l, h, got.Export(), want)
+		}
+		if len(want) > 0 {
+			model = slices.Delete(model, max(l, 0), max(l, 0)+len(want))
+		}
+		// Extracted range is pasted back to a random place.
+		at := r.IntN(len(model) + 1)
+		left, right := Split(&tr, at-1)
+		mid := Merge(&left, &got)
+		tr = Merge(&mid, &right)
+		model = slices.Insert(model, at, want...)
+		checkShape(t, tr.root)
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("treap after extractions = %v, want %v", got, model)
+	}
+	tr.SetStrict(true)
+	if err := panicked(t, func() { tr.Extract(3, 2) }); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Extract(3, 2) in strict mode panicked with %v, want ErrIndexOutOfRange", err)
+	}
+}
+
+func TestTruncateAndResize(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.Truncate(7)
+	tr.Truncate(3)
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("Truncate(3) = %v", got)
+	}
+	tr.Resize(6, 9)
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2, 3, 9, 9, 9}) {
+		t.Fatalf("Resize(6, 9) = %v", got)
+	}
+	tr.Resize(2, 9)
+	tr.Resize(2, 0)
+	if got := tr.Export(); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("Resize(2) = %v", got)
+	}
+	tr.SetLimit(4)
+	tr.Resize(5, 0)
+	if tr.Size() != 2 {
+		t.Errorf("Resize() over the limit changed the size to %d", tr.Size())
+	}
+	tr.Resize(-1, 0)
+	if tr.Size() != 0 {
+		t.Errorf("Resize(-1) keeps %d elements", tr.Size())
+	}
+	tr.PushBack(1, 2)
+	tr.Truncate(-5)
+	if tr.Size() != 0 {
+		t.Errorf("Truncate(-5) keeps %d elements", tr.Size())
+	}
+}
@@ -3,15 +3,32 @@
 This package's data structure uses [Implicit keys] variation.
 This allows to work as dynamic array with ability to split, merge, insert, delete, find in a logarithmic time.
 
+Sizes are stored as int, so on 64-bit platforms a treap may hold multi-billion elements.
+A maximum size may be configured with `SetLimit()`.
+Range sums, minimums, maximums, hashes and range updates need augmentations of every subtree,
+that are opt-in per treap, so a treap that does not use them keeps only sizes, see `SetAugmented()`.
+
 # Package is unsafe to be used in parallel goroutines.
 
+# Debug builds
+
+Build with `-tags treapdebug` to enable internal checks.
+Every root node is stamped with a generation on each mutation,
+so using a treap whose nodes were absorbed by `Merge()`, `Split()` or by its own copy
+panics with `ErrConsumedTreap` instead of silently corrupting data.
+After every mutating operation all invariants of the treap are checked,
+so corruption is reported by the operation that caused it.
+Release builds are not affected by these checks.
+
 [Treap]: https://en.wikipedia.org/wiki/Treap
 [Implicit keys]: https://en.wikipedia.org/wiki/Treap#Implicit_treap
 */
 package treap
 
 import (
-	rand "math/rand/v2"
+	"cmp"
+	"slices"
+	"sync/atomic"
 )
 
 /*
@@ -19,19 +36,91 @@ Internal struct that is the treap itself.
 Not used as a main type since cause problems with initialization.
 */
 type node struct {
+	// Generation of the owning treap, only in debug builds.
+	stamp
 	value    int
 	size     int
 	priority int
+	tiebreak uint64
 	lson     *node
 	rson     *node
+	// Augmentations of the subtree, nil if the treap is not augmented, see `SetAugmented()`.
+	aug  *augment
+	flip bool
+}
+
+/*
+Counter of all created nodes.
+Used to give every node unique tie-breaking word.
+*/
+var created atomic.Uint64
+
+/*
+Creates single node with random priority.
+Tie-breaking word is a mixed counter value,
+so it is unique and does not depend on the random generator state.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func newNode(value int) *node {
+	n := new(node)
+	initNode(n, value, randomPriority(), mix(created.Add(1)))
+	return n
+}
+
+/*
+Resets all fields of the node into a single node with provided priority and tie-breaking word,
+same as `newNode()` but without allocation.
+Every node of the package is initialized here, so new fields are set only in this place.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func initNode(n *node, value int, priority int, tiebreak uint64) {
+	*n = node{value: value, size: 1, priority: priority, tiebreak: tiebreak}
+}
+
+/*
+Bijective mixing function (SplitMix64 finalizer).
+Turns sequential counter values into well spread words.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
 }
 
 /*
-Recalculate node's size by checking all children's sizes.
+Reports whether node n1 must be higher in the treap than node n2.
+Equal priorities are resolved by tie-breaking words,
+so order of nodes is always strict and does not depend on the merge's argument order.
 
 # Time complexity:
   - Constant - requires constant amount of operations;
 */
+func outranks(n1 *node, n2 *node) bool {
+	if n1.priority != n2.priority {
+		return n1.priority > n2.priority
+	}
+	return n1.tiebreak > n2.tiebreak
+}
+
+/*
+Recalculate node's size by checking all children's sizes,
+and other augmentations if the node has them, see `resum()`.
+Pending changes of the node must be pushed before.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+  - Linear - if augmented node got sons that are not augmented, see `resum()`;
+*/
 func sync(n *node) {
 	if n == nil {
 		return
@@ -43,6 +132,50 @@ func sync(n *node) {
 	if n.rson != nil {
 		n.size += n.rson.size
 	}
+	if n.aug != nil {
+		resum(n)
+	}
+}
+
+/*
+Pushes pending changes of the node into its sons,
+so the sons can be read or relinked.
+Every descent into the sons must push the node first.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func push(n *node) {
+	if n == nil {
+		return
+	}
+	if n.flip {
+		reverse(n.lson)
+		reverse(n.rson)
+		n.flip = false
+	}
+	a := n.aug
+	if a == nil {
+		return
+	}
+	// Pending assignment was made before pending negation and addition, so it is pushed first.
+	if a.filled {
+		assign(n.lson, a.fill)
+		assign(n.rson, a.fill)
+		a.filled = false
+	}
+	if a.negated {
+		negate(n.lson)
+		negate(n.rson)
+		a.negated = false
+	}
+	if a.add != 0 {
+		increase(n.lson, a.add)
+		increase(n.rson, a.add)
+		a.add = 0
+	}
 }
 
 /*
@@ -58,11 +191,13 @@ func merge(n1 *node, n2 *node) *node {
 		return n1
 	}
 
-	if n1.priority > n2.priority {
+	if outranks(n1, n2) {
+		push(n1)
 		n1.rson = merge(n1.rson, n2)
 		sync(n1)
 		return n1
 	} else {
+		push(n2)
 		n2.lson = merge(n1, n2.lson)
 		sync(n2)
 		return n2
@@ -88,6 +223,7 @@ func split(n *node, index int) (l *node, r *node) {
 		return n, nil
 	}
 
+	push(n)
 	position := index
 	if n.lson != nil {
 		position -= n.lson.size
@@ -130,6 +266,7 @@ func export(values []int, position int, n *node) {
 	if n == nil {
 		return
 	}
+	push(n)
 	if n.lson != nil {
 		export(values, position, n.lson)
 		position += n.lson.size
@@ -139,11 +276,82 @@ func export(values []int, position int, n *node) {
 }
 
 /*
-Main type of a data structure that stores a single pointer.
-That pointer is pointing to the root node.
+Returns the node on the given index of the subtree, same as `descend()` without the path.
+
+	if index out of range: return nil
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func find(n *node, index int) *node {
+	n, _ = descend(n, index, nil, false)
+	return n
+}
+
+/*
+Returns the node on the given index of the subtree and the path with every visited node appended, if record is true.
+It is the only descent by index, so `find()`, `set()`, iterators, walkers and `lookup()` share it.
+Descent is iterative and does not allocate, while the path has enough capacity.
+
+	if index out of range: return nil and the path with the nodes visited before falling out
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func descend(n *node, index int, path []*node, record bool) (*node, []*node) {
+	for n != nil {
+		push(n)
+		if record {
+			path = append(path, n)
+		}
+		lsize := 0
+		if n.lson != nil {
+			lsize = n.lson.size
+		}
+		if index < lsize {
+			n = n.lson
+		} else if index > lsize {
+			index -= lsize + 1
+			n = n.rson
+		} else {
+			return n, path
+		}
+	}
+	return nil, path
+}
+
+/*
+Main type of a data structure that stores a pointer to the root node
+and configuration of the treap.
 */
 type Treap struct {
-	root *node
+	// Generation of the treap, only in debug builds.
+	stamp
+	root     *node
+	opts     options
+	consumed bool
+	// Layers notified about every edit, not copied by `Merge()` and `Split()`.
+	observers []observer
+	// Write-ahead journal, not copied by `Merge()` and `Split()`.
+	journal *journal
+	// Active logs started by `Record()`, not copied by `Merge()` and `Split()`.
+	logs []*Log
+	// Amount of changes, see `Version()`.
+	version uint64
+}
+
+/*
+Configuration of a treap.
+Copied into the results of `Merge()` and `Split()`.
+*/
+type options struct {
+	limit   int
+	strict  bool
+	guard   *guard
+	pool    *pool
+	balance *balance
+	evict   Policy
+	combine func(int, int) int
 }
 
 /*
@@ -151,30 +359,40 @@ Correctly initialize a Treap data structure.
 Insert all given values to the back by calling `PushBack()` method.
 
 # Time complexity:
-  - Loglinear - time complexity is equal to height of the treap multiplied by amount of provided values;
+  - Linear - time complexity is equal to amount of provided values;
 */
 func New(values ...int) Treap {
-	t := Treap{nil}
+	t := Treap{}
 	t.PushBack(values...)
 	return t
 }
 
 /*
 Merges 2 treaps. Returns resulted treap.
-Old treaps must not be used afterwards.
+Old treaps are left empty and marked as consumed, they must not be used afterwards.
+Resulted treap uses configuration of the 1st treap.
+Size limit is not checked, since no values are inserted.
 
 # Time complexity:
   - Logarithmic - time complexity is equal to height of the highest treap;
 */
-func Merge(t1 *Treap, t2 *Treap) Treap {
+func Merge(t1 *Treap, t2 *Treap) (t Treap) {
 	if t1 == nil && t2 == nil {
 		return Treap{}
 	} else if t1 == nil {
-		return Treap{t2.root}
-	} else if t2 == nil {
-		return Treap{t1.root}
+		t1, t2 = t2, nil
+	}
+	v1 := t1.enter()
+	t = Treap{root: t1.root, opts: t1.opts}
+	if t2 != nil {
+		v2 := t2.enter()
+		t.root = merge(t1.root, t2.root)
+		t.leave(v2)
 	}
-	return Treap{merge(t1.root, t2.root)}
+	t.leave(v1)
+	t1.consume()
+	t2.consume()
+	return t
 }
 
 /*
@@ -184,18 +402,84 @@ Returns 2 resulted treaps:
 	1st: treap index <= given index
 	2nd: treap index >  given index
 
-Old treap must not be used afterwards.
+Old treap is left empty and marked as consumed, it must not be used afterwards.
+Both resulted treaps use configuration of the old treap.
 
 # Time complexity:
   - Logarithmic - time complexity is equal to height of the highest treap;
 */
 func Split(t *Treap, index int) (tl Treap, tr Treap) {
 	if t != nil {
+		v := t.enter()
 		tl.root, tr.root = split(t.root, index)
+		tl.opts, tr.opts = t.opts, t.opts
+		// Resulted treaps are independent, so they may be used by different goroutines.
+		if t.opts.guard != nil {
+			tl.opts.guard, tr.opts.guard = &guard{}, &guard{}
+		}
+		tr.opts.pool = t.opts.pool.fork()
+		tr.opts.balance = t.opts.balance.fork()
+		tl.leave(v)
+		tr.leave(v)
+		t.consume()
 	}
 	return
 }
 
+/*
+Set maximum amount of elements the treap can hold.
+Inserting beyond the limit does nothing (`Try...()` methods return `ErrSizeLimit`),
+unless the treap evicts elements, see `NewBounded()`.
+
+	if limit <= 0: treap is unlimited
+
+Already stored elements are never deleted by this method.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SetLimit(limit int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	t.opts.limit = limit
+}
+
+/*
+Returns maximum amount of elements the treap can hold.
+
+	if treap is unlimited: return 0
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) Limit() int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	}
+	return t.opts.limit
+}
+
+/*
+Reports whether count more elements fit into the treap.
+Everything fits into the treap that evicts elements.
+Comparison is written so it can not overflow even for huge sizes.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) fits(count int) bool {
+	if t.opts.limit <= 0 || t.opts.evict != EvictNone {
+		return true
+	}
+	return count <= t.opts.limit-t.Size()
+}
+
 /*
 Insert value into provided index.
 Splits treap into 2 parts.
@@ -204,64 +488,184 @@ Merges with new node and return the resulted treap.
 In case index out range method calls:
 
 	if index <= 0: t.PushFront(value)
-	if index >= size-1: t.PushBack(value)
+	if index >= size: t.PushBack(value)
+
+Index size-1 inserts before the last element, earlier versions pushed to the back in that case.
+If treap is full: do nothing.
 
 # Time complexity:
   - Logarithmic - time complexity is equal to height of the highest treap;
 */
 func (t *Treap) Insert(index int, value int) {
 	if t == nil {
+		nilReceiver()
 		return
-	} else if t.root == nil {
-		t.root = &node{value: value, size: 1, priority: rand.Int(), lson: nil, rson: nil}
+	} else if !t.fits(1) {
+		return
+	}
+	v := t.enter()
+	t.insert(index, value)
+	t.leave(v)
+}
+
+/*
+Implementation of the `Insert()` method.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) insert(index int, value int) {
+	if t.root == nil {
+		if !t.log(InsertOp(0, value)) {
+			return
+		}
+		t.root = t.opts.pool.get(value)
+		t.inserted(0, 1)
 		return
 	}
 	if index <= 0 {
-		t.PushFront(value)
+		t.pushFront(value)
 		return
-	} else if index >= t.root.size-1 {
-		t.PushBack(value)
+	} else if index >= t.root.size {
+		t.pushBack(value)
+		return
+	} else if !t.log(InsertOp(index, value)) {
 		return
 	}
 	l, r := split(t.root, index-1)
-	l = merge(l, &node{value: value, size: 1, priority: rand.Int(), lson: nil, rson: nil})
+	l = merge(l, t.opts.pool.get(value))
 	t.root = merge(l, r)
+	t.inserted(index, 1)
+	t.evict()
 }
 
 /*
 Insert all provided values to the front of the treap.
+Values are inserted one after another, so the last provided value becomes the first element.
+Provided values are built into a treap in a linear time and merged with the treap once.
+
+If not all values fit into the treap: do nothing.
 
 # Time complexity:
-  - Logarithmic - time complexity is equal to height of the highest treap;
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
 */
 func (t *Treap) PushFront(values ...int) {
 	if t == nil {
+		nilReceiver()
+		return
+	} else if !t.fits(len(values)) {
 		return
 	}
-	var vroot *node
-	for _, value := range values {
-		n := &node{value: value, size: 1, priority: rand.Int(), lson: nil, rson: nil}
-		vroot = merge(n, vroot)
+	v := t.enter()
+	t.pushFront(values...)
+	t.leave(v)
+}
+
+/*
+Implementation of the `PushFront()` method.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
+*/
+func (t *Treap) pushFront(values ...int) {
+	// Every value is inserted to the front, so the last value becomes the first.
+	if t.logging() && !t.log(InsertOp(0, reversed(values)...)) {
+		return
 	}
-	t.root = merge(vroot, t.root)
+	t.root = merge(build(values, true, t.opts.pool), t.root)
+	t.inserted(0, len(values))
+	t.evict()
 }
 
 /*
 Insert all provided values to the back of the treap.
+Provided values are built into a treap in a linear time and merged with the treap once.
+
+If not all values fit into the treap: do nothing.
 
 # Time complexity:
-  - Logarithmic - time complexity is equal to height of the highest treap;
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
 */
 func (t *Treap) PushBack(values ...int) {
 	if t == nil {
+		nilReceiver()
 		return
+	} else if !t.fits(len(values)) {
+		return
+	}
+	v := t.enter()
+	t.pushBack(values...)
+	t.leave(v)
+}
+
+/*
+Implementation of the `PushBack()` method.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values plus height of the treap;
+*/
+func (t *Treap) pushBack(values ...int) {
+	index := size(t.root)
+	if !t.log(InsertOp(index, values...)) {
+		return
+	}
+	t.root = merge(t.root, build(values, false, t.opts.pool))
+	t.inserted(index, len(values))
+	t.evict()
+}
+
+/*
+Same as `Insert()`, but reports why the value was not inserted.
+
+	if t == nil: return ErrNilTreap
+	if treap was consumed: return ErrConsumedTreap
+	if treap is full: return ErrSizeLimit
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) TryInsert(index int, value int) error {
+	if err := t.validInsert(1); err != nil {
+		return err
 	}
-	var vroot *node
-	for _, value := range values {
-		n := &node{value: value, size: 1, priority: rand.Int(), lson: nil, rson: nil}
-		vroot = merge(vroot, n)
+	t.Insert(index, value)
+	return nil
+}
+
+/*
+Same as `PushFront()`, but reports why the values were not inserted.
+
+	if t == nil: return ErrNilTreap
+	if treap was consumed: return ErrConsumedTreap
+	if not all values fit into the treap: return ErrSizeLimit
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) TryPushFront(values ...int) error {
+	if err := t.validInsert(len(values)); err != nil {
+		return err
+	}
+	t.PushFront(values...)
+	return nil
+}
+
+/*
+Same as `PushBack()`, but reports why the values were not inserted.
+
+	if t == nil: return ErrNilTreap
+	if treap was consumed: return ErrConsumedTreap
+	if not all values fit into the treap: return ErrSizeLimit
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) TryPushBack(values ...int) error {
+	if err := t.validInsert(len(values)); err != nil {
+		return err
 	}
-	t.root = merge(t.root, vroot)
+	t.PushBack(values...)
+	return nil
 }
 
 /*
@@ -275,22 +679,71 @@ Some properties of the deletion range:
 	if index_left >= size: do nothing
 	if index_right < 0: do nothing
 
+In strict mode any index outside of the treap causes a panic.
+If recycling is enabled, deleted nodes are put into the free list.
+
 # Time complexity:
   - Logarithmic - time complexity is equal to height of the highest treap;
+  - Linear - if recycling is enabled, time complexity is equal to amount of deleted elements;
 */
 func (t *Treap) Cut(index_left int, index_right int) {
 	if t == nil {
+		nilReceiver()
 		return
-	} else if t.root == nil {
+	}
+	t.checkRange(index_left, index_right)
+	if t.root == nil {
 		return
 	} else if index_left > index_right {
 		return
 	} else if index_right < 0 || index_left >= t.root.size {
 		return
 	}
+	// Clamping prevents index_left-1 from overflowing on math.MinInt.
+	index_left = max(index_left, 0)
+	index_right = min(index_right, t.root.size-1)
+	v := t.enter()
+	t.cut(index_left, index_right)
+	t.leave(v)
+}
+
+/*
+Implementation of the `Cut()` and `Delete()` methods.
+Range must be inside the treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) cut(index_left int, index_right int) {
+	if !t.log(DeleteOp(index_left, index_right-index_left+1)) {
+		return
+	}
 	l, k := split(t.root, index_left-1)
-	_, r := split(k, index_right)
+	// Indexes of the middle part start from index_left.
+	m, r := split(k, index_right-index_left)
 	t.root = merge(l, r)
+	t.deleted(index_left, size(m))
+	t.opts.pool.discard(m)
+}
+
+/*
+Same as `Cut()`, but range must be fully inside the treap.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index_left > index_right or any index out of range: return ErrIndexOutOfRange
+
+Nothing is deleted if error is returned.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) TryCut(index_left int, index_right int) error {
+	if err := t.validRange(index_left, index_right); err != nil {
+		return err
+	}
+	t.Cut(index_left, index_right)
+	return nil
 }
 
 /*
@@ -301,21 +754,97 @@ and then merging 2 necessary parts togheter.
 	if index < 0 || index >= size: do nothing
 
 Method is replacement of a `Cut()` method but for 1 position to delete instead of range.
+In strict mode out of range index causes a panic.
 
 # Time complexity:
   - Logarithmic - time complexity is equal to height of the highest treap;
 */
 func (t *Treap) Delete(index int) {
 	if t == nil {
+		nilReceiver()
 		return
-	} else if t.root == nil {
+	}
+	t.checkIndex(index)
+	if t.root == nil {
 		return
 	} else if index < 0 || index >= t.root.size {
 		return
 	}
-	l, k := split(t.root, index-1)
-	_, r := split(k, index)
-	t.root = merge(l, r)
+	v := t.enter()
+	t.cut(index, index)
+	t.leave(v)
+}
+
+/*
+Same as `Delete()`, but reports why nothing was deleted.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) TryDelete(index int) error {
+	if err := t.validIndex(index); err != nil {
+		return err
+	}
+	t.Delete(index)
+	return nil
+}
+
+/*
+Deletes the first element and returns its value,
+so the treap can be used as a deque.
+
+	if treap is empty: return 0, false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) PopFront() (int, bool) {
+	return t.pop(0)
+}
+
+/*
+Deletes the last element and returns its value, same as `PopFront()`.
+
+	if treap is empty: return 0, false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) PopBack() (int, bool) {
+	if t == nil {
+		nilReceiver()
+		return 0, false
+	}
+	return t.pop(size(t.root) - 1)
+}
+
+/*
+Implementation of the `PopFront()` and `PopBack()` methods.
+Reports false if nothing was deleted, e.g. because the journal failed.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) pop(index int) (int, bool) {
+	if t == nil {
+		nilReceiver()
+		return 0, false
+	} else if t.root == nil {
+		return 0, false
+	}
+	v := t.enter()
+	value, n := find(t.root, index).value, t.root.size
+	t.cut(index, index)
+	deleted := size(t.root) < n
+	t.leave(v)
+	if !deleted {
+		return 0, false
+	}
+	return value, true
 }
 
 /*
@@ -326,6 +855,7 @@ Returns size of a treap.
 */
 func (t *Treap) Size() int {
 	if t == nil {
+		nilReceiver()
 		return 0
 	} else if t.root == nil {
 		return 0
@@ -338,37 +868,289 @@ Return the element on the given index.
 
 	if index out of range: return 0
 
+In strict mode out of range index causes a panic.
+
 # Time complexity:
   - Logarithmic - time complexity is equal to height of the highest treap;
 */
 func (t *Treap) Find(index int) int {
 	if t == nil {
+		nilReceiver()
 		return 0
-	} else if t.root == nil {
+	}
+	t.checkIndex(index)
+	if t.root == nil {
 		return 0
 	} else if index < 0 || index >= t.root.size {
 		return 0
 	}
-	for n := t.root; n != nil; {
-		position := index
-		lson := n.lson
-		var lsize int
-		if lson != nil {
-			lsize = lson.size
-			position -= lsize
-			index -= lsize
+	v := t.enter()
+	n, changed := t.lookup(index)
+	if changed {
+		t.leave(v)
+	} else {
+		t.exit(v)
+	}
+	return n.value
+}
+
+/*
+Same as `Find()`, but reports whether the index is inside of the treap,
+so stored 0 can be told apart from a missing element.
+Does not panic in strict mode.
+
+	if index out of range: return 0, false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Get(index int) (int, bool) {
+	if t == nil {
+		nilReceiver()
+		return 0, false
+	} else if index < 0 || index >= size(t.root) {
+		return 0, false
+	}
+	v := t.enter()
+	n, changed := t.lookup(index)
+	if changed {
+		t.leave(v)
+	} else {
+		t.exit(v)
+	}
+	return n.value, true
+}
+
+/*
+Returns elements on all given indexes, same as `Find()` for each of them.
+Indexes are sorted and answered by a single descent, that is split between the sons at every node,
+so paths shared by the indexes are walked only once.
+Result holds elements in order of the provided indexes, provided slice is not changed.
+Depth of the paths is not checked by balance monitoring.
+
+	if index out of range: its element is 0
+
+In strict mode out of range index causes a panic.
+
+# Time complexity:
+  - Loglinear - time complexity is equal to amount of indexes multiplied by height of the treap, but not more than size of the treap, plus sorting of the indexes;
+*/
+func (t *Treap) FindMany(indexes []int) []int {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	values := make([]int, len(indexes))
+	order := make([]int, 0, len(indexes))
+	for i, index := range indexes {
+		t.checkIndex(index)
+		if index >= 0 && index < size(t.root) {
+			order = append(order, i)
 		}
-		if position < 0 {
-			index += lsize
-			n = lson
-		} else if position > 0 {
-			index--
-			n = n.rson
-		} else {
-			return n.value
+	}
+	slices.SortFunc(order, func(i int, j int) int {
+		return cmp.Compare(indexes[i], indexes[j])
+	})
+	v := t.enter()
+	findMany(t.root, 0, indexes, order, values)
+	t.exit(v)
+	return values
+}
+
+/*
+Saves into values elements of the subtree for all queries, that are positions of their indexes.
+Queries must be sorted by indexes, which must be inside of the subtree, offset is index of its 1st element.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of visited nodes;
+*/
+func findMany(n *node, offset int, indexes []int, queries []int, values []int) {
+	for n != nil && len(queries) > 0 {
+		push(n)
+		index := offset + size(n.lson)
+		lo, _ := slices.BinarySearchFunc(queries, index, func(q int, index int) int {
+			return cmp.Compare(indexes[q], index)
+		})
+		hi := lo
+		for hi < len(queries) && indexes[queries[hi]] == index {
+			values[queries[hi]] = n.value
+			hi++
+		}
+		findMany(n.lson, offset, indexes, queries[:lo], values)
+		offset, queries = index+1, queries[hi:]
+		n = n.rson
+	}
+}
+
+/*
+Returns the first element by walking the left spine, without index arithmetic.
+
+	if treap is empty: return 0, false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Front() (int, bool) {
+	if t == nil {
+		nilReceiver()
+		return 0, false
+	} else if t.root == nil {
+		return 0, false
+	}
+	v := t.enter()
+	n := t.root
+	for push(n); n.lson != nil; push(n) {
+		n = n.lson
+	}
+	t.exit(v)
+	return n.value, true
+}
+
+/*
+Returns the last element by walking the right spine, same as `Front()`.
+
+	if treap is empty: return 0, false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Back() (int, bool) {
+	if t == nil {
+		nilReceiver()
+		return 0, false
+	} else if t.root == nil {
+		return 0, false
+	}
+	v := t.enter()
+	n := t.root
+	for push(n); n.rson != nil; push(n) {
+		n = n.rson
+	}
+	t.exit(v)
+	return n.value, true
+}
+
+/*
+Same as `Find()`, but reports why the element was not found.
+
+	if treap was consumed: return 0, ErrConsumedTreap
+	if treap is empty: return 0, ErrEmptyTreap
+	if index out of range: return 0, ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the highest treap;
+*/
+func (t *Treap) TryFind(index int) (int, error) {
+	if err := t.validIndex(index); err != nil {
+		return 0, err
+	}
+	return t.Find(index), nil
+}
+
+/*
+Overwrites the element on the given index.
+Unlike `Delete()` followed by `Insert()`, no split or merge is made,
+so the shape of the treap and positions of all elements are kept.
+
+	if index out of range: do nothing
+
+In strict mode out of range index causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Set(index int, value int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.checkIndex(index)
+	if t.root == nil {
+		return
+	} else if index < 0 || index >= t.root.size {
+		return
+	}
+	v := t.enter()
+	if t.logReplace(index, value) {
+		set(t.root, index, value)
+		t.updated(index, 1)
+	}
+	t.leave(v)
+}
+
+/*
+Same as `Set()`, but reports why the element was not changed.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) TrySet(index int, value int) error {
+	if err := t.validIndex(index); err != nil {
+		return err
+	}
+	t.Set(index, value)
+	return nil
+}
+
+/*
+Exchanges the elements on the given indexes, same as 2 calls of `Set()`,
+so the treap can back shuffling and sorting algorithms like a slice.
+
+	if index_i == index_j: do nothing
+	if any index out of range: do nothing
+
+In strict mode out of range index causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) Swap(index_i int, index_j int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.checkIndex(index_i)
+	t.checkIndex(index_j)
+	if t.root == nil || index_i == index_j {
+		return
+	} else if index_i < 0 || index_i >= t.root.size || index_j < 0 || index_j >= t.root.size {
+		return
+	}
+	v := t.enter()
+	a, b := find(t.root, index_i).value, find(t.root, index_j).value
+	if t.logReplace(index_i, b) {
+		set(t.root, index_i, b)
+		t.updated(index_i, 1)
+		if t.logReplace(index_j, a) {
+			set(t.root, index_j, a)
+			t.updated(index_j, 1)
 		}
 	}
-	return 0
+	t.leave(v)
+}
+
+/*
+Same as `Swap()`, but reports why the elements were not exchanged.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if any index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) TrySwap(index_i int, index_j int) error {
+	if err := t.validIndex(index_i); err != nil {
+		return err
+	} else if err := t.validIndex(index_j); err != nil {
+		return err
+	}
+	t.Swap(index_i, index_j)
+	return nil
 }
 
 /*
@@ -381,11 +1163,106 @@ This method is recommended if a lot of look up operations will be coming.
 */
 func (t *Treap) Export() []int {
 	if t == nil {
+		nilReceiver()
 		return nil
 	} else if t.root == nil {
 		return nil
 	}
+	v := t.enter()
 	values := make([]int, t.root.size)
 	export(values, 0, t.root)
+	t.exit(v)
+	return values
+}
+
+/*
+Appends all values of the treap to dst and returns the extended slice, same as `Export()`,
+so a buffer can be reused between exports.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) ExportAppend(dst []int) []int {
+	if t == nil {
+		nilReceiver()
+		return dst
+	} else if t.root == nil {
+		return dst
+	}
+	v := t.enter()
+	start := len(dst)
+	dst = slices.Grow(dst, t.root.size)[:start+t.root.size]
+	export(dst[start:], 0, t.root)
+	t.exit(v)
+	return dst
+}
+
+/*
+Returns all values of the treap from the last to the first, same as `Export()` followed by reversal,
+but in a single walk.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) ExportReverse() []int {
+	if t == nil {
+		nilReceiver()
+		return nil
+	} else if t.root == nil {
+		return nil
+	}
+	return t.ExportReverseAppend(make([]int, 0, t.root.size))
+}
+
+/*
+Appends all values of the treap from the last to the first to dst and returns the extended slice, same as `ExportReverse()`.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func (t *Treap) ExportReverseAppend(dst []int) []int {
+	if t == nil {
+		nilReceiver()
+		return dst
+	} else if t.root == nil {
+		return dst
+	}
+	v := t.enter()
+	dst = slices.Grow(dst, t.root.size)
+	var w walker
+	w.seek(t.root, t.root.size-1, true)
+	for n := w.next(); n != nil; n = w.next() {
+		dst = append(dst, n.value)
+	}
+	t.exit(v)
+	return dst
+}
+
+/*
+Returns values of the elements from index_left to index_right, same as `Export()` of the range.
+Range is walked in place, so only its values and 2 paths from the root are visited.
+
+	if index_left > index_right: return nil
+	if any index out of range: return only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the range plus height of the treap;
+*/
+func (t *Treap) ExportRange(index_left int, index_right int) []int {
+	if t == nil {
+		nilReceiver()
+		return nil
+	}
+	t.checkRange(index_left, index_right)
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size(t.root)-1)
+	if index_left > index_right {
+		return nil
+	}
+	v := t.enter()
+	values := collect(t.root, index_left, index_right+1)
+	t.exit(v)
 	return values
 }
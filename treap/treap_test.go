@@ -0,0 +1,437 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// Returns the value the function panicked with, or nil.
+func recovered(fn func()) (r any) {
+	defer func() { r = recover() }()
+	fn()
+	return nil
+}
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		index int
+		want  []int
+	}{
+		{-5, []int{9, 1, 2, 3}},
+		{0, []int{9, 1, 2, 3}},
+		{1, []int{1, 9, 2, 3}},
+		{2, []int{1, 2, 9, 3}},
+		{3, []int{1, 2, 3, 9}},
+		{7, []int{1, 2, 3, 9}},
+	}
+	for _, tt := range tests {
+		tr := New(1, 2, 3)
+		tr.Insert(tt.index, 9)
+		if got := tr.Export(); !slices.Equal(got, tt.want) {
+			t.Errorf("Insert(%d, 9) = %v, want %v", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestInsertEmpty(t *testing.T) {
+	for _, index := range []int{-1, 0, 1} {
+		tr := New()
+		tr.Insert(index, 7)
+		if got := tr.Export(); !slices.Equal(got, []int{7}) {
+			t.Errorf("Insert(%d, 7) into empty treap = %v", index, got)
+		}
+	}
+}
+
+func TestInsertModel(t *testing.T) {
+	tr := New()
+	var model []int
+	for i := range 500 {
+		index := (i * 7919) % (len(model) + 2)
+		tr.Insert(index, i)
+		model = slices.Insert(model, min(index, len(model)), i)
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("Export() = %v, want %v", got, model)
+	}
+}
+
+func TestTryInsert(t *testing.T) {
+	var nilTreap *Treap
+	if err := nilTreap.TryInsert(0, 1); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("TryInsert() on nil treap = %v, want ErrNilTreap", err)
+	}
+	if err := nilTreap.TryPushBack(1); !errors.Is(err, ErrNilTreap) {
+		t.Errorf("TryPushBack() on nil treap = %v, want ErrNilTreap", err)
+	}
+
+	tr := New(1, 2)
+	tr.SetLimit(3)
+	if err := tr.TryInsert(1, 5); err != nil {
+		t.Fatalf("TryInsert() = %v, want nil", err)
+	}
+	if err := tr.TryInsert(1, 6); !errors.Is(err, ErrSizeLimit) {
+		t.Errorf("TryInsert() into full treap = %v, want ErrSizeLimit", err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{1, 5, 2}) {
+		t.Errorf("Export() = %v, want [1 5 2]", got)
+	}
+
+	a, b := New(1), New(2)
+	Merge(&a, &b)
+	if err := a.TryInsert(0, 1); !errors.Is(err, ErrConsumedTreap) {
+		t.Errorf("TryInsert() into consumed treap = %v, want ErrConsumedTreap", err)
+	}
+}
+
+func TestInsertGenericAndPaged(t *testing.T) {
+	g := NewGeneric("a", "b", "c")
+	g.Insert(2, "x")
+	if got := g.Export(); !slices.Equal(got, []string{"a", "b", "x", "c"}) {
+		t.Errorf("Generic Insert(2) = %v", got)
+	}
+
+	base := New(1, 2, 3)
+	p := NewPaged(base.Freeze())
+	p.Insert(2, 9)
+	if got := p.Export(); !slices.Equal(got, []int{1, 2, 9, 3}) {
+		t.Errorf("Paged Insert(2) = %v", got)
+	}
+}
+
+func TestFindDoesNotAllocate(t *testing.T) {
+	tr := New(make([]int, 1000)...)
+	tr.RangeAdd(0, 999, 1)
+	index := 0
+	allocs := testing.AllocsPerRun(1000, func() {
+		tr.Find(index)
+		tr.Get(index)
+		index = (index + 397) % 1000
+	})
+	if allocs != 0 {
+		t.Errorf("Find() and Get() allocate %v times per call", allocs)
+	}
+}
+
+func BenchmarkFind(b *testing.B) {
+	const n = 1 << 16
+	tr := New(make([]int, n)...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		tr.Find(i * 7919 % n)
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	const n = 1 << 16
+	tr := New(make([]int, n)...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		tr.Set(i*7919%n, i)
+	}
+}
+
+func BenchmarkIteratorSeek(b *testing.B) {
+	const n = 1 << 16
+	tr := New(make([]int, n)...)
+	it := tr.Iterator(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		it.Seek(i * 7919 % n)
+	}
+}
+
+func TestIteratorSeekDoesNotAllocate(t *testing.T) {
+	tr := New(make([]int, 1000)...)
+	it := tr.Iterator(0)
+	index := 0
+	allocs := testing.AllocsPerRun(1000, func() {
+		it.Seek(index)
+		it.Next()
+		index = (index + 397) % 1000
+	})
+	if allocs != 0 {
+		t.Errorf("Seek() allocates %v times per call", allocs)
+	}
+}
+
+func BenchmarkInsert(b *testing.B) {
+	b.ReportAllocs()
+	tr := New()
+	for i := range b.N {
+		tr.Insert(i*7919%(i+1), i)
+	}
+}
+
+func TestEqualPriorities(t *testing.T) {
+	values := make([]int, 200)
+	for i := range values {
+		values[i] = i
+	}
+	tr := NewWithPriorities(values, make([]int, len(values)))
+	checkShape(t, tr.root)
+	root := tr.root
+	// Shape does not depend on the order of splits and merges.
+	for _, index := range []int{0, 57, 120, 199} {
+		l, r := Split(&tr, index)
+		tr = Merge(&l, &r)
+		checkShape(t, tr.root)
+		if tr.root != root {
+			t.Fatalf("root changed after split at %d and merge", index)
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, values) {
+		t.Fatalf("Export() = %v", got)
+	}
+}
+
+func TestOutranksIsStrict(t *testing.T) {
+	nodes := []*node{newNode(0), newNode(0), newNode(0)}
+	for _, n := range nodes {
+		n.priority = 7
+	}
+	for _, n1 := range nodes {
+		for _, n2 := range nodes {
+			if n1 != n2 && outranks(n1, n2) == outranks(n2, n1) {
+				t.Fatal("equal priorities are not ordered by tie-breaking words")
+			}
+		}
+	}
+}
+
+func TestSetDoesNotAllocate(t *testing.T) {
+	tr := New(make([]int, 100)...)
+	index := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		tr.Set(index, index)
+		index = (index + 37) % 100
+	})
+	if allocs != 0 {
+		t.Errorf("Set() allocates %v times per call", allocs)
+	}
+}
+
+func TestSet(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.ReverseRange(1, 3)
+	tr.RangeSum(0, 4)
+	tr.Set(1, 10)
+	tr.Set(-1, 7)
+	tr.Set(5, 7)
+	if got := tr.Export(); !slices.Equal(got, []int{1, 10, 3, 2, 5}) {
+		t.Errorf("Export() = %v, want [1 10 3 2 5]", got)
+	}
+	if got := tr.RangeSum(0, 4); got != 21 {
+		t.Errorf("RangeSum() after Set() = %d, want 21", got)
+	}
+	checkShape(t, tr.root)
+}
+
+func TestTrySet(t *testing.T) {
+	tr := New(1, 2)
+	if err := tr.TrySet(1, 5); err != nil || tr.Find(1) != 5 {
+		t.Errorf("TrySet(1, 5) = %v, value %d", err, tr.Find(1))
+	}
+	for _, index := range []int{-1, 2} {
+		if err := tr.TrySet(index, 5); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Errorf("TrySet(%d, 5) = %v, want ErrIndexOutOfRange", index, err)
+		}
+	}
+	var nilTreap *Treap
+	if err := nilTreap.TrySet(0, 1); !errors.Is(err, ErrEmptyTreap) {
+		t.Errorf("TrySet() on nil treap = %v, want ErrEmptyTreap", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	tr := New(4, 5, 6)
+	tr.ReverseRange(0, 2)
+	for index, want := range []int{6, 5, 4} {
+		if value, ok := tr.Get(index); !ok || value != want {
+			t.Errorf("Get(%d) = %d, %v, want %d, true", index, value, ok, want)
+		}
+	}
+	for _, index := range []int{-1, 3} {
+		if value, ok := tr.Get(index); ok || value != 0 {
+			t.Errorf("Get(%d) = %d, %v, want 0, false", index, value, ok)
+		}
+	}
+	var nilTreap *Treap
+	if _, ok := nilTreap.Get(0); ok {
+		t.Error("Get() on nil treap reports an element")
+	}
+}
+
+func TestPop(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	tr.ReverseRange(0, 3)
+	var got []int
+	for {
+		front, ok := tr.PopFront()
+		if !ok {
+			break
+		}
+		back, _ := tr.PopBack()
+		got = append(got, front, back)
+	}
+	if !slices.Equal(got, []int{4, 1, 3, 2}) {
+		t.Errorf("popped values = %v, want [4 1 3 2]", got)
+	}
+	if value, ok := tr.PopBack(); ok || value != 0 {
+		t.Errorf("PopBack() of empty treap = %d, %v", value, ok)
+	}
+
+	journaled := New(1, 2)
+	journaled.SetJournal(failingWriter{})
+	if value, ok := journaled.PopFront(); ok || value != 0 || journaled.Size() != 2 {
+		t.Errorf("PopFront() with failed journal = %d, %v, size %d", value, ok, journaled.Size())
+	}
+}
+
+func TestFrontAndBack(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.ReverseRange(0, 4)
+	tr.RangeAdd(0, 4, 10)
+	if front, ok := tr.Front(); !ok || front != 15 {
+		t.Errorf("Front() = %d, %v, want 15, true", front, ok)
+	}
+	if back, ok := tr.Back(); !ok || back != 11 {
+		t.Errorf("Back() = %d, %v, want 11, true", back, ok)
+	}
+	empty := New()
+	if _, ok := empty.Front(); ok {
+		t.Error("Front() of empty treap reports an element")
+	}
+	if _, ok := empty.Back(); ok {
+		t.Error("Back() of empty treap reports an element")
+	}
+}
+
+func TestFindMany(t *testing.T) {
+	tr := New(10, 11, 12, 13, 14, 15)
+	tr.ReverseRange(0, 5)
+	indexes := []int{4, -1, 0, 4, 9, 2}
+	if got, want := tr.FindMany(indexes), []int{11, 0, 15, 11, 0, 13}; !slices.Equal(got, want) {
+		t.Errorf("FindMany(%v) = %v, want %v", indexes, got, want)
+	}
+	if !slices.Equal(indexes, []int{4, -1, 0, 4, 9, 2}) {
+		t.Errorf("FindMany() changed the indexes to %v", indexes)
+	}
+	if got := tr.FindMany(nil); len(got) != 0 {
+		t.Errorf("FindMany(nil) = %v", got)
+	}
+	r := rand.New(rand.NewPCG(43, 44))
+	values := make([]int, 300)
+	for i := range values {
+		values[i] = r.IntN(1000)
+	}
+	large := New(values...)
+	for range 100 {
+		indexes := make([]int, r.IntN(40))
+		want := make([]int, len(indexes))
+		for i := range indexes {
+			indexes[i] = r.IntN(len(values))
+			want[i] = values[indexes[i]]
+		}
+		if got := large.FindMany(indexes); !slices.Equal(got, want) {
+			t.Fatalf("FindMany(%v) = %v, want %v", indexes, got, want)
+		}
+	}
+	tr.SetStrict(true)
+	if err := panicked(t, func() { tr.FindMany([]int{0, 6}) }); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("FindMany() in strict mode panicked with %v, want ErrIndexOutOfRange", err)
+	}
+}
+
+func TestExportRange(t *testing.T) {
+	values := make([]int, 40)
+	for i := range values {
+		values[i] = i
+	}
+	tr := New(values...)
+	tr.ReverseRange(5, 30)
+	slices.Reverse(values[5:31])
+	for l := -2; l <= len(values)+1; l++ {
+		for h := l - 1; h <= len(values)+1; h++ {
+			if got, want := tr.ExportRange(l, h), clamped(values, l, h); !slices.Equal(got, want) || (want == nil) != (got == nil) {
+				t.Fatalf("ExportRange(%d, %d) = %v, want %v", l, h, got, want)
+			}
+		}
+	}
+	tr.SetStrict(true)
+	if err := panicked(t, func() { tr.ExportRange(0, len(values)) }); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("ExportRange() in strict mode panicked with %v, want ErrIndexOutOfRange", err)
+	}
+}
+
+func TestSwap(t *testing.T) {
+	r := rand.New(rand.NewPCG(47, 48))
+	model := make([]int, 80)
+	for i := range model {
+		model[i] = i
+	}
+	tr := New(model...)
+	tr.SetAugmented(true)
+	for range 500 {
+		i, j := r.IntN(len(model)+2)-1, r.IntN(len(model)+2)-1
+		tr.Swap(i, j)
+		if i >= 0 && i < len(model) && j >= 0 && j < len(model) {
+			model[i], model[j] = model[j], model[i]
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("treap after swaps = %v, want %v", got, model)
+	}
+	if fresh := New(model...); tr.Hash() != fresh.Hash() || tr.RangeMin(0, 9) != slices.Min(model[:10]) {
+		t.Error("augmentations are not kept by Swap()")
+	}
+}
+
+func TestTrySwap(t *testing.T) {
+	tr := New(1, 2, 3)
+	if err := tr.TrySwap(0, 2); err != nil || !slices.Equal(tr.Export(), []int{3, 2, 1}) {
+		t.Errorf("TrySwap(0, 2) = %v, %v", tr.Export(), err)
+	}
+	if err := tr.TrySwap(1, 3); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TrySwap(1, 3) = %v, want ErrIndexOutOfRange", err)
+	}
+	if err := tr.TrySwap(-1, 1); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TrySwap(-1, 1) = %v, want ErrIndexOutOfRange", err)
+	}
+	empty := New()
+	if err := empty.TrySwap(0, 0); !errors.Is(err, ErrEmptyTreap) {
+		t.Errorf("TrySwap() of an empty treap = %v, want ErrEmptyTreap", err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{3, 2, 1}) {
+		t.Errorf("failed TrySwap() changed the treap to %v", got)
+	}
+}
+
+func TestExportReverseAndAppend(t *testing.T) {
+	tr := New(1, 2, 3, 4, 5)
+	tr.ReverseRange(0, 2)
+	if got := tr.ExportReverse(); !slices.Equal(got, []int{5, 4, 1, 2, 3}) {
+		t.Errorf("ExportReverse() = %v", got)
+	}
+	buf := make([]int, 1, 16)
+	buf = tr.ExportAppend(buf)
+	buf = tr.ExportReverseAppend(buf)
+	if want := []int{0, 3, 2, 1, 4, 5, 5, 4, 1, 2, 3}; !slices.Equal(buf, want) {
+		t.Errorf("ExportAppend() and ExportReverseAppend() = %v, want %v", buf, want)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		buf = tr.ExportAppend(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("ExportAppend() into a big enough buffer allocates %v times", allocs)
+	}
+	empty := New()
+	if empty.ExportReverse() != nil || len(empty.ExportAppend(buf[:2])) != 2 || len(empty.ExportReverseAppend(nil)) != 0 {
+		t.Error("export of an empty treap is not empty")
+	}
+}
@@ -0,0 +1,227 @@
+package treap
+
+/*
+Adds delta to every element from index_left to index_right.
+Range is cut out and only its root is changed, sons get pending addition,
+that is pushed down when they are reached by a following operation.
+Sums, minimums, maximums and hashes of the range are updated at once.
+
+	if index_left > index_right: do nothing
+	if any index out of range: change only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus the length of the range if the journal or any log is active;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) RangeAdd(index_left int, index_right int, delta int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.checkRange(index_left, index_right)
+	if t.root == nil {
+		return
+	}
+	index_left = max(index_left, 0)
+	index_right = min(index_right, t.root.size-1)
+	if index_left > index_right || delta == 0 {
+		return
+	}
+	v := t.enter()
+	if t.logAdd(index_left, index_right, delta) {
+		t.augment()
+		l, k := split(t.root, index_left-1)
+		m, r := split(k, index_right-index_left)
+		increase(m, delta)
+		t.root = merge(merge(l, m), r)
+		t.updated(index_left, index_right-index_left+1)
+	}
+	t.leave(v)
+}
+
+/*
+Same as `RangeAdd()`, but range must be fully inside the treap.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index_left > index_right or any index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) TryRangeAdd(index_left int, index_right int, delta int) error {
+	if err := t.validRange(index_left, index_right); err != nil {
+		return err
+	}
+	t.RangeAdd(index_left, index_right, delta)
+	return nil
+}
+
+/*
+Overwrites every element from index_left to index_right with the value.
+Range is cut out and only its root is changed, sons get pending assignment,
+that is pushed down when they are reached by a following operation.
+Assignment replaces pending additions of the range, later additions are applied after it.
+
+	if index_left > index_right: do nothing
+	if any index out of range: change only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus the length of the range if the journal or any log is active;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) RangeAssign(index_left int, index_right int, value int) {
+	if t == nil {
+		nilReceiver()
+		return
+	}
+	t.checkRange(index_left, index_right)
+	if t.root == nil {
+		return
+	}
+	index_left = max(index_left, 0)
+	index_right = min(index_right, t.root.size-1)
+	if index_left > index_right {
+		return
+	}
+	v := t.enter()
+	if t.logAssign(index_left, index_right, value) {
+		t.augment()
+		l, k := split(t.root, index_left-1)
+		m, r := split(k, index_right-index_left)
+		assign(m, value)
+		t.root = merge(merge(l, m), r)
+		t.updated(index_left, index_right-index_left+1)
+	}
+	t.leave(v)
+}
+
+/*
+Overwrites every element of the treap with the value, same as `RangeAssign()` of the whole treap.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap, plus the size of the treap if the journal or any log is active;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) Fill(value int) {
+	if t == nil {
+		nilReceiver()
+		return
+	} else if t.root == nil {
+		return
+	}
+	t.RangeAssign(0, t.root.size-1, value)
+}
+
+/*
+Same as `RangeAssign()`, but range must be fully inside the treap.
+
+	if treap was consumed: return ErrConsumedTreap
+	if treap is empty: return ErrEmptyTreap
+	if index_left > index_right or any index out of range: return ErrIndexOutOfRange
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) TryRangeAssign(index_left int, index_right int, value int) error {
+	if err := t.validRange(index_left, index_right); err != nil {
+		return err
+	}
+	t.RangeAssign(index_left, index_right, value)
+	return nil
+}
+
+/*
+Writes addition to the elements from index_left to index_right as their replacement, same as `log()`.
+Range must be inside the treap.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the range, if the journal or any log is active;
+  - Constant - otherwise;
+*/
+func (t *Treap) logAdd(index_left int, index_right int, delta int) bool {
+	if !t.logging() {
+		return true
+	}
+	values := collect(t.root, index_left, index_right+1)
+	for i := range values {
+		values[i] += delta
+	}
+	return t.logReplace(index_left, values...)
+}
+
+/*
+Writes assignment to the elements from index_left to index_right as their replacement, same as `log()`.
+Range must be inside the treap.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the range, if the journal or any log is active;
+  - Constant - otherwise;
+*/
+func (t *Treap) logAssign(index_left int, index_right int, value int) bool {
+	if !t.logging() {
+		return true
+	}
+	values := make([]int, index_right-index_left+1)
+	for i := range values {
+		values[i] = value
+	}
+	return t.logReplace(index_left, values...)
+}
+
+/*
+Adds delta to all values of the subtree: the node and its augmentations are changed at once,
+addition to the sons is left pending, see `push()`.
+Subtree is augmented before, if it is not yet.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Constant - if the subtree is augmented;
+*/
+func increase(n *node, delta int) {
+	if n == nil {
+		return
+	}
+	augmentAll(n)
+	a := n.aug
+	n.value += delta
+	a.sum += delta * n.size
+	a.min += delta
+	a.max += delta
+	a.hash = hadd(a.hash, hmul(hashOf(delta), a.geo))
+	a.rhash = hadd(a.rhash, hmul(hashOf(delta), a.geo))
+	a.add += delta
+	a.folded = false
+}
+
+/*
+Overwrites all values of the subtree: the node and its augmentations are changed at once,
+assignment to the sons is left pending and replaces their pending negation and addition, see `push()`.
+Subtree is augmented before, if it is not yet.
+
+	if n == nil: do nothing
+
+# Time complexity:
+  - Constant - if the subtree is augmented;
+*/
+func assign(n *node, value int) {
+	if n == nil {
+		return
+	}
+	augmentAll(n)
+	a := n.aug
+	n.value = value
+	a.sum = value * n.size
+	a.min, a.max = value, value
+	a.hash = hmul(leafHash(value), a.geo)
+	a.rhash = a.hash
+	a.fill, a.filled = value, true
+	a.folded = false
+	a.negated = false
+	a.add = 0
+}
@@ -0,0 +1,131 @@
+package treap
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestRangeAdd(t *testing.T) {
+	r := rand.New(rand.NewPCG(33, 34))
+	model := make([]int, 100)
+	tr := New(model...)
+	for step := range 2000 {
+		l, h := r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2
+		delta := r.IntN(21) - 10
+		tr.RangeAdd(l, h, delta)
+		for i := range clamped(model, l, h) {
+			model[max(l, 0)+i] += delta
+		}
+		if step%3 == 0 {
+			l := r.IntN(len(model))
+			h := l + r.IntN(len(model)-l)
+			tr.ReverseRange(l, h)
+			slices.Reverse(model[l : h+1])
+		}
+		l, h = r.IntN(len(model)), r.IntN(len(model))
+		want := 0
+		for _, value := range clamped(model, l, h) {
+			want += value
+		}
+		if got := tr.RangeSum(l, h); got != want {
+			t.Fatalf("step %d: RangeSum(%d, %d) = %d, want %d", step, l, h, got, want)
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("Export() = %v, want %v", got, model)
+	}
+	if fresh := New(model...); tr.Hash() != fresh.Hash() {
+		t.Error("hash is not kept by RangeAdd()")
+	}
+}
+
+func TestTryRangeAdd(t *testing.T) {
+	tr := New(1, 2, 3)
+	if err := tr.TryRangeAdd(1, 2, 5); err != nil || !slices.Equal(tr.Export(), []int{1, 7, 8}) {
+		t.Errorf("TryRangeAdd(1, 2, 5) = %v, %v", tr.Export(), err)
+	}
+	if err := tr.TryRangeAdd(1, 3, 5); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TryRangeAdd(1, 3, 5) = %v, want ErrIndexOutOfRange", err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{1, 7, 8}) {
+		t.Errorf("failed TryRangeAdd() changed the treap to %v", got)
+	}
+}
+
+func TestRangeAssign(t *testing.T) {
+	r := rand.New(rand.NewPCG(35, 36))
+	model := make([]int, 100)
+	tr := New(model...)
+	for step := range 2000 {
+		l, h := r.IntN(len(model)+4)-2, r.IntN(len(model)+4)-2
+		value := r.IntN(21) - 10
+		if r.IntN(2) == 0 {
+			tr.RangeAssign(l, h, value)
+			for i := range clamped(model, l, h) {
+				model[max(l, 0)+i] = value
+			}
+		} else {
+			tr.RangeAdd(l, h, value)
+			for i := range clamped(model, l, h) {
+				model[max(l, 0)+i] += value
+			}
+		}
+		if step%3 == 0 {
+			l := r.IntN(len(model))
+			h := l + r.IntN(len(model)-l)
+			tr.ReverseRange(l, h)
+			slices.Reverse(model[l : h+1])
+		}
+		l, h = r.IntN(len(model)), r.IntN(len(model))
+		if part := clamped(model, l, h); len(part) > 0 {
+			if got, want := tr.RangeMin(l, h), slices.Min(part); got != want {
+				t.Fatalf("step %d: RangeMin(%d, %d) = %d, want %d", step, l, h, got, want)
+			}
+			if got, want := tr.RangeMax(l, h), slices.Max(part); got != want {
+				t.Fatalf("step %d: RangeMax(%d, %d) = %d, want %d", step, l, h, got, want)
+			}
+		}
+	}
+	if got := tr.Export(); !slices.Equal(got, model) {
+		t.Fatalf("Export() = %v, want %v", got, model)
+	}
+	if fresh := New(model...); tr.Hash() != fresh.Hash() {
+		t.Error("hash is not kept by RangeAssign()")
+	}
+}
+
+func TestTryRangeAssign(t *testing.T) {
+	tr := New(1, 2, 3)
+	if err := tr.TryRangeAssign(0, 1, 5); err != nil || !slices.Equal(tr.Export(), []int{5, 5, 3}) {
+		t.Errorf("TryRangeAssign(0, 1, 5) = %v, %v", tr.Export(), err)
+	}
+	if err := tr.TryRangeAssign(2, 1, 0); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("TryRangeAssign(2, 1, 0) = %v, want ErrIndexOutOfRange", err)
+	}
+	empty := New()
+	if err := empty.TryRangeAssign(0, 0, 0); !errors.Is(err, ErrEmptyTreap) {
+		t.Errorf("TryRangeAssign() of an empty treap = %v, want ErrEmptyTreap", err)
+	}
+	if got := tr.Export(); !slices.Equal(got, []int{5, 5, 3}) {
+		t.Errorf("failed TryRangeAssign() changed the treap to %v", got)
+	}
+}
+
+func TestFill(t *testing.T) {
+	tr := New(1, 2, 3, 4)
+	tr.RangeAdd(1, 2, 10)
+	tr.Fill(5)
+	if got := tr.Export(); !slices.Equal(got, []int{5, 5, 5, 5}) {
+		t.Errorf("Fill(5) = %v", got)
+	}
+	if tr.RangeSum(0, 3) != 20 || tr.RangeMax(0, 3) != 5 {
+		t.Errorf("Fill(5) gives sum %d and max %d", tr.RangeSum(0, 3), tr.RangeMax(0, 3))
+	}
+	empty := New()
+	empty.Fill(5)
+	if empty.Size() != 0 {
+		t.Error("Fill() of an empty treap inserted elements")
+	}
+}
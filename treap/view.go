@@ -0,0 +1,160 @@
+package treap
+
+import "iter"
+
+/*
+Read-only window of the treap, whose index 0 is mapped to the 1st index of the window,
+for example a paragraph of a text or a time slice of a series.
+View does not copy anything, all reads are made on the treap itself.
+View keeps indexes of the treap, not elements, so after the treap is changed it covers the same indexes,
+and indexes beyond the end of the treap are not part of it.
+*/
+type View struct {
+	t      *Treap
+	offset int
+	length int
+}
+
+/*
+Returns view of the elements from index_left to index_right.
+
+	if index_left > index_right: return empty view
+	if any index out of range: view covers only existing elements of the range
+
+In strict mode empty or out of range range causes a panic.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (t *Treap) SubView(index_left int, index_right int) View {
+	if t == nil {
+		nilReceiver()
+		return View{}
+	}
+	t.checkRange(index_left, index_right)
+	index_left = max(index_left, 0)
+	index_right = min(index_right, size(t.root)-1)
+	return View{t: t, offset: index_left, length: max(0, index_right-index_left+1)}
+}
+
+/*
+Returns view of the elements of the view from index_left to index_right, same as `SubView()` of the treap.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (w View) SubView(index_left int, index_right int) View {
+	index_left = max(index_left, 0)
+	index_right = min(index_right, w.Len()-1)
+	return View{t: w.t, offset: w.offset + index_left, length: max(0, index_right-index_left+1)}
+}
+
+/*
+Returns amount of elements in the view.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (w View) Len() int {
+	if w.t == nil {
+		return 0
+	}
+	return max(0, min(w.length, size(w.t.root)-w.offset))
+}
+
+/*
+Returns element on the given index of the view and whether it exists, same as `Get()` of the treap.
+
+	if index out of range: return 0, false
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (w View) Get(index int) (int, bool) {
+	if index < 0 || index >= w.Len() {
+		return 0, false
+	}
+	return w.t.Get(w.offset + index)
+}
+
+/*
+Returns all values of the view.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the view plus height of the treap;
+*/
+func (w View) Export() []int {
+	n := w.Len()
+	if n == 0 {
+		return nil
+	}
+	return w.t.ExportRange(w.offset, w.offset+n-1)
+}
+
+/*
+Returns sum of all elements of the view, same as `RangeSum()`.
+
+	if view is empty: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (w View) Sum() int {
+	n := w.Len()
+	if n == 0 {
+		return 0
+	}
+	return w.t.RangeSum(w.offset, w.offset+n-1)
+}
+
+/*
+Returns the smallest element of the view, same as `RangeMin()`.
+
+	if view is empty: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (w View) Min() int {
+	n := w.Len()
+	if n == 0 {
+		return 0
+	}
+	return w.t.RangeMin(w.offset, w.offset+n-1)
+}
+
+/*
+Returns the biggest element of the view, same as `RangeMax()`.
+
+	if view is empty: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (w View) Max() int {
+	n := w.Len()
+	if n == 0 {
+		return 0
+	}
+	return w.t.RangeMax(w.offset, w.offset+n-1)
+}
+
+/*
+Returns iterator over all indexes of the view and their values in order, same as `All()` of the treap.
+
+# Time complexity:
+  - Linear - time complexity is equal to length of the view plus height of the treap;
+*/
+func (w View) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		n := w.Len()
+		if n == 0 {
+			return
+		}
+		for it := w.t.Iterator(w.offset); it.Index() < w.offset+n; it.Next() {
+			if !yield(it.Index()-w.offset, it.Value()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubView(t *testing.T) {
+	tr := New(5, 1, 4, 2, 3, 9, 0)
+	w := tr.SubView(1, 4)
+	if w.Len() != 4 || !slices.Equal(w.Export(), []int{1, 4, 2, 3}) {
+		t.Fatalf("SubView(1, 4) = %v", w.Export())
+	}
+	if w.Sum() != 10 || w.Min() != 1 || w.Max() != 4 {
+		t.Errorf("view has sum %d, min %d and max %d", w.Sum(), w.Min(), w.Max())
+	}
+	if got, ok := w.Get(2); !ok || got != 2 {
+		t.Errorf("Get(2) = %d, %v, want 2", got, ok)
+	}
+	if _, ok := w.Get(4); ok {
+		t.Error("Get(4) of a view of 4 elements exists")
+	}
+	var indexes, values []int
+	for index, value := range w.All() {
+		indexes, values = append(indexes, index), append(values, value)
+	}
+	if !slices.Equal(indexes, []int{0, 1, 2, 3}) || !slices.Equal(values, []int{1, 4, 2, 3}) {
+		t.Errorf("All() yields %v and %v", indexes, values)
+	}
+	if inner := w.SubView(-1, 1); !slices.Equal(inner.Export(), []int{1, 4}) {
+		t.Errorf("SubView(-1, 1) of the view = %v", inner.Export())
+	}
+
+	// View keeps indexes, not elements.
+	tr.Insert(0, 7)
+	if got := w.Export(); !slices.Equal(got, []int{5, 1, 4, 2}) {
+		t.Errorf("view after insertion = %v", got)
+	}
+	tr.TakeBack(5)
+	if w.Len() != 2 || !slices.Equal(w.Export(), []int{5, 1}) {
+		t.Errorf("view after deletion = %v", w.Export())
+	}
+	tr.TakeBack(2)
+	if w.Len() != 0 || w.Export() != nil || w.Sum() != 0 || w.Min() != 0 || w.Max() != 0 {
+		t.Errorf("view beyond the end of the treap = %v", w.Export())
+	}
+	if empty := tr.SubView(3, 2); empty.Len() != 0 {
+		t.Errorf("SubView(3, 2) has length %d", empty.Len())
+	}
+	var zero View
+	if zero.Len() != 0 || zero.Export() != nil {
+		t.Error("zero view is not empty")
+	}
+}
@@ -0,0 +1,67 @@
+package treap
+
+/*
+In-order walker over the nodes of the treap.
+Keeps on its stack all ancestors whose values are not visited yet,
+so stepping is constant in amortized time and no export is needed.
+Pending changes are pushed into every node put on the stack.
+
+Treap must not be changed while the walker is used.
+*/
+type walker struct {
+	stack   []*node
+	reverse bool
+}
+
+/*
+Positions the walker, so the next visited node is the node on the given index.
+Walker visits nodes from the last to the first if reverse is true.
+Path is found by `descend()`, then only the ancestors, that are visited after the node, are kept.
+
+	if index out of range: walker visits nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (w *walker) seek(root *node, index int, reverse bool) {
+	w.stack = w.stack[:0]
+	w.reverse = reverse
+	if root == nil || index < 0 || index >= root.size {
+		return
+	}
+	_, path := descend(root, index, w.stack, true)
+	w.stack = path[:0]
+	for i, n := range path {
+		if i == len(path)-1 || (path[i+1] == n.lson) != reverse {
+			w.stack = append(w.stack, n)
+		}
+	}
+}
+
+/*
+Returns the next node of the walk.
+
+	if walk is finished: return nil
+
+# Time complexity:
+  - Constant - amortized, each node is pushed and popped only once;
+*/
+func (w *walker) next() *node {
+	if len(w.stack) == 0 {
+		return nil
+	}
+	n := w.stack[len(w.stack)-1]
+	w.stack = w.stack[:len(w.stack)-1]
+	if w.reverse {
+		for m := n.lson; m != nil; m = m.rson {
+			push(m)
+			w.stack = append(w.stack, m)
+		}
+	} else {
+		for m := n.rson; m != nil; m = m.lson {
+			push(m)
+			w.stack = append(w.stack, m)
+		}
+	}
+	return n
+}
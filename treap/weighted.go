@@ -0,0 +1,120 @@
+package treap
+
+/*
+Returns sum of all values of the subtree.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func sum(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.aug.sum
+}
+
+/*
+Overwrites the value on the given index and recalculates augmentations on the path to it.
+Index must be inside of the subtree.
+Path is found by `descend()` and kept on the stack, so nothing is allocated for treaps of usual height.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func set(n *node, index int, value int) {
+	var stack [64]*node
+	n, path := descend(n, index, stack[:0], true)
+	n.value = value
+	for i := len(path) - 1; i >= 0; i-- {
+		sync(path[i])
+	}
+}
+
+/*
+Returns the index of the element, whose weight range contains the point.
+Weight ranges of the elements follow each other from 0 in order of the elements.
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func sample(n *node, point int) int {
+	index := 0
+	for n != nil {
+		push(n)
+		lsum := sum(n.lson)
+		if point < lsum {
+			n = n.lson
+			continue
+		}
+		point -= lsum
+		index += size(n.lson)
+		if point < n.value {
+			return index
+		}
+		point -= n.value
+		index++
+		n = n.rson
+	}
+	// Reached only if some weights are negative.
+	return index - 1
+}
+
+/*
+Returns sum of all values of the treap.
+Sum of every subtree is stored in its root, so no traversal is made.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) TotalWeight() int {
+	if t == nil {
+		nilReceiver()
+		return 0
+	}
+	v := t.enter()
+	t.augment()
+	total := sum(t.root)
+	t.exit(v)
+	return total
+}
+
+/*
+Draws random index with probability proportional to the value of the element,
+so values are used as weights, and elements with weight 0 are never drawn.
+Weights can be updated by `SetWeight()` without rebuilding anything, unlike a static alias table.
+All values must be non-negative, otherwise the result is unspecified.
+
+	if treap is empty or total weight is not positive: return -1
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+  - Linear - on the 1st call, if the treap is not augmented, see `SetAugmented()`;
+*/
+func (t *Treap) SampleWeighted() int {
+	if t == nil {
+		nilReceiver()
+		return -1
+	}
+	v := t.enter()
+	t.augment()
+	index := -1
+	if total := sum(t.root); total > 0 {
+		index = sample(t.root, randomN(total))
+	}
+	t.exit(v)
+	return index
+}
+
+/*
+Changes weight (value) of the element on the given index, same as `Set()`.
+Positions of the elements are not changed, so annotations and marks are not affected.
+
+	if index out of range: do nothing
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func (t *Treap) SetWeight(index int, weight int) {
+	t.Set(index, weight)
+}
@@ -0,0 +1,41 @@
+package treap
+
+import "testing"
+
+func TestSampleWeighted(t *testing.T) {
+	tr := New(1, 0, 3)
+	if got := tr.TotalWeight(); got != 4 {
+		t.Fatalf("TotalWeight() = %d, want 4", got)
+	}
+	var counts [3]int
+	const draws = 20000
+	for range draws {
+		counts[tr.SampleWeighted()]++
+	}
+	if counts[1] != 0 {
+		t.Errorf("element with weight 0 was drawn %d times", counts[1])
+	}
+	if share := float64(counts[2]) / draws; share < 0.7 || share > 0.8 {
+		t.Errorf("element with weight 3 of 4 was drawn with frequency %.3f", share)
+	}
+	tr.SetWeight(0, 0)
+	tr.SetWeight(1, 2)
+	for range 100 {
+		if got := tr.SampleWeighted(); got == 0 {
+			t.Fatal("element with weight set to 0 was drawn")
+		}
+	}
+	if got := tr.TotalWeight(); got != 5 {
+		t.Errorf("TotalWeight() after SetWeight() = %d, want 5", got)
+	}
+}
+
+func TestSampleWeightedWithoutWeight(t *testing.T) {
+	empty, zero := New(), New(0, 0)
+	if got := empty.SampleWeighted(); got != -1 {
+		t.Errorf("SampleWeighted() of empty treap = %d, want -1", got)
+	}
+	if got := zero.SampleWeighted(); got != -1 {
+		t.Errorf("SampleWeighted() with zero total weight = %d, want -1", got)
+	}
+}
@@ -0,0 +1,119 @@
+package treap
+
+/*
+Group of treaps sharing one node arena, for example hundreds of documents of a single application.
+All treaps of the workspace take nodes from the same free list and return deleted nodes into it,
+so memory freed by one treap is reused by another, and memory of the whole group is controlled as a unit.
+
+Recycling is enabled by default. Configuration of the arena (recycling, accounting)
+is shared, so changing it by any treap of the workspace changes it for all of them.
+Results of `Merge()` and `Split()` keep sharing the arena, but are not tracked, see `Adopt()`.
+
+# Treaps of the workspace are unsafe to be used in parallel goroutines, even different ones.
+*/
+type Workspace struct {
+	arena  *pool
+	treaps []*Treap
+}
+
+/*
+Correctly initialize an empty workspace.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func NewWorkspace() *Workspace {
+	return &Workspace{arena: &pool{recycle: true, shared: true}}
+}
+
+/*
+Creates new treap of the workspace.
+Insert all given values to the back by calling `PushBack()` method.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of provided values;
+*/
+func (w *Workspace) New(values ...int) *Treap {
+	t := &Treap{}
+	w.Adopt(t)
+	t.PushBack(values...)
+	return t
+}
+
+/*
+Moves the treap into the workspace, so it uses the shared arena and is released by `ReleaseAll()`.
+Free list of the previous allocator of the treap is dropped.
+
+	if t == nil: do nothing
+
+# Time complexity:
+  - Amortized constant - requires single append;
+*/
+func (w *Workspace) Adopt(t *Treap) {
+	if t == nil {
+		return
+	}
+	t.opts.pool = w.arena
+	w.treaps = append(w.treaps, t)
+}
+
+/*
+Returns amount of treaps tracked by the workspace.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (w *Workspace) Len() int {
+	return len(w.treaps)
+}
+
+/*
+Returns memory usage report of the whole workspace, same as `Stats()` of the treap.
+Live nodes are counted for all tracked treaps.
+
+# Time complexity:
+  - Linear - time complexity is equal to amount of tracked treaps;
+*/
+func (w *Workspace) Stats() Stats {
+	s := Stats{NodesFree: w.arena.size}
+	for _, t := range w.treaps {
+		s.NodesLive += t.Size()
+	}
+	if st := w.arena.stats; st != nil {
+		s.NodesAllocated = st.allocatedNodes.Load()
+		s.NodesReused = st.reusedNodes.Load()
+		s.NodesFreed = st.freedNodes.Load()
+	}
+	s.ArenaBytes = int64(s.NodesFree) * nodeBytes
+	s.ScanBytes = int64(s.NodesLive+s.NodesFree) * nodeBytes
+	return s
+}
+
+/*
+Drops the free list of the arena, so its memory is reclaimed by the garbage collector.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func (w *Workspace) Trim() {
+	w.arena.free, w.arena.size = nil, 0
+}
+
+/*
+Destroys all tracked treaps, see `Destroy()`, and drops the free list of the arena.
+Treaps are no longer tracked, but stay empty and can be used afterwards.
+
+# Time complexity:
+  - Linear - time complexity is equal to total size of all tracked treaps;
+*/
+func (w *Workspace) ReleaseAll() {
+	recycle := w.arena.recycle
+	// Nodes are unlinked without filling the free list, that is dropped anyway.
+	w.arena.recycle = false
+	for _, t := range w.treaps {
+		t.Destroy()
+	}
+	w.arena.recycle = recycle
+	w.Trim()
+	w.treaps = nil
+}
@@ -0,0 +1,55 @@
+package treap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWorkspaceSharesArena(t *testing.T) {
+	w := NewWorkspace()
+	a, b := w.New(1, 2, 3, 4), w.New()
+	a.Cut(0, 2)
+	if got := w.Stats(); got.NodesFree != 3 || got.NodesLive != 1 {
+		t.Fatalf("Stats() after Cut() = %+v", got)
+	}
+	live := nodesOf(a.root)
+	b.PushBack(5, 6, 7)
+	if got := w.Stats().NodesFree; got != 0 {
+		t.Errorf("NodesFree = %d after reuse, want 0", got)
+	}
+	if got := b.Export(); !slices.Equal(got, []int{5, 6, 7}) {
+		t.Errorf("Export() = %v", got)
+	}
+	for _, n := range nodesOf(b.root) {
+		if slices.Contains(live, n) {
+			t.Error("live node of another treap is reused")
+		}
+	}
+}
+
+func TestWorkspaceReleaseAll(t *testing.T) {
+	w := NewWorkspace()
+	a := w.New(1, 2, 3)
+	adopted := New(4, 5)
+	w.Adopt(&adopted)
+	w.Adopt(nil)
+	if w.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", w.Len())
+	}
+	w.ReleaseAll()
+	if w.Len() != 0 || a.Size() != 0 || adopted.Size() != 0 {
+		t.Errorf("ReleaseAll() leaves %d treaps with sizes %d and %d", w.Len(), a.Size(), adopted.Size())
+	}
+	if got := w.Stats(); got.NodesFree != 0 || got.NodesLive != 0 {
+		t.Errorf("Stats() after ReleaseAll() = %+v", got)
+	}
+	a.PushBack(1)
+	a.Delete(0)
+	if got := w.Stats().NodesFree; got != 1 {
+		t.Errorf("NodesFree = %d, recycling is not restored", got)
+	}
+	w.Trim()
+	if got := w.Stats().NodesFree; got != 0 {
+		t.Errorf("NodesFree = %d after Trim()", got)
+	}
+}
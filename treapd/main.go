@@ -0,0 +1,202 @@
+/*
+Command treapd serves a single treap over HTTP with JSON bodies,
+so several clients can share one big sequence without embedding the package.
+
+Usage:
+
+	go run main/treapd -addr=:8080 -journal=seq.journal
+
+Endpoints:
+
+	POST /insert   {"index": 0, "values": [1, 2, 3]}  insert values before the index
+	POST /delete   {"index": 0, "count": 2}           delete count elements from the index
+	GET  /find?index=0                                 {"value": 1}
+	GET  /range?left=0&right=9                         {"values": [...]}
+	GET  /size                                         {"size": 10}
+	GET  /export                                       {"values": [...]}
+
+Indexes have exact meaning as in `Op`, invalid requests are answered with 400 status.
+If journal is provided, treap is recovered from it on start,
+journal is compacted into a new file and every change is written into it before being applied.
+*/
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"main/treap"
+)
+
+var (
+	addr        = flag.String("addr", ":8080", "address to listen on")
+	journalPath = flag.String("journal", "", "journal file, changes are not persisted if empty")
+)
+
+// Treap shared by all requests.
+type server struct {
+	mu sync.Mutex
+	t  treap.Treap
+}
+
+// Body of the insert and delete requests.
+type edit struct {
+	Index  int   `json:"index"`
+	Values []int `json:"values,omitempty"`
+	Count  int   `json:"count,omitempty"`
+}
+
+func main() {
+	flag.Parse()
+	s := &server{t: treap.New()}
+	if *journalPath != "" {
+		journal, err := s.recover(*journalPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer journal.Close()
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /insert", s.edit(treap.OpInsert))
+	mux.HandleFunc("POST /delete", s.edit(treap.OpDelete))
+	mux.HandleFunc("GET /find", s.find)
+	mux.HandleFunc("GET /range", s.rangeValues)
+	mux.HandleFunc("GET /size", s.size)
+	mux.HandleFunc("GET /export", s.export)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// Recovers the treap from the journal and starts compacted journal in its place.
+func (s *server) recover(path string) (*os.File, error) {
+	if file, err := os.Open(path); err == nil {
+		s.t, err = treap.Recover(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	tmp := path + ".tmp"
+	journal, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	// Journal starts with the snapshot of the recovered treap.
+	s.t.SetJournal(syncedFile{journal})
+	if err := s.t.JournalErr(); err != nil {
+		journal.Close()
+		return nil, err
+	} else if err := os.Rename(tmp, path); err != nil {
+		journal.Close()
+		return nil, err
+	}
+	return journal, nil
+}
+
+// File synced after every write, so every applied change survives a crash.
+type syncedFile struct {
+	*os.File
+}
+
+func (f syncedFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.File.Sync()
+}
+
+// Returns handler applying insert or delete operation.
+func (s *server) edit(kind treap.OpKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var e edit
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		op := treap.DeleteOp(e.Index, e.Count)
+		if kind == treap.OpInsert {
+			op = treap.InsertOp(e.Index, e.Values...)
+		}
+		s.mu.Lock()
+		err := s.t.Apply(op)
+		size := s.t.Size()
+		s.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reply(w, map[string]int{"size": size})
+	}
+}
+
+func (s *server) find(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(r.URL.Query().Get("index"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	value, err := s.t.TryFind(index)
+	s.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply(w, map[string]int{"value": value})
+}
+
+func (s *server) rangeValues(w http.ResponseWriter, r *http.Request) {
+	left, err1 := strconv.Atoi(r.URL.Query().Get("left"))
+	right, err2 := strconv.Atoi(r.URL.Query().Get("right"))
+	if err := errors.Join(err1, err2); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	size := s.t.Size()
+	var values []int
+	if left >= 0 && left <= right && right < size {
+		values = make([]int, 0, right-left+1)
+		for i := left; i <= right; i++ {
+			values = append(values, s.t.Find(i))
+		}
+	}
+	s.mu.Unlock()
+	if values == nil {
+		http.Error(w, fmt.Sprintf("range [%d, %d] with size %d", left, right, size), http.StatusBadRequest)
+		return
+	}
+	reply(w, map[string][]int{"values": values})
+}
+
+func (s *server) size(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	size := s.t.Size()
+	s.mu.Unlock()
+	reply(w, map[string]int{"size": size})
+}
+
+func (s *server) export(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	values := s.t.Export()
+	s.mu.Unlock()
+	if values == nil {
+		values = []int{}
+	}
+	reply(w, map[string][]int{"values": values})
+}
+
+// Writes JSON reply.
+func reply(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"main/treap"
+)
+
+// Serves the request by the handler and returns status and decoded JSON reply.
+func serve(t *testing.T, handler http.HandlerFunc, method string, target string, body string) (int, map[string]json.RawMessage) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	var reply map[string]json.RawMessage
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+			t.Fatalf("%s %s replied %q: %v", method, target, w.Body, err)
+		}
+	}
+	return w.Code, reply
+}
+
+func TestHandlers(t *testing.T) {
+	s := &server{t: treap.New()}
+	for _, tc := range []struct {
+		handler http.HandlerFunc
+		method  string
+		target  string
+		body    string
+		status  int
+		reply   string
+	}{
+		{s.edit(treap.OpInsert), "POST", "/insert", `{"index": 0, "values": [1, 2, 3, 4]}`, 200, `{"size":4}`},
+		{s.edit(treap.OpInsert), "POST", "/insert", `{"index": 9, "values": [5]}`, 400, ""},
+		{s.edit(treap.OpDelete), "POST", "/delete", `{"index": 1, "count": 2}`, 200, `{"size":2}`},
+		{s.edit(treap.OpDelete), "POST", "/delete", `{"index":`, 400, ""},
+		{s.find, "GET", "/find?index=1", "", 200, `{"value":4}`},
+		{s.find, "GET", "/find?index=2", "", 400, ""},
+		{s.find, "GET", "/find?index=x", "", 400, ""},
+		{s.rangeValues, "GET", "/range?left=0&right=1", "", 200, `{"values":[1,4]}`},
+		{s.rangeValues, "GET", "/range?left=1&right=0", "", 400, ""},
+		{s.rangeValues, "GET", "/range?left=0", "", 400, ""},
+		{s.size, "GET", "/size", "", 200, `{"size":2}`},
+		{s.export, "GET", "/export", "", 200, `{"values":[1,4]}`},
+	} {
+		status, reply := serve(t, tc.handler, tc.method, tc.target, tc.body)
+		if status != tc.status {
+			t.Fatalf("%s %s %s = status %d, want %d", tc.method, tc.target, tc.body, status, tc.status)
+		} else if got, _ := json.Marshal(reply); status == http.StatusOK && string(got) != tc.reply {
+			t.Fatalf("%s %s %s = %s, want %s", tc.method, tc.target, tc.body, got, tc.reply)
+		}
+	}
+	empty := &server{t: treap.New()}
+	if _, reply := serve(t, empty.export, "GET", "/export", ""); string(reply["values"]) != "[]" {
+		t.Errorf("export of empty treap = %s, want []", reply["values"])
+	}
+}
+
+func TestRecover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seq.journal")
+	s := &server{t: treap.New()}
+	journal, err := s.recover(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serve(t, s.edit(treap.OpInsert), "POST", "/insert", `{"index": 0, "values": [1, 2, 3]}`)
+	serve(t, s.edit(treap.OpDelete), "POST", "/delete", `{"index": 0, "count": 1}`)
+	journal.Close()
+
+	restarted := &server{t: treap.New()}
+	journal, err = restarted.recover(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	if got := restarted.t.Export(); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("recovered treap = %v, want [2 3]", got)
+	}
+	if matches, _ := filepath.Glob(path + ".tmp"); matches != nil {
+		t.Errorf("temporary journal %v is left", matches)
+	}
+}
+
+func TestRecoverCorruptedJournal(t *testing.T) {
+	tr := treap.New(1, 2, 3)
+	var buf bytes.Buffer
+	tr.SetJournal(&buf)
+	valid := buf.Len()
+	tr.Insert(0, 4)
+	tr.Insert(0, 5)
+	journal := slices.Clone(buf.Bytes())
+	// Length of the 1st insertion is corrupted, so its checksum does not match.
+	binary.LittleEndian.PutUint64(journal[valid+9:], 1<<45)
+	path := filepath.Join(t.TempDir(), "seq.journal")
+	if err := os.WriteFile(path, journal, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := &server{t: treap.New()}
+	if _, err := s.recover(path); !errors.Is(err, treap.ErrBadJournal) {
+		t.Errorf("recover() of a corrupted journal = %v, want ErrBadJournal", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, journal) {
+		t.Error("corrupted journal is replaced")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Package treaputil provides top-level helpers over the treap, mirroring common helpers of slices,
+so code working with slices can move to the treap by swapping imports instead of rewriting call sites.
+Every helper is a thin wrapper of a method of the treap and has the same time complexity.
+
+# Package is unsafe to be used in parallel goroutines.
+*/
+package treaputil
+
+import "main/treap"
+
+/*
+Returns sum of all elements of the treap.
+
+	if treap is empty: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func Sum(t *treap.Treap) int {
+	if t.Size() == 0 {
+		return 0
+	}
+	return t.RangeSum(0, t.Size()-1)
+}
+
+/*
+Returns the smallest element of the treap, same as slices.Min, but does not panic.
+
+	if treap is empty: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func Min(t *treap.Treap) int {
+	if t.Size() == 0 {
+		return 0
+	}
+	return t.RangeMin(0, t.Size()-1)
+}
+
+/*
+Returns the biggest element of the treap, same as slices.Max, but does not panic.
+
+	if treap is empty: return 0
+
+# Time complexity:
+  - Logarithmic - time complexity is equal to height of the treap;
+*/
+func Max(t *treap.Treap) int {
+	if t.Size() == 0 {
+		return 0
+	}
+	return t.RangeMax(0, t.Size()-1)
+}
+
+/*
+Reverses order of the elements of the treap in place, same as slices.Reverse.
+
+# Time complexity:
+  - Constant - requires constant amount of operations;
+*/
+func Reverse(t *treap.Treap) {
+	t.Reverse()
+}
+
+/*
+Returns new treap with the same elements, same as slices.Clone.
+New treap has default configuration.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func Clone(t *treap.Treap) treap.Treap {
+	return treap.New(t.Export()...)
+}
+
+/*
+Reports whether both treaps have the same elements in the same order, same as slices.Equal.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treaps;
+*/
+func Equal(a *treap.Treap, b *treap.Treap) bool {
+	return treap.Equal(a, b)
+}
+
+/*
+Returns index of the first element equal to the value, same as slices.Index.
+
+	if treap does not contain the value: return -1
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func Index(t *treap.Treap, value int) int {
+	return t.IndexOf(value)
+}
+
+/*
+Reports whether the treap contains the value, same as slices.Contains.
+
+# Time complexity:
+  - Linear - time complexity is equal to size of the treap;
+*/
+func Contains(t *treap.Treap, value int) bool {
+	return t.Contains(value)
+}
@@ -0,0 +1,47 @@
+package treaputil
+
+import (
+	"slices"
+	"testing"
+
+	"main/treap"
+)
+
+func TestHelpersMirrorSlices(t *testing.T) {
+	values := []int{3, -1, 4, 1, -5, 9, 2, 6}
+	tr := treap.New(values...)
+	if got, want := Sum(&tr), 19; got != want {
+		t.Errorf("Sum() = %d, want %d", got, want)
+	}
+	if Min(&tr) != slices.Min(values) || Max(&tr) != slices.Max(values) {
+		t.Errorf("Min() = %d, Max() = %d", Min(&tr), Max(&tr))
+	}
+	for _, value := range []int{4, 7, -5} {
+		if Index(&tr, value) != slices.Index(values, value) || Contains(&tr, value) != slices.Contains(values, value) {
+			t.Errorf("Index(%d) = %d, Contains(%d) = %v", value, Index(&tr, value), value, Contains(&tr, value))
+		}
+	}
+	clone := Clone(&tr)
+	Reverse(&tr)
+	slices.Reverse(values)
+	if got := tr.Export(); !slices.Equal(got, values) {
+		t.Errorf("Reverse() = %v", got)
+	}
+	if Equal(&tr, &clone) {
+		t.Error("clone changed together with the reversed treap")
+	}
+	Reverse(&clone)
+	if !Equal(&tr, &clone) {
+		t.Error("reversed clone is not equal to the reversed treap")
+	}
+}
+
+func TestHelpersOfEmptyTreap(t *testing.T) {
+	empty := treap.New()
+	if Sum(&empty) != 0 || Min(&empty) != 0 || Max(&empty) != 0 || Index(&empty, 0) != -1 || Contains(&empty, 0) {
+		t.Error("helpers of an empty treap return non-zero results")
+	}
+	if clone := Clone(&empty); clone.Size() != 0 {
+		t.Errorf("Clone() of an empty treap has size %d", clone.Size())
+	}
+}